@@ -6,10 +6,12 @@ import (
 	stdlog "log"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/apex/log"
 
+	"github.com/axiomhq/axiom-go/adapters/common"
 	"github.com/axiomhq/axiom-go/axiom"
 	"github.com/axiomhq/axiom-go/axiom/ingest"
 )
@@ -61,6 +63,16 @@ func SetIngestOptions(opts ...ingest.Option) Option {
 	}
 }
 
+// SetCommonOptions specifies the options shared by all adapters. The message
+// and level are stored in the "message" and "severity" fields by default and
+// the handler blocks when its buffer is full.
+func SetCommonOptions(options ...common.Option) Option {
+	return func(h *Handler) error {
+		h.commonOptions = options
+		return nil
+	}
+}
+
 // Handler implements a [log.Handler] used for shipping logs to Axiom.
 type Handler struct {
 	client      *axiom.Client
@@ -68,8 +80,18 @@ type Handler struct {
 
 	clientOptions []axiom.Option
 	ingestOptions []ingest.Option
+	commonOptions []common.Option
+
+	config common.Config
+
+	// sent is the amount of events passed on for ingestion, processed the
+	// amount of events the server has acknowledged.
+	sent      atomic.Uint64
+	processed uint64
+	dropped   atomic.Uint64
 
 	eventCh   chan axiom.Event
+	cancel    context.CancelFunc
 	closeCh   chan struct{}
 	closeOnce sync.Once
 }
@@ -120,13 +142,27 @@ func New(options ...Option) (*Handler, error) {
 		}
 	}
 
+	handler.config = common.Config{
+		MessageField: "message",
+		LevelField:   "severity",
+		TimeField:    ingest.TimestampField,
+		BlockOnFull:  true,
+	}.Apply(handler.commonOptions...)
+	ingestOptions := handler.config.IngestOptions(handler.ingestOptions...)
+
 	// Run background ingest.
+	var ctx context.Context
+	ctx, handler.cancel = context.WithCancel(context.Background())
 	go func() {
 		defer close(handler.closeCh)
+		defer handler.cancel()
 
 		logger := stdlog.New(os.Stderr, "[AXIOM|APEX]", 0)
 
-		res, err := handler.client.IngestChannel(context.Background(), handler.datasetName, handler.eventCh, handler.ingestOptions...)
+		res, err := handler.client.IngestChannel(ctx, handler.datasetName, handler.eventCh, ingestOptions...)
+		if res != nil {
+			handler.processed = res.Ingested + res.Failed
+		}
 		if err != nil {
 			logger.Printf("failed to ingest events: %s\n", err)
 		} else if res.Failed > 0 {
@@ -142,10 +178,33 @@ func New(options ...Option) (*Handler, error) {
 // Close the handler and make sure all events are flushed. Closing the handler
 // renders it unusable for further use.
 func (h *Handler) Close() {
+	_ = h.Shutdown(context.Background())
+}
+
+// Shutdown closes the handler like [Handler.Close] but stops waiting for the
+// events to be flushed when the context is done. In that case, ingestion is
+// aborted and a [*common.DrainError] carrying the amount of dropped events is
+// returned.
+func (h *Handler) Shutdown(ctx context.Context) error {
 	h.closeOnce.Do(func() {
 		close(h.eventCh)
-		<-h.closeCh
 	})
+
+	if err := common.Drain(ctx, h.closeCh, h.cancel); err != nil {
+		return &common.DrainError{
+			Dropped: h.sent.Load() - h.processed,
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// DroppedCount returns the amount of events dropped because the buffer was
+// full. Events are only dropped if blocking is disabled by passing
+// [common.WithBlockOnFull] to the [SetCommonOptions] option.
+func (h *Handler) DroppedCount() uint64 {
+	return h.dropped.Load()
 }
 
 // HandleLog implements [log.Handler].
@@ -158,15 +217,29 @@ func (h *Handler) HandleLog(entry *log.Entry) error {
 	}
 
 	// Set timestamp, severity and actual message.
-	event[ingest.TimestampField] = entry.Timestamp.Format(time.RFC3339Nano)
-	event["severity"] = entry.Level.String()
-	event["message"] = entry.Message
+	event[h.config.TimeField] = entry.Timestamp.Format(time.RFC3339Nano)
+	event[h.config.LevelField] = entry.Level.String()
+	event[h.config.MessageField] = entry.Message
 
 	select {
 	case <-h.closeCh:
 		return errors.New("handler closed")
 	default:
+	}
+
+	if h.config.BlockOnFull {
+		h.sent.Add(1)
 		h.eventCh <- event
 		return nil
 	}
+
+	select {
+	case h.eventCh <- event:
+		h.sent.Add(1)
+	default:
+		h.dropped.Add(1)
+		h.config.Drop(1)
+	}
+
+	return nil
 }
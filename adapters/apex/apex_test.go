@@ -2,6 +2,7 @@ package apex
 
 import (
 	"bufio"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -14,6 +15,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/axiomhq/axiom-go/adapters/common"
 	"github.com/axiomhq/axiom-go/axiom"
 	"github.com/axiomhq/axiom-go/axiom/ingest"
 	"github.com/axiomhq/axiom-go/internal/test/adapters"
@@ -149,15 +151,157 @@ func TestHandler_NoPanicAfterClose(t *testing.T) {
 	assert.EqualValues(t, 1, atomic.LoadUint64(&lines))
 }
 
-func setup(t *testing.T) func(dataset string, client *axiom.Client) (*log.Logger, func()) {
-	return func(dataset string, client *axiom.Client) (*log.Logger, func()) {
+func TestHandler_CommonOptions(t *testing.T) {
+	exp := fmt.Sprintf(`{"ts":"%s","lvl":"info","key":"value","msg":"my message"}`,
+		time.Now().Format(time.RFC3339Nano))
+
+	var hasRun uint64
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "ts", r.URL.Query().Get("timestamp-field"))
+
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		b, err := io.ReadAll(zsr)
+		require.NoError(t, err)
+
+		testhelper.JSONEqExp(t, exp, string(b), []string{"ts"})
+
+		atomic.AddUint64(&hasRun, 1)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	logger, closeHandler := adapters.Setup(t, hf, setup(t, SetCommonOptions(
+		common.WithMessageField("msg"),
+		common.WithLevelField("lvl"),
+		common.WithTimeField("ts"),
+	)))
+
+	logger.
+		WithField("key", "value").
+		Info("my message")
+
+	closeHandler()
+
+	assert.EqualValues(t, 1, atomic.LoadUint64(&hasRun))
+}
+
+func TestHandler_Shutdown(t *testing.T) {
+	releaseCh := make(chan struct{})
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		// Don't acknowledge the ingestion until the test is done.
+		select {
+		case <-r.Context().Done():
+		case <-releaseCh:
+		}
+	}
+
+	var handler *Handler
+	logger, _ := adapters.Setup(t, hf, func(dataset string, client *axiom.Client) (*log.Logger, func()) {
 		t.Helper()
 
-		handler, err := New(
+		var err error
+		handler, err = New(
 			SetClient(client),
 			SetDataset(dataset),
 		)
 		require.NoError(t, err)
+
+		logger := &log.Logger{
+			Handler: handler,
+			Level:   log.InfoLevel,
+		}
+
+		return logger, handler.Close
+	})
+	t.Cleanup(func() { close(releaseCh) })
+
+	for i := 0; i < 3; i++ {
+		logger.Info("my message")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+
+	err := handler.Shutdown(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	var drainErr *common.DrainError
+	require.ErrorAs(t, err, &drainErr)
+	assert.EqualValues(t, 3, drainErr.Dropped)
+}
+
+func TestHandler_BlockOnFull(t *testing.T) {
+	var lines uint64
+	releaseCh := make(chan struct{})
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		// Block ingestion until all events are logged.
+		<-releaseCh
+
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		s := bufio.NewScanner(zsr)
+		for s.Scan() {
+			atomic.AddUint64(&lines, 1)
+		}
+		assert.NoError(t, s.Err())
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	var onDrop atomic.Int64
+	var handler *Handler
+	logger, closeHandler := adapters.Setup(t, hf, func(dataset string, client *axiom.Client) (*log.Logger, func()) {
+		t.Helper()
+
+		var err error
+		handler, err = New(
+			SetClient(client),
+			SetDataset(dataset),
+			SetCommonOptions(
+				common.WithBlockOnFull(false),
+				common.WithOnDrop(func(n int) { onDrop.Add(int64(n)) }),
+			),
+		)
+		require.NoError(t, err)
+		t.Cleanup(handler.Close)
+
+		logger := &log.Logger{
+			Handler: handler,
+			Level:   log.InfoLevel,
+		}
+
+		return logger, handler.Close
+	})
+
+	// At most one batch is in flight and one is buffered, all other events
+	// must be dropped without blocking.
+	for i := 0; i < 3000; i++ {
+		logger.Info("my message")
+	}
+
+	assert.GreaterOrEqual(t, handler.DroppedCount(), uint64(1000))
+	assert.EqualValues(t, handler.DroppedCount(), onDrop.Load())
+
+	close(releaseCh)
+	closeHandler()
+
+	assert.EqualValues(t, 3000-handler.DroppedCount(), atomic.LoadUint64(&lines))
+}
+
+func setup(t *testing.T, options ...Option) func(dataset string, client *axiom.Client) (*log.Logger, func()) {
+	return func(dataset string, client *axiom.Client) (*log.Logger, func()) {
+		t.Helper()
+
+		handler, err := New(append([]Option{
+			SetClient(client),
+			SetDataset(dataset),
+		}, options...)...)
+		require.NoError(t, err)
 		t.Cleanup(handler.Close)
 
 		logger := &log.Logger{
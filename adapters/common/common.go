@@ -0,0 +1,146 @@
+package common
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/axiomhq/axiom-go/axiom/ingest"
+)
+
+// Config is the configuration shared by all adapters. Every adapter has its own
+// defaults which are modified by passing [Option]s to the adapter.
+type Config struct {
+	// MessageField is the name of the field the log message is stored in.
+	MessageField string
+	// LevelField is the name of the field the log level is stored in.
+	LevelField string
+	// TimeField is the name of the field the timestamp is stored in. If it
+	// differs from [ingest.TimestampField], Axiom is instructed to extract the
+	// timestamp from this field.
+	TimeField string
+	// BlockOnFull specifies if logging blocks when the buffer of an adapter is
+	// full because events are logged faster than they can be ingested. If
+	// false, events are dropped instead.
+	BlockOnFull bool
+	// OnDrop is called with the amount of events dropped because the buffer of
+	// an adapter was full. It is called synchronously by the logging call, so
+	// it must not block or log to the dropping logger.
+	OnDrop func(n int)
+}
+
+// An Option modifies the [Config] of an adapter.
+type Option func(*Config)
+
+// WithMessageField specifies the name of the field the log message is stored
+// in.
+func WithMessageField(name string) Option {
+	return func(c *Config) { c.MessageField = name }
+}
+
+// WithLevelField specifies the name of the field the log level is stored in.
+func WithLevelField(name string) Option {
+	return func(c *Config) { c.LevelField = name }
+}
+
+// WithTimeField specifies the name of the field the timestamp is stored in.
+func WithTimeField(name string) Option {
+	return func(c *Config) { c.TimeField = name }
+}
+
+// WithBlockOnFull specifies if logging blocks when the buffer of an adapter is
+// full. If false, events are dropped instead. Adapters block by default.
+func WithBlockOnFull(block bool) Option {
+	return func(c *Config) { c.BlockOnFull = block }
+}
+
+// WithOnDrop specifies a function that is called with the amount of events
+// dropped because the buffer of an adapter was full. It must not block or log
+// to the dropping logger.
+func WithOnDrop(fn func(n int)) Option {
+	return func(c *Config) { c.OnDrop = fn }
+}
+
+// Drop records the given amount of dropped events by calling the configured
+// [Config.OnDrop] function, if any.
+func (c Config) Drop(n int) {
+	if c.OnDrop != nil {
+		c.OnDrop(n)
+	}
+}
+
+// Apply returns a copy of the config with the given options applied. Options
+// that set an empty field name are ignored.
+func (c Config) Apply(options ...Option) Config {
+	res := c
+	for _, option := range options {
+		if option != nil {
+			option(&res)
+		}
+	}
+
+	if res.MessageField == "" {
+		res.MessageField = c.MessageField
+	}
+	if res.LevelField == "" {
+		res.LevelField = c.LevelField
+	}
+	if res.TimeField == "" {
+		res.TimeField = c.TimeField
+	}
+
+	return res
+}
+
+// IngestOptions returns the given ingest options prefixed with the options
+// needed for Axiom to pick up the configured time field. Options passed in
+// take precedence.
+func (c Config) IngestOptions(options ...ingest.Option) []ingest.Option {
+	if c.TimeField == "" || c.TimeField == ingest.TimestampField {
+		return options
+	}
+	return append([]ingest.Option{ingest.SetTimestampField(c.TimeField)}, options...)
+}
+
+// DrainError is returned when an adapter is shut down before all of its
+// buffered events were ingested.
+type DrainError struct {
+	// Dropped is the amount of events that were not ingested.
+	Dropped uint64
+	// Err is the reason for aborting the drain, usually the error of the
+	// context passed to the adapters shutdown method.
+	Err error
+}
+
+// Error implements error.
+func (e *DrainError) Error() string {
+	return fmt.Sprintf("%d events dropped: %s", e.Dropped, e.Err)
+}
+
+// Unwrap returns the underlying error.
+func (e *DrainError) Unwrap() error {
+	return e.Err
+}
+
+// Drain waits for the done channel to be closed, which signals that an adapter
+// has flushed all of its buffered events. If the context is done before, abort
+// is called to stop ingestion and the cause of the context is returned once the
+// done channel is closed.
+func Drain(ctx context.Context, done <-chan struct{}, abort func()) error {
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+	}
+
+	// Draining might have finished in the meantime.
+	select {
+	case <-done:
+		return nil
+	default:
+	}
+
+	abort()
+	<-done
+
+	return context.Cause(ctx)
+}
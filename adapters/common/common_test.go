@@ -0,0 +1,98 @@
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/axiomhq/axiom-go/axiom/ingest"
+)
+
+func TestConfig_Apply(t *testing.T) {
+	defaults := Config{
+		MessageField: "message",
+		LevelField:   "severity",
+		TimeField:    ingest.TimestampField,
+	}
+
+	assert.Equal(t, defaults, defaults.Apply())
+	assert.Equal(t, defaults, defaults.Apply(nil, WithMessageField("")))
+
+	act := defaults.Apply(
+		WithMessageField("msg"),
+		WithLevelField("lvl"),
+		WithTimeField("ts"),
+	)
+	assert.Equal(t, Config{
+		MessageField: "msg",
+		LevelField:   "lvl",
+		TimeField:    "ts",
+	}, act)
+
+	// The defaults must not be modified.
+	assert.Equal(t, "message", defaults.MessageField)
+}
+
+func TestConfig_Drop(t *testing.T) {
+	// Dropping without a callback must not panic.
+	Config{}.Drop(1)
+
+	var dropped int
+	config := Config{BlockOnFull: true}.Apply(
+		WithBlockOnFull(false),
+		WithOnDrop(func(n int) { dropped += n }),
+	)
+	assert.False(t, config.BlockOnFull)
+
+	config.Drop(1)
+	config.Drop(2)
+	assert.Equal(t, 3, dropped)
+}
+
+func TestConfig_IngestOptions(t *testing.T) {
+	apply := func(options []ingest.Option) ingest.Options {
+		var opts ingest.Options
+		for _, option := range options {
+			option(&opts)
+		}
+		return opts
+	}
+
+	// The default time field doesn't need any options.
+	assert.Empty(t, Config{TimeField: ingest.TimestampField}.IngestOptions())
+	assert.Empty(t, Config{}.IngestOptions())
+
+	opts := apply(Config{TimeField: "ts"}.IngestOptions())
+	assert.Equal(t, "ts", opts.TimestampField)
+
+	// Options passed in take precedence.
+	opts = apply(Config{TimeField: "ts"}.IngestOptions(ingest.SetTimestampField("time")))
+	assert.Equal(t, "time", opts.TimestampField)
+}
+
+func TestDrain(t *testing.T) {
+	done := make(chan struct{})
+	close(done)
+
+	err := Drain(context.Background(), done, func() { t.Error("drain must not be aborted") })
+	assert.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	done = make(chan struct{})
+	err = Drain(ctx, done, func() { close(done) })
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestDrainError(t *testing.T) {
+	var err error = &DrainError{
+		Dropped: 3,
+		Err:     context.DeadlineExceeded,
+	}
+
+	assert.EqualError(t, err, "3 events dropped: context deadline exceeded")
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
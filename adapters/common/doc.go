@@ -0,0 +1,2 @@
+// Package common provides configuration shared by all adapters.
+package common
@@ -6,10 +6,12 @@ import (
 	"log"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/sirupsen/logrus"
 
+	"github.com/axiomhq/axiom-go/adapters/common"
 	"github.com/axiomhq/axiom-go/axiom"
 	"github.com/axiomhq/axiom-go/axiom/ingest"
 )
@@ -70,6 +72,25 @@ func SetLevels(levels ...logrus.Level) Option {
 	}
 }
 
+// SetFlushInterval specifies the interval at which the collected logs are
+// ingested, if the batch isn't full before. Defaults to one second.
+func SetFlushInterval(interval time.Duration) Option {
+	return func(h *Hook) error {
+		h.flushInterval = interval
+		return nil
+	}
+}
+
+// SetCommonOptions specifies the options shared by all adapters. The message
+// and level are stored in the "message" and "severity" fields by default and
+// the hook blocks when its buffer is full.
+func SetCommonOptions(options ...common.Option) Option {
+	return func(h *Hook) error {
+		h.commonOptions = options
+		return nil
+	}
+}
+
 // Hook implements a [logrus.Hook] used for shipping logs to Axiom.
 type Hook struct {
 	client      *axiom.Client
@@ -77,9 +98,20 @@ type Hook struct {
 
 	clientOptions []axiom.Option
 	ingestOptions []ingest.Option
+	commonOptions []common.Option
 	levels        []logrus.Level
+	flushInterval time.Duration
+
+	config common.Config
+
+	// sent is the amount of events passed on for ingestion, processed the
+	// amount of events the server has acknowledged.
+	sent      atomic.Uint64
+	processed uint64
+	dropped   atomic.Uint64
 
 	eventCh   chan axiom.Event
+	cancel    context.CancelFunc
 	closeCh   chan struct{}
 	closeOnce sync.Once
 }
@@ -132,13 +164,31 @@ func New(options ...Option) (*Hook, error) {
 		}
 	}
 
+	hook.config = common.Config{
+		MessageField: "message",
+		LevelField:   "severity",
+		TimeField:    ingest.TimestampField,
+		BlockOnFull:  true,
+	}.Apply(hook.commonOptions...)
+
+	ingestOptions := hook.config.IngestOptions(hook.ingestOptions...)
+	if hook.flushInterval > 0 {
+		ingestOptions = append([]ingest.Option{ingest.SetFlushInterval(hook.flushInterval)}, ingestOptions...)
+	}
+
 	// Run background ingest.
+	var ctx context.Context
+	ctx, hook.cancel = context.WithCancel(context.Background())
 	go func() {
 		defer close(hook.closeCh)
+		defer hook.cancel()
 
 		logger := log.New(os.Stderr, "[AXIOM|LOGRUS]", 0)
 
-		res, err := hook.client.IngestChannel(context.Background(), hook.datasetName, hook.eventCh, hook.ingestOptions...)
+		res, err := hook.client.IngestChannel(ctx, hook.datasetName, hook.eventCh, ingestOptions...)
+		if res != nil {
+			hook.processed = res.Ingested + res.Failed
+		}
 		if err != nil {
 			logger.Printf("failed to ingest events: %s\n", err)
 		} else if res.Failed > 0 {
@@ -155,10 +205,33 @@ func New(options ...Option) (*Hook, error) {
 // registered with [logrus.RegisterExitHandler]. Closing the hook renders it
 // unusable for further use.
 func (h *Hook) Close() {
+	_ = h.Shutdown(context.Background())
+}
+
+// Shutdown closes the hook like [Hook.Close] but stops waiting for the
+// events to be flushed when the context is done. In that case, ingestion is
+// aborted and a [*common.DrainError] carrying the amount of dropped events is
+// returned.
+func (h *Hook) Shutdown(ctx context.Context) error {
 	h.closeOnce.Do(func() {
 		close(h.eventCh)
-		<-h.closeCh
 	})
+
+	if err := common.Drain(ctx, h.closeCh, h.cancel); err != nil {
+		return &common.DrainError{
+			Dropped: h.sent.Load() - h.processed,
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// DroppedCount returns the amount of events dropped because the buffer was
+// full. Events are only dropped if blocking is disabled by passing
+// [common.WithBlockOnFull] to the [SetCommonOptions] option.
+func (h *Hook) DroppedCount() uint64 {
+	return h.dropped.Load()
 }
 
 // Levels implements [logrus.Hook].
@@ -170,21 +243,40 @@ func (h *Hook) Levels() []logrus.Level {
 func (h *Hook) Fire(entry *logrus.Entry) error {
 	event := axiom.Event{}
 
-	// Set fields first.
+	// Set fields first. Errors don't marshal to JSON in a meaningful way, so
+	// they are converted to their message, just like the logrus
+	// [logrus.JSONFormatter] does.
 	for k, v := range entry.Data {
+		if err, ok := v.(error); ok {
+			v = err.Error()
+		}
 		event[k] = v
 	}
 
 	// Set timestamp, severity and actual message.
-	event[ingest.TimestampField] = entry.Time.Format(time.RFC3339Nano)
-	event["severity"] = entry.Level.String()
-	event["message"] = entry.Message
+	event[h.config.TimeField] = entry.Time.Format(time.RFC3339Nano)
+	event[h.config.LevelField] = entry.Level.String()
+	event[h.config.MessageField] = entry.Message
 
 	select {
 	case <-h.closeCh:
 		return errors.New("handler closed")
 	default:
+	}
+
+	if h.config.BlockOnFull {
+		h.sent.Add(1)
 		h.eventCh <- event
 		return nil
 	}
+
+	select {
+	case h.eventCh <- event:
+		h.sent.Add(1)
+	default:
+		h.dropped.Add(1)
+		h.config.Drop(1)
+	}
+
+	return nil
 }
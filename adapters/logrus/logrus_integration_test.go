@@ -4,7 +4,9 @@ package logrus_test
 
 import (
 	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"github.com/stretchr/testify/require"
@@ -19,6 +21,7 @@ func Test(t *testing.T) {
 		hook, err := adapter.New(
 			adapter.SetClient(client),
 			adapter.SetDataset(dataset),
+			adapter.SetFlushInterval(time.Millisecond*100),
 		)
 		require.NoError(t, err)
 
@@ -29,6 +32,6 @@ func Test(t *testing.T) {
 
 		logger.WithField("mood", "hyped").Info("This is awesome!")
 		logger.WithField("mood", "worried").Warn("This is no that awesome...")
-		logger.WithField("mood", "depressed").Error("This is rather bad.")
+		logger.WithField("mood", "depressed").WithError(errors.New("bad")).Error("This is rather bad.")
 	})
 }
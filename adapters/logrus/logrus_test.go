@@ -2,6 +2,8 @@ package logrus
 
 import (
 	"bufio"
+	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -14,6 +16,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/axiomhq/axiom-go/adapters/common"
 	"github.com/axiomhq/axiom-go/axiom"
 	"github.com/axiomhq/axiom-go/internal/test/adapters"
 	"github.com/axiomhq/axiom-go/internal/test/testhelper"
@@ -158,17 +161,220 @@ func TestHook_NoPanicAfterClose(t *testing.T) {
 	assert.EqualValues(t, 1, atomic.LoadUint64(&lines))
 }
 
-func setup(t *testing.T) func(dataset string, client *axiom.Client) (*logrus.Logger, func()) {
-	return func(dataset string, client *axiom.Client) (*logrus.Logger, func()) {
+func TestHook_ErrorField(t *testing.T) {
+	now := time.Now()
+
+	exp := fmt.Sprintf(`{"_time":"%s","severity":"error","error":"my error","message":"my message"}`,
+		now.Format(time.RFC3339Nano))
+
+	var hasRun uint64
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		b, err := io.ReadAll(zsr)
+		assert.NoError(t, err)
+
+		assert.JSONEq(t, exp, string(b))
+
+		atomic.AddUint64(&hasRun, 1)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	logger, closeHook := adapters.Setup(t, hf, setup(t))
+
+	logger.
+		WithTime(now).
+		WithError(errors.New("my error")).
+		Error("my message")
+
+	closeHook()
+
+	assert.EqualValues(t, 1, atomic.LoadUint64(&hasRun))
+}
+
+func TestHook_FlushInterval(t *testing.T) {
+	var lines uint64
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		s := bufio.NewScanner(zsr)
+		for s.Scan() {
+			atomic.AddUint64(&lines, 1)
+		}
+		assert.NoError(t, s.Err())
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	logger, _ := adapters.Setup(t, hf, setup(t, SetFlushInterval(time.Millisecond*50)))
+
+	logger.Info("my message")
+
+	// Wait for the flush, which is well before the default interval.
+	time.Sleep(time.Millisecond * 250)
+
+	assert.EqualValues(t, 1, atomic.LoadUint64(&lines))
+}
+
+func TestHook_CommonOptions(t *testing.T) {
+	now := time.Now()
+
+	exp := fmt.Sprintf(`{"ts":"%s","lvl":"info","key":"value","msg":"my message"}`,
+		now.Format(time.RFC3339Nano))
+
+	var hasRun uint64
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "ts", r.URL.Query().Get("timestamp-field"))
+
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		b, err := io.ReadAll(zsr)
+		assert.NoError(t, err)
+
+		assert.JSONEq(t, exp, string(b))
+
+		atomic.AddUint64(&hasRun, 1)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	logger, closeHook := adapters.Setup(t, hf, setup(t, SetCommonOptions(
+		common.WithMessageField("msg"),
+		common.WithLevelField("lvl"),
+		common.WithTimeField("ts"),
+	)))
+
+	logger.
+		WithTime(now).
+		WithField("key", "value").
+		Info("my message")
+
+	closeHook()
+
+	assert.EqualValues(t, 1, atomic.LoadUint64(&hasRun))
+}
+
+func TestHook_Shutdown(t *testing.T) {
+	releaseCh := make(chan struct{})
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		// Don't acknowledge the ingestion until the test is done.
+		select {
+		case <-r.Context().Done():
+		case <-releaseCh:
+		}
+	}
+
+	var hook *Hook
+	logger, _ := adapters.Setup(t, hf, func(dataset string, client *axiom.Client) (*logrus.Logger, func()) {
+		t.Helper()
+
+		var err error
+		hook, err = New(
+			SetClient(client),
+			SetDataset(dataset),
+		)
+		require.NoError(t, err)
+
+		logger := logrus.New()
+		logger.AddHook(hook)
+		logger.Out = io.Discard
+
+		return logger, hook.Close
+	})
+	t.Cleanup(func() { close(releaseCh) })
+
+	for i := 0; i < 3; i++ {
+		logger.Info("my message")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+
+	err := hook.Shutdown(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	var drainErr *common.DrainError
+	require.ErrorAs(t, err, &drainErr)
+	assert.EqualValues(t, 3, drainErr.Dropped)
+}
+
+func TestHook_BlockOnFull(t *testing.T) {
+	var lines uint64
+	releaseCh := make(chan struct{})
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		// Block ingestion until all events are logged.
+		<-releaseCh
+
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		s := bufio.NewScanner(zsr)
+		for s.Scan() {
+			atomic.AddUint64(&lines, 1)
+		}
+		assert.NoError(t, s.Err())
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	var onDrop atomic.Int64
+	var hook *Hook
+	logger, closeHook := adapters.Setup(t, hf, func(dataset string, client *axiom.Client) (*logrus.Logger, func()) {
 		t.Helper()
 
-		hook, err := New(
+		var err error
+		hook, err = New(
 			SetClient(client),
 			SetDataset(dataset),
+			SetCommonOptions(
+				common.WithBlockOnFull(false),
+				common.WithOnDrop(func(n int) { onDrop.Add(int64(n)) }),
+			),
 		)
 		require.NoError(t, err)
 		t.Cleanup(hook.Close)
 
+		logger := logrus.New()
+		logger.AddHook(hook)
+		logger.Out = io.Discard
+
+		return logger, hook.Close
+	})
+
+	// At most one batch is in flight and one is buffered, all other events
+	// must be dropped without blocking.
+	for i := 0; i < 3000; i++ {
+		logger.Info("my message")
+	}
+
+	assert.GreaterOrEqual(t, hook.DroppedCount(), uint64(1000))
+	assert.EqualValues(t, hook.DroppedCount(), onDrop.Load())
+
+	close(releaseCh)
+	closeHook()
+
+	assert.EqualValues(t, 3000-hook.DroppedCount(), atomic.LoadUint64(&lines))
+}
+
+func setup(t *testing.T, options ...Option) func(dataset string, client *axiom.Client) (*logrus.Logger, func()) {
+	return func(dataset string, client *axiom.Client) (*logrus.Logger, func()) {
+		t.Helper()
+
+		hook, err := New(append([]Option{
+			SetClient(client),
+			SetDataset(dataset),
+		}, options...)...)
+		require.NoError(t, err)
+		t.Cleanup(hook.Close)
+
 		logger := logrus.New()
 		logger.AddHook(hook)
 
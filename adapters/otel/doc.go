@@ -0,0 +1,6 @@
+// Package otel provides an exporter for the logs signal of the OpenTelemetry
+// Go SDK (go.opentelemetry.io/otel/sdk/log).
+//
+// The exporter lives in its own module, as the logs SDK requires a more recent
+// Go version and OpenTelemetry release than the Axiom Go module itself.
+package otel
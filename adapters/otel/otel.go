@@ -0,0 +1,302 @@
+package otel
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+
+	"github.com/axiomhq/axiom-go/adapters/common"
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/axiom/ingest"
+)
+
+var _ sdklog.Exporter = (*Exporter)(nil)
+
+const (
+	// resourceField is the field the attributes of the resource that emitted a
+	// record are stored in.
+	resourceField = "resource"
+	// scopeField is the field the name and version of the instrumentation
+	// scope that emitted a record are stored in.
+	scopeField = "scope"
+	// eventNameField is the field the event name of a record is stored in.
+	eventNameField = "event_name"
+	// traceIDField and spanIDField are the fields the trace and span ID of a
+	// record are stored in, if it was emitted in the context of a span.
+	traceIDField = "trace_id"
+	spanIDField  = "span_id"
+)
+
+// ErrMissingDatasetName is raised when a dataset name is not provided. Set it
+// manually using the [SetDataset] option or export "AXIOM_DATASET".
+var ErrMissingDatasetName = errors.New("missing dataset name")
+
+// An Option modifies the behaviour of the Axiom exporter.
+type Option func(*Exporter) error
+
+// SetClient specifies the Axiom client to use for ingesting the logs.
+func SetClient(client *axiom.Client) Option {
+	return func(e *Exporter) error {
+		e.client = client
+		return nil
+	}
+}
+
+// SetClientOptions specifies the Axiom client options to pass to
+// [axiom.NewClient] which is only called if no [axiom.Client] was specified by
+// the [SetClient] option.
+func SetClientOptions(options ...axiom.Option) Option {
+	return func(e *Exporter) error {
+		e.clientOptions = options
+		return nil
+	}
+}
+
+// SetDataset specifies the dataset to ingest the logs into. Can also be
+// specified using the "AXIOM_DATASET" environment variable.
+func SetDataset(datasetName string) Option {
+	return func(e *Exporter) error {
+		e.datasetName = datasetName
+		return nil
+	}
+}
+
+// SetIngestOptions specifies the ingestion options to use for ingesting the
+// logs.
+func SetIngestOptions(opts ...ingest.Option) Option {
+	return func(e *Exporter) error {
+		e.ingestOptions = opts
+		return nil
+	}
+}
+
+// SetCommonOptions specifies the options shared by all adapters. The message
+// and level are stored in the "message" and "severity" fields by default. As
+// records are buffered by the [sdklog.BatchProcessor] and not by the exporter,
+// the options concerning a full buffer are ignored.
+func SetCommonOptions(options ...common.Option) Option {
+	return func(e *Exporter) error {
+		e.commonOptions = options
+		return nil
+	}
+}
+
+// Exporter implements a [sdklog.Exporter] used for shipping logs to Axiom. It
+// is meant to be wrapped by a [sdklog.BatchProcessor] which batches the
+// records, as every call to [Exporter.Export] results in an ingest request.
+type Exporter struct {
+	client      *axiom.Client
+	datasetName string
+
+	clientOptions []axiom.Option
+	ingestOptions []ingest.Option
+	commonOptions []common.Option
+
+	config   common.Config
+	shutdown atomic.Bool
+}
+
+// New creates a new [Exporter] configured to ingest logs into Axiom. It
+// automatically takes its configuration from the environment. To connect,
+// export the following environment variables:
+//
+//   - AXIOM_TOKEN
+//   - AXIOM_ORG_ID (only when using a personal token)
+//   - AXIOM_DATASET
+//
+// The configuration can be set manually using options which are prefixed with
+// "Set".
+//
+// An API token with "ingest" permission is sufficient enough.
+//
+// Register the exporter with a [sdklog.LoggerProvider], wrapped by a
+// [sdklog.BatchProcessor]:
+//
+//	provider := sdklog.NewLoggerProvider(
+//		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)),
+//	)
+func New(options ...Option) (*Exporter, error) {
+	e := new(Exporter)
+
+	// Apply supplied options.
+	for _, option := range options {
+		if option == nil {
+			continue
+		} else if err := option(e); err != nil {
+			return nil, err
+		}
+	}
+
+	// Create client, if not set.
+	if e.client == nil {
+		var err error
+		if e.client, err = axiom.NewClient(e.clientOptions...); err != nil {
+			return nil, err
+		}
+	}
+
+	// When the dataset name is not set, use "AXIOM_DATASET".
+	if e.datasetName == "" {
+		if e.datasetName = os.Getenv("AXIOM_DATASET"); e.datasetName == "" {
+			return nil, ErrMissingDatasetName
+		}
+	}
+
+	e.config = common.Config{
+		MessageField: "message",
+		LevelField:   "severity",
+		TimeField:    ingest.TimestampField,
+	}.Apply(e.commonOptions...)
+	e.ingestOptions = e.config.IngestOptions(e.ingestOptions...)
+
+	return e, nil
+}
+
+// Export ingests the given records as a single batch. The records are not
+// retained.
+//
+// It implements [sdklog.Exporter].
+func (e *Exporter) Export(ctx context.Context, records []sdklog.Record) error {
+	if e.shutdown.Load() || len(records) == 0 {
+		return nil
+	}
+
+	events := make([]axiom.Event, 0, len(records))
+	for i := range records {
+		events = append(events, e.event(&records[i]))
+	}
+
+	res, err := e.client.IngestEvents(ctx, e.datasetName, events, e.ingestOptions...)
+	if err != nil {
+		return err
+	} else if res.Failed > 0 {
+		// Best effort on notifying the user about the ingest failure.
+		return fmt.Errorf("event at %s failed to ingest: %s",
+			res.Failures[0].Timestamp, res.Failures[0].Error)
+	}
+
+	return nil
+}
+
+// Shutdown shuts down the exporter. Subsequent calls to [Exporter.Export] are
+// no-ops. As records are ingested synchronously by [Exporter.Export], there is
+// nothing left to flush.
+//
+// It implements [sdklog.Exporter].
+func (e *Exporter) Shutdown(ctx context.Context) error {
+	e.shutdown.Store(true)
+	return ctx.Err()
+}
+
+// ForceFlush is a no-op, as records are ingested synchronously by
+// [Exporter.Export]. Flushing the records buffered by the SDK is up to the
+// processor wrapping the exporter.
+//
+// It implements [sdklog.Exporter].
+func (e *Exporter) ForceFlush(ctx context.Context) error {
+	return ctx.Err()
+}
+
+// event maps the given record to an Axiom event. Attributes are stored as
+// top-level fields.
+func (e *Exporter) event(r *sdklog.Record) axiom.Event {
+	event := make(axiom.Event, r.AttributesLen()+8)
+
+	r.WalkAttributes(func(kv otellog.KeyValue) bool {
+		event[kv.Key] = value(kv.Value)
+		return true
+	})
+
+	ts := r.Timestamp()
+	if ts.IsZero() {
+		ts = r.ObservedTimestamp()
+	}
+	event[e.config.TimeField] = ts
+
+	if level := severity(r); level != "" {
+		event[e.config.LevelField] = level
+	}
+	if body := r.Body(); !body.Empty() {
+		event[e.config.MessageField] = value(body)
+	}
+	if name := r.EventName(); name != "" {
+		event[eventNameField] = name
+	}
+
+	if traceID := r.TraceID(); traceID.IsValid() {
+		event[traceIDField] = hex.EncodeToString(traceID[:])
+	}
+	if spanID := r.SpanID(); spanID.IsValid() {
+		event[spanIDField] = hex.EncodeToString(spanID[:])
+	}
+
+	if res := r.Resource(); res != nil && res.Len() > 0 {
+		attrs := make(map[string]any, res.Len())
+		for iter := res.Iter(); iter.Next(); {
+			attr := iter.Attribute()
+			attrs[string(attr.Key)] = attr.Value.AsInterface()
+		}
+		event[resourceField] = attrs
+	}
+
+	if scope := r.InstrumentationScope(); scope.Name != "" {
+		attrs := map[string]any{"name": scope.Name}
+		if scope.Version != "" {
+			attrs["version"] = scope.Version
+		}
+		event[scopeField] = attrs
+	}
+
+	return event
+}
+
+// severity returns the severity text of the given record or, if not set, the
+// lower case name of its severity. An empty string is returned if neither is
+// set.
+func severity(r *sdklog.Record) string {
+	if text := r.SeverityText(); text != "" {
+		return text
+	} else if sev := r.Severity(); sev != otellog.SeverityUndefined {
+		return strings.ToLower(sev.String())
+	}
+	return ""
+}
+
+// value converts the given log value to its Go representation.
+func value(v otellog.Value) any {
+	switch v.Kind() {
+	case otellog.KindBool:
+		return v.AsBool()
+	case otellog.KindFloat64:
+		return v.AsFloat64()
+	case otellog.KindInt64:
+		return v.AsInt64()
+	case otellog.KindString:
+		return v.AsString()
+	case otellog.KindBytes:
+		return v.AsBytes()
+	case otellog.KindSlice:
+		values := v.AsSlice()
+		res := make([]any, len(values))
+		for i, val := range values {
+			res[i] = value(val)
+		}
+		return res
+	case otellog.KindMap:
+		kvs := v.AsMap()
+		res := make(map[string]any, len(kvs))
+		for _, kv := range kvs {
+			res[kv.Key] = value(kv.Value)
+		}
+		return res
+	case otellog.KindEmpty:
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package otel_test
+
+import (
+	"context"
+	"log"
+
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+
+	adapter "github.com/axiomhq/axiom-go/adapters/otel"
+)
+
+func Example() {
+	// Export "AXIOM_DATASET" in addition to the required environment variables.
+
+	exporter, err := adapter.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	provider := sdklog.NewLoggerProvider(
+		sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)),
+	)
+	defer func() {
+		if shutdownErr := provider.Shutdown(context.Background()); shutdownErr != nil {
+			log.Fatal(shutdownErr)
+		}
+	}()
+
+	logger := provider.Logger("example")
+
+	var record otellog.Record
+	record.SetSeverity(otellog.SeverityInfo)
+	record.SetBody(otellog.StringValue("This is awesome!"))
+	record.AddAttributes(otellog.String("mood", "hyped"))
+
+	logger.Emit(context.Background(), record)
+}
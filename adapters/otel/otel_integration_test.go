@@ -0,0 +1,49 @@
+//go:build integration
+
+package otel_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+
+	adapter "github.com/axiomhq/axiom-go/adapters/otel"
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/internal/test/adapters"
+)
+
+func Test(t *testing.T) {
+	adapters.IntegrationTest(t, "otel", func(ctx context.Context, dataset string, client *axiom.Client) {
+		exporter, err := adapter.New(
+			adapter.SetClient(client),
+			adapter.SetDataset(dataset),
+		)
+		require.NoError(t, err)
+
+		provider := sdklog.NewLoggerProvider(
+			sdklog.WithProcessor(sdklog.NewBatchProcessor(exporter)),
+		)
+		defer func() {
+			err := provider.Shutdown(ctx)
+			assert.NoError(t, err)
+		}()
+
+		logger := provider.Logger("integration-test")
+
+		emit := func(severity otellog.Severity, msg, mood string) {
+			var record otellog.Record
+			record.SetSeverity(severity)
+			record.SetBody(otellog.StringValue(msg))
+			record.AddAttributes(otellog.String("mood", mood))
+			logger.Emit(ctx, record)
+		}
+
+		emit(otellog.SeverityInfo, "This is awesome!", "hyped")
+		emit(otellog.SeverityWarn, "This is no that awesome...", "worried")
+		emit(otellog.SeverityError, "This is rather bad.", "depressed")
+	})
+}
@@ -0,0 +1,200 @@
+package otel
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	otellog "go.opentelemetry.io/otel/log"
+	sdklog "go.opentelemetry.io/otel/sdk/log"
+	"go.opentelemetry.io/otel/sdk/resource"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+
+	"github.com/axiomhq/axiom-go/adapters/common"
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/internal/test/adapters"
+	"github.com/axiomhq/axiom-go/internal/test/testhelper"
+)
+
+// TestNew makes sure New() picks up the "AXIOM_DATASET" environment variable.
+func TestNew(t *testing.T) {
+	testhelper.SafeClearEnv(t)
+
+	t.Setenv("AXIOM_TOKEN", "xaat-test")
+	t.Setenv("AXIOM_ORG_ID", "123")
+
+	exporter, err := New()
+	require.ErrorIs(t, err, ErrMissingDatasetName)
+	require.Nil(t, exporter)
+
+	t.Setenv("AXIOM_DATASET", "test")
+
+	exporter, err = New()
+	require.NoError(t, err)
+	require.NotNil(t, exporter)
+}
+
+func TestExporter(t *testing.T) {
+	now := time.Now()
+
+	exp := fmt.Sprintf(`{
+		"_time": "%s",
+		"severity": "info",
+		"message": "my message",
+		"key": "value",
+		"nested": { "count": 1, "tags": ["a", "b"] },
+		"resource": { "service.name": "my-service" },
+		"scope": { "name": "my-scope", "version": "v1.0.0" }
+	}`, now.Format(time.RFC3339Nano))
+
+	hasRun := false
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		b, err := io.ReadAll(zsr)
+		require.NoError(t, err)
+
+		assert.JSONEq(t, exp, string(b))
+
+		hasRun = true
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	logger, _ := adapters.Setup(t, hf, func(dataset string, client *axiom.Client) (otellog.Logger, func()) {
+		t.Helper()
+
+		exporter, err := New(
+			SetClient(client),
+			SetDataset(dataset),
+		)
+		require.NoError(t, err)
+
+		provider := sdklog.NewLoggerProvider(
+			sdklog.WithProcessor(sdklog.NewSimpleProcessor(exporter)),
+			sdklog.WithResource(resource.NewSchemaless(semconv.ServiceName("my-service"))),
+		)
+		t.Cleanup(func() {
+			err := provider.Shutdown(context.Background())
+			require.NoError(t, err)
+		})
+
+		return provider.Logger("my-scope", otellog.WithInstrumentationVersion("v1.0.0")), func() {}
+	})
+
+	var record otellog.Record
+	record.SetTimestamp(now)
+	record.SetSeverity(otellog.SeverityInfo)
+	record.SetBody(otellog.StringValue("my message"))
+	record.AddAttributes(
+		otellog.String("key", "value"),
+		otellog.Map("nested",
+			otellog.Int("count", 1),
+			otellog.Slice("tags", otellog.StringValue("a"), otellog.StringValue("b")),
+		),
+	)
+	logger.Emit(context.Background(), record)
+
+	assert.True(t, hasRun)
+}
+
+func TestExporter_CommonOptions(t *testing.T) {
+	now := time.Now()
+
+	exp := fmt.Sprintf(`{"ts":"%s","level":"warning","msg":"my message"}`,
+		now.Format(time.RFC3339Nano))
+
+	hasRun := false
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "ts", r.URL.Query().Get("timestamp-field"))
+
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		b, err := io.ReadAll(zsr)
+		require.NoError(t, err)
+
+		assert.JSONEq(t, exp, string(b))
+
+		hasRun = true
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	exporter, _ := adapters.Setup(t, hf, func(dataset string, client *axiom.Client) (*Exporter, func()) {
+		t.Helper()
+
+		exporter, err := New(
+			SetClient(client),
+			SetDataset(dataset),
+			SetCommonOptions(
+				common.WithMessageField("msg"),
+				common.WithLevelField("level"),
+				common.WithTimeField("ts"),
+			),
+		)
+		require.NoError(t, err)
+
+		return exporter, func() {}
+	})
+
+	var record sdklog.Record
+	record.SetTimestamp(now)
+	record.SetSeverityText("warning")
+	record.SetBody(otellog.StringValue("my message"))
+
+	err := exporter.Export(context.Background(), []sdklog.Record{record})
+	require.NoError(t, err)
+
+	assert.True(t, hasRun)
+}
+
+func TestExporter_Shutdown(t *testing.T) {
+	var calls int
+	hf := func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	exporter, _ := adapters.Setup(t, hf, func(dataset string, client *axiom.Client) (*Exporter, func()) {
+		t.Helper()
+
+		exporter, err := New(
+			SetClient(client),
+			SetDataset(dataset),
+		)
+		require.NoError(t, err)
+
+		return exporter, func() {}
+	})
+
+	var record sdklog.Record
+	record.SetBody(otellog.StringValue("my message"))
+
+	require.NoError(t, exporter.Export(context.Background(), []sdklog.Record{record}))
+	require.NoError(t, exporter.ForceFlush(context.Background()))
+	require.NoError(t, exporter.Shutdown(context.Background()))
+
+	// Records exported after shutdown are discarded.
+	require.NoError(t, exporter.Export(context.Background(), []sdklog.Record{record}))
+
+	assert.Equal(t, 1, calls)
+
+	// The deadline or cancellation of the context is honored.
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	assert.ErrorIs(t, exporter.ForceFlush(ctx), context.Canceled)
+	assert.ErrorIs(t, exporter.Shutdown(ctx), context.Canceled)
+}
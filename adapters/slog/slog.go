@@ -9,9 +9,12 @@ import (
 	"log/slog"
 	"os"
 	"slices"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/axiomhq/axiom-go/adapters/common"
 	"github.com/axiomhq/axiom-go/axiom"
 	"github.com/axiomhq/axiom-go/axiom/ingest"
 )
@@ -24,6 +27,19 @@ const defaultBatchSize = 1000
 // manually using the [SetDataset] option or export "AXIOM_DATASET".
 var ErrMissingDatasetName = errors.New("missing dataset name")
 
+// AttributeNesting controls how grouped attributes are represented in the
+// events ingested into Axiom.
+type AttributeNesting uint8
+
+const (
+	// Nested keeps grouped attributes as nested objects, e.g.
+	// {"http":{"method":"GET"}}. This is the default.
+	Nested AttributeNesting = iota
+	// FlattenDots flattens grouped attributes into top-level keys separated by
+	// dots, e.g. {"http.method":"GET"}.
+	FlattenDots
+)
+
 // An Option modifies the behaviour of the Axiom handler.
 type Option func(*Handler) error
 
@@ -71,14 +87,58 @@ func SetLevel(level slog.Leveler) Option {
 	}
 }
 
+// SetAttributeNesting specifies how grouped attributes, either created by
+// [slog.Group] or [Handler.WithGroup], are represented in the events. Defaults
+// to [Nested].
+func SetAttributeNesting(nesting AttributeNesting) Option {
+	return func(h *Handler) error {
+		h.nesting = nesting
+		return nil
+	}
+}
+
+// SetBufferSize specifies the amount of events buffered by the handler until
+// they are picked up for ingestion. Defaults to 1000.
+func SetBufferSize(size int) Option {
+	return func(h *Handler) error {
+		if size < 0 {
+			return errors.New("buffer size must not be negative")
+		}
+		h.bufferSize = size
+		return nil
+	}
+}
+
+// SetCommonOptions specifies the options shared by all adapters. The message
+// and level are stored in the "msg" and "level" fields by default and the
+// handler blocks when its buffer is full.
+func SetCommonOptions(options ...common.Option) Option {
+	return func(h *Handler) error {
+		h.commonOptions = options
+		return nil
+	}
+}
+
 type rootHandler struct {
 	client      *axiom.Client
 	datasetName string
 
 	clientOptions []axiom.Option
 	ingestOptions []ingest.Option
+	commonOptions []common.Option
+	nesting       AttributeNesting
+	bufferSize    int
+
+	config  common.Config
+	dropped atomic.Uint64
+
+	// sent is the amount of events passed on for ingestion, processed the
+	// amount of events the server has acknowledged.
+	sent      atomic.Uint64
+	processed uint64
 
 	eventCh   chan axiom.Event
+	cancel    context.CancelFunc
 	closeCh   chan struct{}
 	closeOnce sync.Once
 }
@@ -109,7 +169,8 @@ type Handler struct {
 // calling [Handler.Close].
 func New(options ...Option) (*Handler, error) {
 	root := &rootHandler{
-		eventCh: make(chan axiom.Event, defaultBatchSize),
+		bufferSize: defaultBatchSize,
+
 		closeCh: make(chan struct{}),
 	}
 
@@ -142,13 +203,29 @@ func New(options ...Option) (*Handler, error) {
 		}
 	}
 
+	root.config = common.Config{
+		MessageField: slog.MessageKey,
+		LevelField:   slog.LevelKey,
+		TimeField:    ingest.TimestampField,
+		BlockOnFull:  true,
+	}.Apply(root.commonOptions...)
+	root.ingestOptions = root.config.IngestOptions(root.ingestOptions...)
+
+	root.eventCh = make(chan axiom.Event, root.bufferSize)
+
 	// Run background ingest.
+	var ctx context.Context
+	ctx, root.cancel = context.WithCancel(context.Background())
 	go func() {
 		defer close(root.closeCh)
+		defer root.cancel()
 
 		logger := log.New(os.Stderr, "[AXIOM|SLOG]", 0)
 
-		res, err := root.client.IngestChannel(context.Background(), root.datasetName, root.eventCh, root.ingestOptions...)
+		res, err := root.client.IngestChannel(ctx, root.datasetName, root.eventCh, root.ingestOptions...)
+		if res != nil {
+			root.processed = res.Ingested + res.Failed
+		}
 		if err != nil {
 			logger.Printf("failed to ingest events: %s\n", err)
 		} else if res.Failed > 0 {
@@ -164,10 +241,33 @@ func New(options ...Option) (*Handler, error) {
 // Close the handler and make sure all events are flushed. Closing the handler
 // renders it unusable for further use.
 func (h *Handler) Close() {
+	_ = h.Shutdown(context.Background())
+}
+
+// Shutdown closes the handler like [Handler.Close] but stops waiting for the
+// events to be flushed when the context is done. In that case, ingestion is
+// aborted and a [*common.DrainError] carrying the amount of dropped events is
+// returned.
+func (h *Handler) Shutdown(ctx context.Context) error {
 	h.closeOnce.Do(func() {
 		close(h.eventCh)
-		<-h.closeCh
 	})
+
+	if err := common.Drain(ctx, h.closeCh, h.cancel); err != nil {
+		return &common.DrainError{
+			Dropped: h.sent.Load() - h.processed,
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// DroppedCount returns the amount of events dropped because the buffer was
+// full. Events are only dropped if blocking is disabled by passing
+// [common.WithBlockOnFull] to the [SetCommonOptions] option.
+func (h *Handler) DroppedCount() uint64 {
+	return h.dropped.Load()
 }
 
 // Enabled implements [slog.Handler].
@@ -183,34 +283,57 @@ func (h *Handler) Enabled(_ context.Context, level slog.Level) bool {
 func (h *Handler) Handle(_ context.Context, r slog.Record) error {
 	event := axiom.Event{}
 
+	// When flattening, attributes in handler groups are prefixed with the
+	// group names.
+	var prefix string
+	if h.nesting == FlattenDots && len(h.groups) > 0 {
+		prefix = strings.Join(h.groups, ".") + "."
+	}
+
 	// Set handler attributes first, record attributes second.
 	for _, attr := range h.attrs {
-		addAttrToEvent(event, attr)
+		h.addAttrToEvent(event, prefix, attr)
 	}
 	r.Attrs(func(attr slog.Attr) bool {
-		addAttrToEvent(event, attr)
+		h.addAttrToEvent(event, prefix, attr)
 		return true
 	})
 
 	// Nest attributes in handler groups as objects, if any.
-	for i := len(h.groups) - 1; i >= 0; i-- {
-		event = axiom.Event{h.groups[i]: event}
+	if h.nesting == Nested {
+		for i := len(h.groups) - 1; i >= 0; i-- {
+			event = axiom.Event{h.groups[i]: event}
+		}
 	}
 
 	// Set timestamp, level and actual message. The zero time is ignored.
 	if !r.Time.IsZero() {
-		event[ingest.TimestampField] = r.Time.Format(time.RFC3339Nano)
+		event[h.config.TimeField] = r.Time.Format(time.RFC3339Nano)
 	}
-	event[slog.LevelKey] = r.Level.String()
-	event[slog.MessageKey] = r.Message
+	event[h.config.LevelField] = r.Level.String()
+	event[h.config.MessageField] = r.Message
 
 	select {
 	case <-h.closeCh:
 		return errors.New("handler closed")
 	default:
+	}
+
+	if h.config.BlockOnFull {
+		h.sent.Add(1)
 		h.eventCh <- event
 		return nil
 	}
+
+	select {
+	case h.eventCh <- event:
+		h.sent.Add(1)
+	default:
+		h.dropped.Add(1)
+		h.config.Drop(1)
+	}
+
+	return nil
 }
 
 // WithAttrs implements [slog.Handler].
@@ -243,22 +366,34 @@ func (h *Handler) clone() *Handler {
 	}
 }
 
-func addAttrToEvent(event axiom.Event, attr slog.Attr) {
+func (h *Handler) addAttrToEvent(event axiom.Event, prefix string, attr slog.Attr) {
 	if attr.Equal(slog.Attr{}) {
 		return
 	}
 
-	// If we have a group, nest it as an object.
 	v := attr.Value.Resolve()
-	if v.Kind() == slog.KindGroup {
-		group := axiom.Event{}
-		for _, attr := range v.Group() {
-			addAttrToEvent(group, attr)
+	if v.Kind() != slog.KindGroup {
+		event[prefix+attr.Key] = v.Any()
+		return
+	}
+
+	// If we have a group, either flatten it into the event using its key as
+	// prefix or nest it as an object.
+	if h.nesting == FlattenDots {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
 		}
-		if len(group) > 0 && attr.Key != "" {
-			event[attr.Key] = group
+		for _, attr := range v.Group() {
+			h.addAttrToEvent(event, prefix, attr)
 		}
-	} else {
-		event[attr.Key] = v.Any()
+		return
+	}
+
+	group := axiom.Event{}
+	for _, attr := range v.Group() {
+		h.addAttrToEvent(group, "", attr)
+	}
+	if len(group) > 0 && attr.Key != "" {
+		event[attr.Key] = group
 	}
 }
@@ -17,6 +17,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/axiomhq/axiom-go/adapters/common"
 	"github.com/axiomhq/axiom-go/axiom"
 	"github.com/axiomhq/axiom-go/axiom/ingest"
 	"github.com/axiomhq/axiom-go/internal/test/adapters"
@@ -183,17 +184,251 @@ func TestHandler_Groups(t *testing.T) {
 	assert.EqualValues(t, 2, atomic.LoadUint64(&lines))
 }
 
-func setup(t *testing.T) func(dataset string, client *axiom.Client) (*slog.Logger, func()) {
-	return func(dataset string, client *axiom.Client) (*slog.Logger, func()) {
+func TestHandler_AttributeNesting(t *testing.T) {
+	tests := []struct {
+		name    string
+		nesting AttributeNesting
+		exp     string
+	}{
+		{
+			name:    "nested",
+			nesting: Nested,
+			exp:     `{"_time":"%s","level":"INFO","req":{"http":{"method":"GET","status":200}},"msg":"my message"}`,
+		},
+		{
+			name:    "flatten dots",
+			nesting: FlattenDots,
+			exp:     `{"_time":"%s","level":"INFO","req.http.method":"GET","req.http.status":200,"msg":"my message"}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exp := fmt.Sprintf(tt.exp, time.Now().Format(time.RFC3339Nano))
+
+			var lines uint64
+			hf := func(w http.ResponseWriter, r *http.Request) {
+				zsr, err := zstd.NewReader(r.Body)
+				require.NoError(t, err)
+
+				s := bufio.NewScanner(zsr)
+				for s.Scan() {
+					testhelper.JSONEqExp(t, exp, s.Text(), []string{ingest.TimestampField})
+					atomic.AddUint64(&lines, 1)
+				}
+				assert.NoError(t, s.Err())
+
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = w.Write([]byte("{}"))
+			}
+
+			logger, closeHandler := adapters.Setup(t, hf, setup(t, SetAttributeNesting(tt.nesting)))
+
+			logger.WithGroup("req").LogAttrs(context.Background(), slog.LevelInfo, "my message",
+				slog.Group("http", slog.String("method", "GET"), slog.Int("status", 200)),
+			)
+
+			closeHandler()
+
+			assert.EqualValues(t, 1, atomic.LoadUint64(&lines))
+		})
+	}
+}
+
+func TestHandler_DropOnFull(t *testing.T) {
+	var lines uint64
+	releaseCh := make(chan struct{})
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		// Block ingestion until all events are logged.
+		<-releaseCh
+
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		s := bufio.NewScanner(zsr)
+		for s.Scan() {
+			atomic.AddUint64(&lines, 1)
+		}
+		assert.NoError(t, s.Err())
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	var handler *Handler
+	logger, closeHandler := adapters.Setup(t, hf, func(dataset string, client *axiom.Client) (*slog.Logger, func()) {
 		t.Helper()
 
-		handler, err := New(
+		var err error
+		handler, err = New(
 			SetClient(client),
 			SetDataset(dataset),
+			SetBufferSize(1),
+			SetCommonOptions(common.WithBlockOnFull(false)),
 		)
 		require.NoError(t, err)
 		t.Cleanup(handler.Close)
 
+		return slog.New(handler), handler.Close
+	})
+
+	// At most one event is in flight and one is buffered, all others must be
+	// dropped without blocking.
+	for i := 0; i < 10; i++ {
+		logger.Info("my message")
+	}
+
+	assert.GreaterOrEqual(t, handler.DroppedCount(), uint64(8))
+
+	close(releaseCh)
+	closeHandler()
+
+	assert.EqualValues(t, 10-handler.DroppedCount(), atomic.LoadUint64(&lines))
+}
+
+func TestHandler_BlockOnFull(t *testing.T) {
+	var lines uint64
+	releaseCh := make(chan struct{})
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		// Block ingestion until all events are logged.
+		<-releaseCh
+
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		s := bufio.NewScanner(zsr)
+		for s.Scan() {
+			atomic.AddUint64(&lines, 1)
+		}
+		assert.NoError(t, s.Err())
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	var onDrop atomic.Int64
+	var handler *Handler
+	logger, closeHandler := adapters.Setup(t, hf, func(dataset string, client *axiom.Client) (*slog.Logger, func()) {
+		t.Helper()
+
+		var err error
+		handler, err = New(
+			SetClient(client),
+			SetDataset(dataset),
+			SetBufferSize(1),
+			SetCommonOptions(
+				common.WithBlockOnFull(false),
+				common.WithOnDrop(func(n int) { onDrop.Add(int64(n)) }),
+			),
+		)
+		require.NoError(t, err)
+		t.Cleanup(handler.Close)
+
+		return slog.New(handler), handler.Close
+	})
+
+	// At most one event is in flight and one is buffered, all others must be
+	// dropped without blocking.
+	for i := 0; i < 10; i++ {
+		logger.Info("my message")
+	}
+
+	assert.GreaterOrEqual(t, handler.DroppedCount(), uint64(8))
+	assert.EqualValues(t, handler.DroppedCount(), onDrop.Load())
+
+	close(releaseCh)
+	closeHandler()
+
+	assert.EqualValues(t, 10-handler.DroppedCount(), atomic.LoadUint64(&lines))
+}
+
+func TestHandler_CommonOptions(t *testing.T) {
+	exp := fmt.Sprintf(`{"ts":"%s","severity":"INFO","key":"value","message":"my message"}`,
+		time.Now().Format(time.RFC3339Nano))
+
+	var hasRun uint64
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "ts", r.URL.Query().Get("timestamp-field"))
+
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		b, err := io.ReadAll(zsr)
+		assert.NoError(t, err)
+
+		testhelper.JSONEqExp(t, exp, string(b), []string{"ts"})
+
+		atomic.AddUint64(&hasRun, 1)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	logger, flush := adapters.Setup(t, hf, setup(t, SetCommonOptions(
+		common.WithMessageField("message"),
+		common.WithLevelField("severity"),
+		common.WithTimeField("ts"),
+	)))
+
+	logger.
+		With("key", "value").
+		Info("my message")
+
+	flush()
+
+	assert.EqualValues(t, 1, atomic.LoadUint64(&hasRun))
+}
+
+func TestHandler_Shutdown(t *testing.T) {
+	releaseCh := make(chan struct{})
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		// Don't acknowledge the ingestion until the test is done.
+		select {
+		case <-r.Context().Done():
+		case <-releaseCh:
+		}
+	}
+
+	var handler *Handler
+	logger, _ := adapters.Setup(t, hf, func(dataset string, client *axiom.Client) (*slog.Logger, func()) {
+		t.Helper()
+
+		var err error
+		handler, err = New(
+			SetClient(client),
+			SetDataset(dataset),
+		)
+		require.NoError(t, err)
+
+		return slog.New(handler), handler.Close
+	})
+	t.Cleanup(func() { close(releaseCh) })
+
+	for i := 0; i < 3; i++ {
+		logger.Info("my message")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+
+	err := handler.Shutdown(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	var drainErr *common.DrainError
+	require.ErrorAs(t, err, &drainErr)
+	assert.EqualValues(t, 3, drainErr.Dropped)
+}
+
+func setup(t *testing.T, options ...Option) func(dataset string, client *axiom.Client) (*slog.Logger, func()) {
+	return func(dataset string, client *axiom.Client) (*slog.Logger, func()) {
+		t.Helper()
+
+		handler, err := New(append([]Option{
+			SetClient(client),
+			SetDataset(dataset),
+		}, options...)...)
+		require.NoError(t, err)
+		t.Cleanup(handler.Close)
+
 		return slog.New(handler), handler.Close
 	}
 }
@@ -0,0 +1,2 @@
+// Package stdlog provides an adapter for the standard library's log package.
+package stdlog
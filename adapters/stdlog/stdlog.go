@@ -0,0 +1,351 @@
+package stdlog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/axiomhq/axiom-go/adapters/common"
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/axiom/ingest"
+)
+
+var _ io.WriteCloser = (*Writer)(nil)
+
+const defaultBatchSize = 1000
+
+// MessageField is the field the log message is stored in by default.
+const MessageField = "message"
+
+// ErrMissingDatasetName is raised when a dataset name is not provided. Set it
+// manually using the [SetDataset] option or export "AXIOM_DATASET".
+var ErrMissingDatasetName = errors.New("missing dataset name")
+
+// ErrWriterClosed is returned when writing to a closed [Writer].
+var ErrWriterClosed = errors.New("writer closed")
+
+// An Option modifies the behaviour of the Axiom writer.
+type Option func(*Writer) error
+
+// SetClient specifies the Axiom client to use for ingesting the logs.
+func SetClient(client *axiom.Client) Option {
+	return func(w *Writer) error {
+		w.client = client
+		return nil
+	}
+}
+
+// SetClientOptions specifies the Axiom client options to pass to
+// [axiom.NewClient] which is only called if no [axiom.Client] was specified by
+// the [SetClient] option.
+func SetClientOptions(options ...axiom.Option) Option {
+	return func(w *Writer) error {
+		w.clientOptions = options
+		return nil
+	}
+}
+
+// SetDataset specifies the dataset to ingest the logs into. Can also be
+// specified using the "AXIOM_DATASET" environment variable.
+func SetDataset(datasetName string) Option {
+	return func(w *Writer) error {
+		w.datasetName = datasetName
+		return nil
+	}
+}
+
+// SetIngestOptions specifies the ingestion options to use for ingesting the
+// logs.
+func SetIngestOptions(opts ...ingest.Option) Option {
+	return func(w *Writer) error {
+		w.ingestOptions = opts
+		return nil
+	}
+}
+
+// SetFlushInterval specifies the interval at which the collected logs are
+// ingested, if the batch isn't full before. Defaults to one second.
+func SetFlushInterval(interval time.Duration) Option {
+	return func(w *Writer) error {
+		w.flushInterval = interval
+		return nil
+	}
+}
+
+// SetParseFields specifies if leading key=value pairs of a log line are parsed
+// into fields of the event. Values can be quoted using Go syntax. Parsing
+// stops at the first word that is not a key=value pair and the remainder of
+// the line is used as message. Defaults to false.
+func SetParseFields(parse bool) Option {
+	return func(w *Writer) error {
+		w.parseFields = parse
+		return nil
+	}
+}
+
+// SetCommonOptions specifies the options shared by all adapters. The message is
+// stored in the [MessageField] field by default and the writer blocks when its
+// buffer is full. As the standard library logger has no notion of levels, the
+// level field is ignored.
+func SetCommonOptions(options ...common.Option) Option {
+	return func(w *Writer) error {
+		w.commonOptions = options
+		return nil
+	}
+}
+
+// Writer implements an [io.Writer] used for shipping logs written by a
+// [log.Logger] to Axiom. Every line is ingested as an event with the line as
+// message and the time it was written as timestamp.
+type Writer struct {
+	client      *axiom.Client
+	datasetName string
+
+	clientOptions []axiom.Option
+	ingestOptions []ingest.Option
+	commonOptions []common.Option
+	flushInterval time.Duration
+	parseFields   bool
+
+	config common.Config
+
+	// partial holds an incomplete line until it is terminated by a newline.
+	partial  []byte
+	writeMtx sync.Mutex
+	closed   bool
+
+	// sent is the amount of events passed on for ingestion, processed the
+	// amount of events the server has acknowledged.
+	sent      atomic.Uint64
+	processed uint64
+	dropped   atomic.Uint64
+
+	eventCh   chan axiom.Event
+	cancel    context.CancelFunc
+	closeCh   chan struct{}
+	closeOnce sync.Once
+}
+
+// New creates a new writer that ingests logs into Axiom. It automatically
+// takes its configuration from the environment. To connect, export the
+// following environment variables:
+//
+//   - AXIOM_TOKEN
+//   - AXIOM_ORG_ID (only when using a personal token)
+//   - AXIOM_DATASET
+//
+// The configuration can be set manually using options which are prefixed with
+// "Set".
+//
+// An API token with "ingest" permission is sufficient enough.
+//
+// A writer needs to be closed properly to make sure all logs are sent by
+// calling [Writer.Close].
+func New(options ...Option) (*Writer, error) {
+	w := &Writer{
+		eventCh: make(chan axiom.Event, defaultBatchSize),
+		closeCh: make(chan struct{}),
+	}
+
+	// Apply supplied options.
+	for _, option := range options {
+		if option == nil {
+			continue
+		} else if err := option(w); err != nil {
+			return nil, err
+		}
+	}
+
+	// Create client, if not set.
+	if w.client == nil {
+		var err error
+		if w.client, err = axiom.NewClient(w.clientOptions...); err != nil {
+			return nil, err
+		}
+	}
+
+	// When the dataset name is not set, use "AXIOM_DATASET".
+	if w.datasetName == "" {
+		if w.datasetName = os.Getenv("AXIOM_DATASET"); w.datasetName == "" {
+			return nil, ErrMissingDatasetName
+		}
+	}
+
+	w.config = common.Config{
+		MessageField: MessageField,
+		TimeField:    ingest.TimestampField,
+		BlockOnFull:  true,
+	}.Apply(w.commonOptions...)
+
+	ingestOptions := w.config.IngestOptions(w.ingestOptions...)
+	if w.flushInterval > 0 {
+		ingestOptions = append([]ingest.Option{ingest.SetFlushInterval(w.flushInterval)}, ingestOptions...)
+	}
+
+	// Run background ingest.
+	var ctx context.Context
+	ctx, w.cancel = context.WithCancel(context.Background())
+	go func() {
+		defer close(w.closeCh)
+		defer w.cancel()
+
+		logger := log.New(os.Stderr, "[AXIOM|STDLOG]", 0)
+
+		res, err := w.client.IngestChannel(ctx, w.datasetName, w.eventCh, ingestOptions...)
+		if res != nil {
+			w.processed = res.Ingested + res.Failed
+		}
+		if err != nil {
+			logger.Printf("failed to ingest events: %s\n", err)
+		} else if res.Failed > 0 {
+			// Best effort on notifying the user about the ingest failure.
+			logger.Printf("event at %s failed to ingest: %s\n",
+				res.Failures[0].Timestamp, res.Failures[0].Error)
+		}
+	}()
+
+	return w, nil
+}
+
+// Write implements [io.Writer]. Every complete line is ingested as an event.
+// An incomplete line is kept until it is completed by a subsequent write or
+// the writer is closed.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.writeMtx.Lock()
+	defer w.writeMtx.Unlock()
+
+	if w.closed {
+		return 0, ErrWriterClosed
+	}
+
+	now := time.Now()
+
+	b := p
+	if len(w.partial) > 0 {
+		b = append(w.partial, p...)
+		w.partial = nil
+	}
+
+	for {
+		i := bytes.IndexByte(b, '\n')
+		if i < 0 {
+			break
+		}
+		w.emit(string(b[:i]), now)
+		b = b[i+1:]
+	}
+	if len(b) > 0 {
+		w.partial = append([]byte(nil), b...)
+	}
+
+	return len(p), nil
+}
+
+// Close the writer and make sure all events are flushed. An incomplete line
+// is ingested as is. Closing the writer renders it unusable for further use.
+func (w *Writer) Close() error {
+	return w.Shutdown(context.Background())
+}
+
+// Shutdown closes the writer like [Writer.Close] but stops waiting for the
+// events to be flushed when the context is done. In that case, ingestion is
+// aborted and a [*common.DrainError] carrying the amount of dropped events is
+// returned.
+func (w *Writer) Shutdown(ctx context.Context) error {
+	w.closeOnce.Do(func() {
+		w.writeMtx.Lock()
+		if len(w.partial) > 0 {
+			w.emit(string(w.partial), time.Now())
+			w.partial = nil
+		}
+		w.closed = true
+		w.writeMtx.Unlock()
+
+		close(w.eventCh)
+	})
+
+	if err := common.Drain(ctx, w.closeCh, w.cancel); err != nil {
+		return &common.DrainError{
+			Dropped: w.sent.Load() - w.processed,
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// DroppedCount returns the amount of events dropped because the buffer was
+// full. Events are only dropped if blocking is disabled by passing
+// [common.WithBlockOnFull] to the [SetCommonOptions] option.
+func (w *Writer) DroppedCount() uint64 {
+	return w.dropped.Load()
+}
+
+func (w *Writer) emit(line string, now time.Time) {
+	line = strings.TrimSuffix(line, "\r")
+
+	event := axiom.Event{}
+	if w.parseFields {
+		line = parseFields(event, line)
+	}
+
+	// Set timestamp and actual message.
+	event[w.config.TimeField] = now.Format(time.RFC3339Nano)
+	event[w.config.MessageField] = line
+
+	if !w.config.BlockOnFull {
+		select {
+		case w.eventCh <- event:
+			w.sent.Add(1)
+		default:
+			w.dropped.Add(1)
+			w.config.Drop(1)
+		}
+		return
+	}
+
+	w.sent.Add(1)
+	w.eventCh <- event
+}
+
+// parseFields parses the leading key=value pairs of the given line into the
+// given event and returns the remainder of the line.
+func parseFields(event axiom.Event, line string) string {
+	rest := strings.TrimLeft(line, " ")
+	for rest != "" {
+		eq := strings.IndexByte(rest, '=')
+		if eq <= 0 || strings.ContainsAny(rest[:eq], " \t\"") {
+			break
+		}
+		key, value := rest[:eq], rest[eq+1:]
+
+		var tail string
+		if strings.HasPrefix(value, `"`) {
+			quoted, err := strconv.QuotedPrefix(value)
+			if err != nil {
+				break
+			}
+			tail = value[len(quoted):]
+			value, _ = strconv.Unquote(quoted)
+		} else if i := strings.IndexByte(value, ' '); i >= 0 {
+			value, tail = value[:i], value[i:]
+		}
+
+		// A pair must be followed by a space or end the line.
+		if tail != "" && tail[0] != ' ' {
+			break
+		}
+
+		event[key] = value
+		rest = strings.TrimLeft(tail, " ")
+	}
+	return rest
+}
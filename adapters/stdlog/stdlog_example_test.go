@@ -0,0 +1,30 @@
+package stdlog_test
+
+import (
+	"log"
+
+	adapter "github.com/axiomhq/axiom-go/adapters/stdlog"
+)
+
+func Example() {
+	// Export "AXIOM_DATASET" in addition to the required environment variables.
+
+	writer, err := adapter.New(
+		adapter.SetParseFields(true),
+	)
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() {
+		if closeErr := writer.Close(); closeErr != nil {
+			log.Fatal(closeErr)
+		}
+	}()
+
+	// The writer takes care of the timestamp.
+	logger := log.New(writer, "", 0)
+
+	logger.Print("mood=hyped This is awesome!")
+	logger.Print("mood=worried This is no that awesome...")
+	logger.Print("mood=depressed This is rather bad.")
+}
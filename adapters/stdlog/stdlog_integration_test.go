@@ -0,0 +1,38 @@
+//go:build integration
+
+package stdlog_test
+
+import (
+	"context"
+	"log"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	adapter "github.com/axiomhq/axiom-go/adapters/stdlog"
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/internal/test/adapters"
+)
+
+func Test(t *testing.T) {
+	adapters.IntegrationTest(t, "stdlog", func(_ context.Context, dataset string, client *axiom.Client) {
+		writer, err := adapter.New(
+			adapter.SetClient(client),
+			adapter.SetDataset(dataset),
+			adapter.SetParseFields(true),
+		)
+		require.NoError(t, err)
+
+		defer func() {
+			err := writer.Close()
+			assert.NoError(t, err)
+		}()
+
+		logger := log.New(writer, "", 0)
+
+		logger.Print("mood=hyped This is awesome!")
+		logger.Print("mood=worried This is no that awesome...")
+		logger.Print("mood=depressed This is rather bad.")
+	})
+}
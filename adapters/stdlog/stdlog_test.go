@@ -0,0 +1,281 @@
+package stdlog
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/axiomhq/axiom-go/adapters/common"
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/axiom/ingest"
+	"github.com/axiomhq/axiom-go/internal/test/adapters"
+	"github.com/axiomhq/axiom-go/internal/test/testhelper"
+)
+
+// TestNew makes sure New() picks up the "AXIOM_DATASET" environment variable.
+func TestNew(t *testing.T) {
+	testhelper.SafeClearEnv(t)
+
+	t.Setenv("AXIOM_TOKEN", "xaat-test")
+	t.Setenv("AXIOM_ORG_ID", "123")
+
+	writer, err := New()
+	require.ErrorIs(t, err, ErrMissingDatasetName)
+	require.Nil(t, writer)
+
+	t.Setenv("AXIOM_DATASET", "test")
+
+	writer, err = New()
+	require.NoError(t, err)
+	require.NotNil(t, writer)
+	t.Cleanup(func() { _ = writer.Close() })
+
+	assert.Equal(t, "test", writer.datasetName)
+}
+
+func TestWriter(t *testing.T) {
+	events, hf := recordEvents(t)
+
+	logger, closeWriter := adapters.Setup(t, hf, setup(t))
+
+	logger.Print("my message")
+	logger.Print("key=value my message")
+
+	closeWriter()
+
+	exp := []map[string]any{
+		{"message": "my message"},
+		{"message": "key=value my message"},
+	}
+	assert.Equal(t, exp, events())
+}
+
+func TestWriter_ParseFields(t *testing.T) {
+	events, hf := recordEvents(t)
+
+	logger, closeWriter := adapters.Setup(t, hf, setup(t, SetParseFields(true)))
+
+	logger.Print("my message")
+	logger.Print("user=alice status=200 my message")
+	logger.Print(`path="/a b" dur=1ms`)
+	logger.Print(`user=bob msg="unterminated`)
+	logger.Print("=value a=b")
+
+	closeWriter()
+
+	exp := []map[string]any{
+		{"message": "my message"},
+		{"message": "my message", "user": "alice", "status": "200"},
+		{"message": "", "path": "/a b", "dur": "1ms"},
+		{"message": `msg="unterminated`, "user": "bob"},
+		{"message": "=value a=b"},
+	}
+	assert.Equal(t, exp, events())
+}
+
+func TestWriter_PartialLines(t *testing.T) {
+	events, hf := recordEvents(t)
+
+	writer, closeWriter := adapters.Setup(t, hf, func(dataset string, client *axiom.Client) (*Writer, func()) {
+		t.Helper()
+
+		logger, closeWriter := setup(t)(dataset, client)
+		return logger.Writer().(*Writer), closeWriter
+	})
+
+	_, err := fmt.Fprint(writer, "first ")
+	require.NoError(t, err)
+	_, err = fmt.Fprint(writer, "line\nsecond line\r\nincomplete")
+	require.NoError(t, err)
+
+	closeWriter()
+
+	exp := []map[string]any{
+		{"message": "first line"},
+		{"message": "second line"},
+		{"message": "incomplete"},
+	}
+	assert.Equal(t, exp, events())
+}
+
+func TestWriter_WriteAfterClose(t *testing.T) {
+	_, hf := recordEvents(t)
+
+	logger, closeWriter := adapters.Setup(t, hf, setup(t))
+
+	closeWriter()
+
+	_, err := fmt.Fprintln(logger.Writer(), "my message")
+	assert.ErrorIs(t, err, ErrWriterClosed)
+}
+
+func TestWriter_CommonOptions(t *testing.T) {
+	var hasRun uint64
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "ts", r.URL.Query().Get("timestamp-field"))
+
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		var event map[string]any
+		require.NoError(t, json.NewDecoder(zsr).Decode(&event))
+
+		assert.Contains(t, event, "ts")
+		assert.NotContains(t, event, ingest.TimestampField)
+		assert.Equal(t, "my message", event["msg"])
+
+		atomic.AddUint64(&hasRun, 1)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	logger, closeWriter := adapters.Setup(t, hf, setup(t, SetCommonOptions(
+		common.WithMessageField("msg"),
+		common.WithLevelField("severity"),
+		common.WithTimeField("ts"),
+	)))
+
+	logger.Print("my message")
+
+	closeWriter()
+
+	assert.EqualValues(t, 1, atomic.LoadUint64(&hasRun))
+}
+
+func TestWriter_Shutdown(t *testing.T) {
+	releaseCh := make(chan struct{})
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		// Don't acknowledge the ingestion until the test is done.
+		select {
+		case <-r.Context().Done():
+		case <-releaseCh:
+		}
+	}
+
+	logger, _ := adapters.Setup(t, hf, setup(t))
+	writer := logger.Writer().(*Writer)
+	t.Cleanup(func() { close(releaseCh) })
+
+	for i := 0; i < 3; i++ {
+		logger.Print("my message")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+
+	err := writer.Shutdown(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	var drainErr *common.DrainError
+	require.ErrorAs(t, err, &drainErr)
+	assert.EqualValues(t, 3, drainErr.Dropped)
+}
+
+func TestWriter_BlockOnFull(t *testing.T) {
+	var lines uint64
+	releaseCh := make(chan struct{})
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		// Block ingestion until all events are logged.
+		<-releaseCh
+
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		s := bufio.NewScanner(zsr)
+		for s.Scan() {
+			atomic.AddUint64(&lines, 1)
+		}
+		assert.NoError(t, s.Err())
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	var onDrop atomic.Int64
+	logger, closeWriter := adapters.Setup(t, hf, setup(t, SetCommonOptions(
+		common.WithBlockOnFull(false),
+		common.WithOnDrop(func(n int) { onDrop.Add(int64(n)) }),
+	)))
+	writer := logger.Writer().(*Writer)
+
+	// At most one batch is in flight and one is buffered, all other events
+	// must be dropped without blocking.
+	for i := 0; i < 3000; i++ {
+		logger.Print("my message")
+	}
+
+	assert.GreaterOrEqual(t, writer.DroppedCount(), uint64(1000))
+	assert.EqualValues(t, writer.DroppedCount(), onDrop.Load())
+
+	close(releaseCh)
+	closeWriter()
+
+	assert.EqualValues(t, 3000-writer.DroppedCount(), atomic.LoadUint64(&lines))
+}
+
+// recordEvents returns a handler that records all ingested events without
+// their timestamp and a function that returns the recorded events.
+func recordEvents(t *testing.T) (func() []map[string]any, http.HandlerFunc) {
+	var (
+		events []map[string]any
+		mtx    sync.Mutex
+	)
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		s := bufio.NewScanner(zsr)
+		for s.Scan() {
+			var event map[string]any
+			require.NoError(t, json.Unmarshal(s.Bytes(), &event))
+
+			assert.Contains(t, event, ingest.TimestampField)
+			delete(event, ingest.TimestampField)
+
+			mtx.Lock()
+			events = append(events, event)
+			mtx.Unlock()
+		}
+		assert.NoError(t, s.Err())
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+	return func() []map[string]any {
+		mtx.Lock()
+		defer mtx.Unlock()
+		return events
+	}, hf
+}
+
+func setup(t *testing.T, options ...Option) func(dataset string, client *axiom.Client) (*log.Logger, func()) {
+	return func(dataset string, client *axiom.Client) (*log.Logger, func()) {
+		t.Helper()
+
+		writer, err := New(append([]Option{
+			SetClient(client),
+			SetDataset(dataset),
+		}, options...)...)
+		require.NoError(t, err)
+
+		closeWriter := func() {
+			err := writer.Close()
+			require.NoError(t, err)
+		}
+		t.Cleanup(closeWriter)
+
+		return log.New(writer, "", 0), closeWriter
+	}
+}
@@ -12,6 +12,7 @@ import (
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
+	"github.com/axiomhq/axiom-go/adapters/common"
 	"github.com/axiomhq/axiom-go/axiom"
 	"github.com/axiomhq/axiom-go/axiom/ingest"
 )
@@ -70,7 +71,9 @@ func SetDataset(datasetName string) Option {
 }
 
 // SetIngestOptions specifies the ingestion options to use for ingesting the
-// logs.
+// logs. Logs are ingested using [axiom.DatasetsService.Ingest], so options that
+// are resolved on the client, like [ingest.SetFieldRedactor], are not
+// supported and make ingestion fail with an [axiom.ErrUnsupportedIngestOption].
 func SetIngestOptions(opts ...ingest.Option) Option {
 	return func(ws *WriteSyncer) error {
 		ws.ingestOptions = opts
@@ -87,6 +90,17 @@ func SetLevelEnabler(levelEnabler zapcore.LevelEnabler) Option {
 	}
 }
 
+// SetCommonOptions specifies the options shared by all adapters. The message
+// and level are stored in the "msg" and "level" fields by default. As logs are
+// buffered until synced, the core never drops logs and the options concerning
+// a full buffer are ignored.
+func SetCommonOptions(options ...common.Option) Option {
+	return func(ws *WriteSyncer) error {
+		ws.commonOptions = options
+		return nil
+	}
+}
+
 // WriteSyncer implements a [zapcore.WriteSyncer] used for shipping logs to
 // Axiom.
 type WriteSyncer struct {
@@ -95,7 +109,9 @@ type WriteSyncer struct {
 
 	clientOptions []axiom.Option
 	ingestOptions []ingest.Option
+	commonOptions []common.Option
 	levelEnabler  zapcore.LevelEnabler
+	encoder       zapcore.Encoder
 
 	buf    bytes.Buffer
 	bufMtx sync.Mutex
@@ -114,6 +130,18 @@ type WriteSyncer struct {
 //
 // An API token with "ingest" permission is sufficient enough.
 func New(options ...Option) (zapcore.Core, error) {
+	ws, err := NewWriteSyncer(options...)
+	if err != nil {
+		return nil, err
+	}
+	return ws.Core(), nil
+}
+
+// NewWriteSyncer creates a new [WriteSyncer] configured like [New]. Use it
+// instead of [New] to keep access to the [WriteSyncer], e.g. to call
+// [WriteSyncer.Shutdown]. The [zapcore.Core] is created using
+// [WriteSyncer.Core].
+func NewWriteSyncer(options ...Option) (*WriteSyncer, error) {
 	ws := &WriteSyncer{
 		levelEnabler: zap.LevelEnablerFunc(func(lvl zapcore.Level) bool {
 			return true
@@ -144,9 +172,41 @@ func New(options ...Option) (zapcore.Core, error) {
 		}
 	}
 
-	enc := zapcore.NewJSONEncoder(encoderConfig)
+	config := common.Config{
+		MessageField: encoderConfig.MessageKey,
+		LevelField:   encoderConfig.LevelKey,
+		TimeField:    encoderConfig.TimeKey,
+	}.Apply(ws.commonOptions...)
+	ws.ingestOptions = config.IngestOptions(ws.ingestOptions...)
+
+	// Copy the encoder config to not modify it for other cores.
+	encCfg := encoderConfig
+	encCfg.MessageKey = config.MessageField
+	encCfg.LevelKey = config.LevelField
+	encCfg.TimeKey = config.TimeField
+
+	ws.encoder = zapcore.NewJSONEncoder(encCfg)
 
-	return zapcore.NewCore(enc, ws, ws.levelEnabler), nil
+	return ws, nil
+}
+
+// NewCore creates a new [zapcore.Core] that ingests logs into the given dataset
+// using the given client. It is a shorthand for calling [New] with the
+// [SetClient] and [SetDataset] options, followed by any additional options.
+//
+// Logs are buffered in memory and only ingested when the logger is synced, so
+// make sure to call [zap.Logger.Sync] periodically and before the application
+// exits.
+func NewCore(client *axiom.Client, dataset string, options ...Option) (zapcore.Core, error) {
+	return New(append([]Option{
+		SetClient(client),
+		SetDataset(dataset),
+	}, options...)...)
+}
+
+// Core returns a new [zapcore.Core] that writes to the [WriteSyncer].
+func (ws *WriteSyncer) Core() zapcore.Core {
+	return zapcore.NewCore(ws.encoder.Clone(), ws, ws.levelEnabler)
 }
 
 // Write implements [zapcore.WriteSyncer].
@@ -157,12 +217,21 @@ func (ws *WriteSyncer) Write(p []byte) (n int, err error) {
 	return ws.buf.Write(p)
 }
 
-// Sync implements [zapcore.WriteSyncer].
+// Sync implements [zapcore.WriteSyncer]. As it doesn't take a context, it gives
+// up after 15 seconds. Use [WriteSyncer.Shutdown] to control the deadline.
 func (ws *WriteSyncer) Sync() error {
 	// Best effort context timeout. A sync should never take that long.
 	ctx, cancel := context.WithTimeout(context.Background(), time.Second*15)
 	defer cancel()
 
+	return ws.Shutdown(ctx)
+}
+
+// Shutdown ingests the buffered logs like [WriteSyncer.Sync] but gives up when
+// the given context is done. In that case, a [*common.DrainError] carrying the
+// amount of dropped logs is returned. The [WriteSyncer] stays usable, so it
+// can also be used to sync with a custom deadline.
+func (ws *WriteSyncer) Shutdown(ctx context.Context) error {
 	ws.bufMtx.Lock()
 	defer ws.bufMtx.Unlock()
 
@@ -173,13 +242,20 @@ func (ws *WriteSyncer) Sync() error {
 	// Make sure to reset the buffer.
 	defer ws.buf.Reset()
 
+	lines := bytes.Count(ws.buf.Bytes(), []byte("\n"))
+
 	r, err := axiom.ZstdEncoder()(&ws.buf)
 	if err != nil {
 		return err
 	}
 
 	res, err := ws.client.Ingest(ctx, ws.datasetName, r, axiom.NDJSON, axiom.Zstd, ws.ingestOptions...)
-	if err != nil {
+	if err != nil && ctx.Err() != nil {
+		return &common.DrainError{
+			Dropped: uint64(lines),
+			Err:     context.Cause(ctx),
+		}
+	} else if err != nil {
 		return err
 	} else if res.Failed > 0 {
 		// Best effort on notifying the user about the ingest failure.
@@ -1,6 +1,8 @@
 package zap
 
 import (
+	"bytes"
+	"context"
 	"fmt"
 	"io"
 	"net/http"
@@ -12,6 +14,7 @@ import (
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
 
+	"github.com/axiomhq/axiom-go/adapters/common"
 	"github.com/axiomhq/axiom-go/axiom"
 	"github.com/axiomhq/axiom-go/axiom/ingest"
 	"github.com/axiomhq/axiom-go/internal/test/adapters"
@@ -86,3 +89,128 @@ func TestCore(t *testing.T) {
 
 	assert.True(t, hasRun)
 }
+
+func TestNewCore(t *testing.T) {
+	var lines int
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		b, err := io.ReadAll(zsr)
+		require.NoError(t, err)
+
+		lines += bytes.Count(b, []byte("\n"))
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	logger, _ := adapters.Setup(t, hf, func(dataset string, client *axiom.Client) (*zap.Logger, func()) {
+		t.Helper()
+
+		core, err := NewCore(client, dataset, SetLevelEnabler(zap.WarnLevel))
+		require.NoError(t, err)
+
+		return zap.New(core), func() {}
+	})
+
+	logger.Info("my message")
+	logger.Warn("my message")
+
+	// Nothing is sent until the logger is synced.
+	assert.Zero(t, lines)
+
+	require.NoError(t, logger.Sync())
+
+	assert.Equal(t, 1, lines)
+}
+
+func TestCore_CommonOptions(t *testing.T) {
+	now := time.Now()
+
+	exp := fmt.Sprintf(`{"ts":"%s","severity":"info","key":"value","message":"my message"}`,
+		now.Format(time.RFC3339Nano))
+
+	hasRun := false
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "ts", r.URL.Query().Get("timestamp-field"))
+
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		b, err := io.ReadAll(zsr)
+		require.NoError(t, err)
+
+		assert.JSONEq(t, exp, string(b))
+
+		hasRun = true
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	logger, _ := adapters.Setup(t, hf, func(dataset string, client *axiom.Client) (*zap.Logger, func()) {
+		t.Helper()
+
+		core, err := NewCore(client, dataset, SetCommonOptions(
+			common.WithMessageField("message"),
+			common.WithLevelField("severity"),
+			common.WithTimeField("ts"),
+		))
+		require.NoError(t, err)
+
+		return zap.New(core), func() {}
+	})
+
+	// Timestamp field is set manually to make the JSONEq assertion pass.
+	logger.Info("my message",
+		zap.String("key", "value"),
+		zap.Time("ts", now),
+	)
+
+	require.NoError(t, logger.Sync())
+
+	assert.True(t, hasRun)
+
+	// The default encoder config must not be modified.
+	assert.Equal(t, "msg", encoderConfig.MessageKey)
+}
+
+func TestWriteSyncer_Shutdown(t *testing.T) {
+	releaseCh := make(chan struct{})
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		// Don't acknowledge the ingestion until the test is done.
+		select {
+		case <-r.Context().Done():
+		case <-releaseCh:
+		}
+	}
+
+	var ws *WriteSyncer
+	logger, _ := adapters.Setup(t, hf, func(dataset string, client *axiom.Client) (*zap.Logger, func()) {
+		t.Helper()
+
+		var err error
+		ws, err = NewWriteSyncer(SetClient(client), SetDataset(dataset))
+		require.NoError(t, err)
+
+		return zap.New(ws.Core()), func() {}
+	})
+	t.Cleanup(func() { close(releaseCh) })
+
+	for i := 0; i < 3; i++ {
+		logger.Info("my message")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+
+	start := time.Now()
+	err := ws.Shutdown(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.Less(t, time.Since(start), time.Second)
+
+	var drainErr *common.DrainError
+	require.ErrorAs(t, err, &drainErr)
+	assert.EqualValues(t, 3, drainErr.Dropped)
+}
@@ -0,0 +1,3 @@
+// Package zerolog provides an adapter for the popular github.com/rs/zerolog
+// logging library.
+package zerolog
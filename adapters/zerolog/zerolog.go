@@ -0,0 +1,393 @@
+package zerolog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/axiomhq/axiom-go/adapters/common"
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/axiom/ingest"
+)
+
+var _ io.WriteCloser = (*Writer)(nil)
+
+const (
+	defaultBatchSize      = 1000
+	defaultBufferSize     = 10000
+	defaultFlushInterval  = time.Second
+	defaultTimestampField = "time"
+)
+
+// ErrMissingDatasetName is raised when a dataset name is not provided. Set it
+// manually using the [SetDataset] option or export "AXIOM_DATASET".
+var ErrMissingDatasetName = errors.New("missing dataset name")
+
+// ErrWriterClosed is returned when writing to a closed [Writer].
+var ErrWriterClosed = errors.New("writer closed")
+
+// An Option modifies the behaviour of the Axiom writer.
+type Option func(*Writer) error
+
+// SetClient specifies the Axiom client to use for ingesting the logs.
+func SetClient(client *axiom.Client) Option {
+	return func(w *Writer) error {
+		w.client = client
+		return nil
+	}
+}
+
+// SetClientOptions specifies the Axiom client options to pass to
+// [axiom.NewClient] which is only called if no [axiom.Client] was specified by
+// the [SetClient] option.
+func SetClientOptions(options ...axiom.Option) Option {
+	return func(w *Writer) error {
+		w.clientOptions = options
+		return nil
+	}
+}
+
+// SetDataset specifies the dataset to ingest the logs into. Can also be
+// specified using the "AXIOM_DATASET" environment variable.
+func SetDataset(datasetName string) Option {
+	return func(w *Writer) error {
+		w.datasetName = datasetName
+		return nil
+	}
+}
+
+// SetIngestOptions specifies the ingestion options to use for ingesting the
+// logs. Logs are ingested using [axiom.DatasetsService.Ingest], so options that
+// are resolved on the client, like [ingest.SetFieldRedactor], are not
+// supported and make ingestion fail with an [axiom.ErrUnsupportedIngestOption].
+func SetIngestOptions(opts ...ingest.Option) Option {
+	return func(w *Writer) error {
+		w.ingestOptions = opts
+		return nil
+	}
+}
+
+// SetTimestampField specifies the field zerolog writes the timestamp of a log
+// event to. Defaults to "time" which matches zerolog's default
+// "zerolog.TimestampFieldName".
+func SetTimestampField(field string) Option {
+	return func(w *Writer) error {
+		w.timestampField = field
+		return nil
+	}
+}
+
+// SetBatchSize specifies the amount of log events that are ingested together.
+// A batch is ingested as soon as it is full. Defaults to 1000.
+func SetBatchSize(size int) Option {
+	return func(w *Writer) error {
+		if size < 1 {
+			return errors.New("batch size must be positive")
+		}
+		w.batchSize = size
+		return nil
+	}
+}
+
+// SetBufferSize specifies the maximum amount of log events buffered by the
+// writer until they are ingested. If it is smaller than the batch size, the
+// batch size is used instead. Defaults to 10000.
+func SetBufferSize(size int) Option {
+	return func(w *Writer) error {
+		if size < 1 {
+			return errors.New("buffer size must be positive")
+		}
+		w.bufferSize = size
+		return nil
+	}
+}
+
+// SetFlushInterval specifies the interval at which the collected log events
+// are ingested, if the batch isn't full before. Defaults to one second.
+func SetFlushInterval(interval time.Duration) Option {
+	return func(w *Writer) error {
+		if interval <= 0 {
+			return errors.New("flush interval must be positive")
+		}
+		w.flushInterval = interval
+		return nil
+	}
+}
+
+// SetCommonOptions specifies the options shared by all adapters. As log events
+// are passed on as is, the time field acts like [SetTimestampField]. The
+// message and level fields are not honored, configure them using
+// "zerolog.MessageFieldName" and "zerolog.LevelFieldName" instead. The writer
+// blocks when its buffer is full by default.
+func SetCommonOptions(options ...common.Option) Option {
+	return func(w *Writer) error {
+		w.commonOptions = options
+		return nil
+	}
+}
+
+// Writer implements an [io.Writer] used for shipping logs written by zerolog
+// to Axiom. Every call to [Writer.Write] is expected to carry exactly one log
+// event encoded as JSON, which is what zerolog does. The log events are not
+// parsed but passed on as is. The timestamp is extracted from the configured
+// timestamp field by the server.
+type Writer struct {
+	client      *axiom.Client
+	datasetName string
+
+	clientOptions  []axiom.Option
+	ingestOptions  []ingest.Option
+	commonOptions  []common.Option
+	timestampField string
+	batchSize      int
+	bufferSize     int
+	flushInterval  time.Duration
+
+	config  common.Config
+	dropped atomic.Uint64
+
+	buf    bytes.Buffer
+	lines  int
+	closed bool
+	bufMtx sync.Mutex
+	// bufCond is signaled when room in the buffer frees up or the writer is
+	// closed.
+	bufCond *sync.Cond
+
+	// written is the amount of log events accepted by the writer, processed
+	// the amount of log events the server has acknowledged.
+	written   atomic.Uint64
+	processed uint64
+
+	ctx       context.Context
+	cancel    context.CancelFunc
+	flushCh   chan struct{}
+	closeCh   chan struct{}
+	doneCh    chan struct{}
+	closeOnce sync.Once
+}
+
+// New creates a new writer that ingests logs into Axiom. It automatically
+// takes its configuration from the environment. To connect, export the
+// following environment variables:
+//
+//   - AXIOM_TOKEN
+//   - AXIOM_ORG_ID (only when using a personal token)
+//   - AXIOM_DATASET
+//
+// The configuration can be set manually using options which are prefixed with
+// "Set".
+//
+// An API token with "ingest" permission is sufficient enough.
+//
+// A writer needs to be closed properly to make sure all logs are sent by
+// calling [Writer.Close].
+func New(options ...Option) (*Writer, error) {
+	w := &Writer{
+		timestampField: defaultTimestampField,
+		batchSize:      defaultBatchSize,
+		bufferSize:     defaultBufferSize,
+		flushInterval:  defaultFlushInterval,
+
+		flushCh: make(chan struct{}, 1),
+		closeCh: make(chan struct{}),
+		doneCh:  make(chan struct{}),
+	}
+	w.bufCond = sync.NewCond(&w.bufMtx)
+
+	// Apply supplied options.
+	for _, option := range options {
+		if option == nil {
+			continue
+		} else if err := option(w); err != nil {
+			return nil, err
+		}
+	}
+
+	// Create client, if not set.
+	if w.client == nil {
+		var err error
+		if w.client, err = axiom.NewClient(w.clientOptions...); err != nil {
+			return nil, err
+		}
+	}
+
+	// When the dataset name is not set, use "AXIOM_DATASET".
+	if w.datasetName == "" {
+		if w.datasetName = os.Getenv("AXIOM_DATASET"); w.datasetName == "" {
+			return nil, ErrMissingDatasetName
+		}
+	}
+
+	w.config = common.Config{
+		TimeField:   w.timestampField,
+		BlockOnFull: true,
+	}.Apply(w.commonOptions...)
+	w.timestampField = w.config.TimeField
+
+	if w.bufferSize < w.batchSize {
+		w.bufferSize = w.batchSize
+	}
+
+	// The timestamp field is always set first, so explicitly configured
+	// ingest options take precedence.
+	w.ingestOptions = append([]ingest.Option{
+		ingest.SetTimestampField(w.timestampField),
+	}, w.ingestOptions...)
+
+	// Run background ingest.
+	w.ctx, w.cancel = context.WithCancel(context.Background())
+	go w.run()
+
+	return w, nil
+}
+
+// Write implements [io.Writer]. The log event is buffered and ingested with
+// the next batch. If the buffer is full, it blocks until there is room or drops
+// the log event, if blocking is disabled by passing [common.WithBlockOnFull] to
+// the [SetCommonOptions] option. A dropped log event is not reported as an
+// error, so zerolog doesn't complain about it.
+func (w *Writer) Write(p []byte) (int, error) {
+	w.bufMtx.Lock()
+	for !w.closed && w.lines >= w.bufferSize {
+		if !w.config.BlockOnFull {
+			w.bufMtx.Unlock()
+			w.dropped.Add(1)
+			w.config.Drop(1)
+			return len(p), nil
+		}
+		w.bufCond.Wait()
+	}
+	if w.closed {
+		w.bufMtx.Unlock()
+		return 0, ErrWriterClosed
+	}
+	n, _ := w.buf.Write(p)
+	if len(p) > 0 && p[len(p)-1] != '\n' {
+		w.buf.WriteByte('\n')
+	}
+	w.lines++
+	w.written.Add(1)
+	full := w.lines >= w.batchSize
+	w.bufMtx.Unlock()
+
+	// Signal a full batch without blocking the caller.
+	if full {
+		select {
+		case w.flushCh <- struct{}{}:
+		default:
+		}
+	}
+
+	return n, nil
+}
+
+// Close the writer and make sure all buffered log events are flushed. Closing
+// the writer renders it unusable for further use.
+func (w *Writer) Close() error {
+	return w.Shutdown(context.Background())
+}
+
+// Shutdown closes the writer like [Writer.Close] but stops waiting for the log
+// events to be flushed when the context is done. In that case, ingestion is
+// aborted and a [*common.DrainError] carrying the amount of dropped log events
+// is returned.
+func (w *Writer) Shutdown(ctx context.Context) error {
+	w.closeOnce.Do(func() {
+		// Reject further writes before the final flush, so no log event is
+		// left behind in the buffer.
+		w.bufMtx.Lock()
+		w.closed = true
+		w.bufCond.Broadcast()
+		w.bufMtx.Unlock()
+
+		close(w.closeCh)
+	})
+
+	if err := common.Drain(ctx, w.doneCh, w.cancel); err != nil {
+		return &common.DrainError{
+			Dropped: w.written.Load() - w.processed,
+			Err:     err,
+		}
+	}
+
+	return nil
+}
+
+// DroppedCount returns the amount of log events dropped because the buffer was
+// full. Log events are only dropped if blocking is disabled by passing
+// [common.WithBlockOnFull] to the [SetCommonOptions] option.
+func (w *Writer) DroppedCount() uint64 {
+	return w.dropped.Load()
+}
+
+func (w *Writer) run() {
+	defer close(w.doneCh)
+	defer w.cancel()
+
+	logger := log.New(os.Stderr, "[AXIOM|ZEROLOG]", 0)
+
+	t := time.NewTicker(w.flushInterval)
+	defer t.Stop()
+
+	flush := func() {
+		if err := w.flush(); err != nil {
+			logger.Printf("failed to ingest events: %s\n", err)
+		}
+	}
+
+	for {
+		select {
+		case <-w.closeCh:
+			flush()
+			return
+		case <-w.flushCh:
+			flush()
+		case <-t.C:
+			flush()
+		}
+	}
+}
+
+func (w *Writer) flush() error {
+	w.bufMtx.Lock()
+	if w.lines == 0 {
+		w.bufMtx.Unlock()
+		return nil
+	}
+	b := bytes.Clone(w.buf.Bytes())
+	w.buf.Reset()
+	w.lines = 0
+	w.bufCond.Broadcast()
+	w.bufMtx.Unlock()
+
+	// Best effort context timeout. A flush should never take that long.
+	ctx, cancel := context.WithTimeout(w.ctx, time.Second*15)
+	defer cancel()
+
+	r, err := axiom.ZstdEncoder()(bytes.NewReader(b))
+	if err != nil {
+		return err
+	}
+
+	res, err := w.client.Ingest(ctx, w.datasetName, r, axiom.NDJSON, axiom.Zstd, w.ingestOptions...)
+	if err != nil {
+		return err
+	}
+	w.processed += res.Ingested + res.Failed
+
+	if res.Failed > 0 {
+		// Best effort on notifying the user about the ingest failure.
+		return fmt.Errorf("event at %s failed to ingest: %s",
+			res.Failures[0].Timestamp, res.Failures[0].Error)
+	}
+
+	return nil
+}
@@ -0,0 +1,30 @@
+package zerolog_test
+
+import (
+	"fmt"
+	"log"
+
+	adapter "github.com/axiomhq/axiom-go/adapters/zerolog"
+)
+
+func Example() {
+	// Export "AXIOM_DATASET" in addition to the required environment variables.
+
+	writer, err := adapter.New()
+	if err != nil {
+		log.Fatal(err)
+	}
+	defer func() {
+		if closeErr := writer.Close(); closeErr != nil {
+			log.Fatal(closeErr)
+		}
+	}()
+
+	// Usually, the writer is passed to zerolog:
+	//
+	//	logger := zerolog.New(writer).With().Timestamp().Logger()
+	//	logger.Info().Str("mood", "hyped").Msg("This is awesome!")
+	//
+	// which writes log events like this one:
+	fmt.Fprintln(writer, `{"level":"info","mood":"hyped","time":"2023-03-01T12:00:00Z","message":"This is awesome!"}`)
+}
@@ -0,0 +1,38 @@
+//go:build integration
+
+package zerolog_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	adapter "github.com/axiomhq/axiom-go/adapters/zerolog"
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/internal/test/adapters"
+)
+
+func Test(t *testing.T) {
+	adapters.IntegrationTest(t, "zerolog", func(_ context.Context, dataset string, client *axiom.Client) {
+		writer, err := adapter.New(
+			adapter.SetClient(client),
+			adapter.SetDataset(dataset),
+		)
+		require.NoError(t, err)
+
+		defer func() {
+			err := writer.Close()
+			assert.NoError(t, err)
+		}()
+
+		// This is what zerolog writes for a log event.
+		now := time.Now().Format(time.RFC3339)
+		fmt.Fprintf(writer, `{"level":"info","mood":"hyped","time":%q,"message":"This is awesome!"}`+"\n", now)
+		fmt.Fprintf(writer, `{"level":"warn","mood":"worried","time":%q,"message":"This is no that awesome..."}`+"\n", now)
+		fmt.Fprintf(writer, `{"level":"error","mood":"depressed","time":%q,"message":"This is rather bad."}`+"\n", now)
+	})
+}
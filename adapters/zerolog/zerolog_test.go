@@ -0,0 +1,337 @@
+package zerolog
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/axiomhq/axiom-go/adapters/common"
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/internal/test/adapters"
+	"github.com/axiomhq/axiom-go/internal/test/testhelper"
+)
+
+// TestNew makes sure New() picks up the "AXIOM_DATASET" environment variable.
+func TestNew(t *testing.T) {
+	testhelper.SafeClearEnv(t)
+
+	t.Setenv("AXIOM_TOKEN", "xaat-test")
+	t.Setenv("AXIOM_ORG_ID", "123")
+
+	writer, err := New()
+	require.ErrorIs(t, err, ErrMissingDatasetName)
+	require.Nil(t, writer)
+
+	t.Setenv("AXIOM_DATASET", "test")
+
+	writer, err = New()
+	require.NoError(t, err)
+	require.NotNil(t, writer)
+	t.Cleanup(func() { _ = writer.Close() })
+
+	assert.Equal(t, "test", writer.datasetName)
+}
+
+func TestWriter(t *testing.T) {
+	exp := `{"level":"info","key":"value","time":"2023-03-01T12:00:00Z","message":"my message"}`
+
+	var lines uint64
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "time", r.URL.Query().Get("timestamp-field"))
+
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		s := bufio.NewScanner(zsr)
+		for s.Scan() {
+			// The log event must be passed on as is.
+			assert.Equal(t, exp, s.Text())
+			atomic.AddUint64(&lines, 1)
+		}
+		assert.NoError(t, s.Err())
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	writer, closeWriter := adapters.Setup(t, hf, setup(t))
+
+	// zerolog terminates every log event with a newline but a missing one must
+	// not corrupt the batch.
+	_, err := fmt.Fprintln(writer, exp)
+	require.NoError(t, err)
+	_, err = fmt.Fprint(writer, exp)
+	require.NoError(t, err)
+
+	closeWriter()
+
+	assert.EqualValues(t, 2, atomic.LoadUint64(&lines))
+}
+
+func TestWriter_TimestampField(t *testing.T) {
+	var hasRun uint64
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "ts", r.URL.Query().Get("timestamp-field"))
+
+		atomic.AddUint64(&hasRun, 1)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	writer, closeWriter := adapters.Setup(t, hf, setup(t, SetTimestampField("ts")))
+
+	_, err := fmt.Fprintln(writer, `{"level":"info","ts":1677672000,"message":"my message"}`)
+	require.NoError(t, err)
+
+	closeWriter()
+
+	assert.EqualValues(t, 1, atomic.LoadUint64(&hasRun))
+}
+
+func TestWriter_CommonOptions(t *testing.T) {
+	var hasRun uint64
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "ts", r.URL.Query().Get("timestamp-field"))
+
+		atomic.AddUint64(&hasRun, 1)
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	writer, closeWriter := adapters.Setup(t, hf, setup(t, SetCommonOptions(common.WithTimeField("ts"))))
+
+	_, err := fmt.Fprintln(writer, `{"level":"info","ts":1677672000,"message":"my message"}`)
+	require.NoError(t, err)
+
+	closeWriter()
+
+	assert.EqualValues(t, 1, atomic.LoadUint64(&hasRun))
+}
+
+func TestWriter_FlushFullBatch(t *testing.T) {
+	var lines uint64
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		s := bufio.NewScanner(zsr)
+		for s.Scan() {
+			atomic.AddUint64(&lines, 1)
+		}
+		assert.NoError(t, s.Err())
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	writer, _ := adapters.Setup(t, hf, setup(t,
+		SetBatchSize(10),
+		SetFlushInterval(time.Hour),
+	))
+
+	for i := 0; i < 10; i++ {
+		_, err := fmt.Fprintln(writer, `{"level":"info","message":"my message"}`)
+		require.NoError(t, err)
+	}
+
+	// Let the server process.
+	time.Sleep(time.Millisecond * 250)
+
+	// Should have a full batch right away, long before the flush interval.
+	assert.EqualValues(t, 10, atomic.LoadUint64(&lines))
+}
+
+func TestWriter_FlushInterval(t *testing.T) {
+	var lines uint64
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		s := bufio.NewScanner(zsr)
+		for s.Scan() {
+			atomic.AddUint64(&lines, 1)
+		}
+		assert.NoError(t, s.Err())
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	writer, _ := adapters.Setup(t, hf, setup(t, SetFlushInterval(time.Millisecond*50)))
+
+	_, err := fmt.Fprintln(writer, `{"level":"info","message":"my message"}`)
+	require.NoError(t, err)
+
+	// Wait for the timer based flush.
+	time.Sleep(time.Millisecond * 250)
+
+	assert.EqualValues(t, 1, atomic.LoadUint64(&lines))
+}
+
+func TestWriter_DropOnFull(t *testing.T) {
+	var lines uint64
+	releaseCh := make(chan struct{})
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		// Block ingestion until all log events are written.
+		<-releaseCh
+
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		s := bufio.NewScanner(zsr)
+		for s.Scan() {
+			atomic.AddUint64(&lines, 1)
+		}
+		assert.NoError(t, s.Err())
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	var onDrop atomic.Int64
+	writer, closeWriter := adapters.Setup(t, hf, setup(t,
+		SetBatchSize(1),
+		SetBufferSize(1),
+		SetCommonOptions(
+			common.WithBlockOnFull(false),
+			common.WithOnDrop(func(n int) { onDrop.Add(int64(n)) }),
+		),
+	))
+
+	// At most one log event is in flight and one is buffered, all others must
+	// be dropped without blocking.
+	for i := 0; i < 10; i++ {
+		_, err := fmt.Fprintln(writer, `{"level":"info","message":"my message"}`)
+		require.NoError(t, err)
+	}
+
+	assert.GreaterOrEqual(t, writer.DroppedCount(), uint64(8))
+	assert.EqualValues(t, writer.DroppedCount(), onDrop.Load())
+
+	close(releaseCh)
+	closeWriter()
+
+	assert.EqualValues(t, 10-writer.DroppedCount(), atomic.LoadUint64(&lines))
+}
+
+func TestWriter_BlockOnFull(t *testing.T) {
+	var lines uint64
+	releaseCh := make(chan struct{})
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		// Block ingestion until released.
+		<-releaseCh
+
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		s := bufio.NewScanner(zsr)
+		for s.Scan() {
+			atomic.AddUint64(&lines, 1)
+		}
+		assert.NoError(t, s.Err())
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	writer, closeWriter := adapters.Setup(t, hf, setup(t,
+		SetBatchSize(1),
+		SetBufferSize(1),
+	))
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 10; i++ {
+			_, err := fmt.Fprintln(writer, `{"level":"info","message":"my message"}`)
+			assert.NoError(t, err)
+		}
+	}()
+
+	// Writing blocks while the buffer is full.
+	select {
+	case <-done:
+		t.Fatal("writes didn't block on a full buffer")
+	case <-time.After(time.Millisecond * 100):
+	}
+
+	close(releaseCh)
+	<-done
+	closeWriter()
+
+	assert.Zero(t, writer.DroppedCount())
+	assert.EqualValues(t, 10, atomic.LoadUint64(&lines))
+}
+
+func TestWriter_WriteAfterClose(t *testing.T) {
+	hf := func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte("{}"))
+	}
+
+	writer, closeWriter := adapters.Setup(t, hf, setup(t))
+
+	closeWriter()
+
+	_, err := fmt.Fprintln(writer, `{"level":"info","message":"my message"}`)
+	assert.ErrorIs(t, err, ErrWriterClosed)
+}
+
+func TestWriter_Shutdown(t *testing.T) {
+	releaseCh := make(chan struct{})
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		// Don't acknowledge the ingestion until the test is done.
+		select {
+		case <-r.Context().Done():
+		case <-releaseCh:
+		}
+	}
+
+	writer, _ := adapters.Setup(t, hf, setup(t))
+	t.Cleanup(func() { close(releaseCh) })
+
+	for i := 0; i < 3; i++ {
+		_, err := fmt.Fprintln(writer, `{"level":"info","message":"my message"}`)
+		require.NoError(t, err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*100)
+	defer cancel()
+
+	err := writer.Shutdown(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	var drainErr *common.DrainError
+	require.ErrorAs(t, err, &drainErr)
+	assert.EqualValues(t, 3, drainErr.Dropped)
+}
+
+func setup(t *testing.T, options ...Option) func(dataset string, client *axiom.Client) (*Writer, func()) {
+	return func(dataset string, client *axiom.Client) (*Writer, func()) {
+		t.Helper()
+
+		writer, err := New(append([]Option{
+			SetClient(client),
+			SetDataset(dataset),
+		}, options...)...)
+		require.NoError(t, err)
+
+		closeWriter := func() {
+			err := writer.Close()
+			require.NoError(t, err)
+		}
+		t.Cleanup(closeWriter)
+
+		return writer, closeWriter
+	}
+}
@@ -0,0 +1,171 @@
+package axiom
+
+import "strings"
+
+// aplTokenKind is the kind of an [aplToken].
+type aplTokenKind uint8
+
+const (
+	// aplPunct is a single punctuation character, e.g. "|".
+	aplPunct aplTokenKind = iota
+	// aplIdent is a bare identifier, e.g. "logs".
+	aplIdent
+	// aplRef is a quoted reference to an entity, e.g. "['logs']".
+	aplRef
+	// aplOther is any other token, like a string or number literal.
+	aplOther
+)
+
+// aplToken is a token of an APL query, as returned by [tokenizeAPL]. Start and
+// end are the byte offsets of the token in the query. Text is the name of an
+// identifier or reference or the character of a punctuation token.
+type aplToken struct {
+	kind       aplTokenKind
+	start, end int
+	text       string
+}
+
+func (t aplToken) is(punct string) bool {
+	return t.kind == aplPunct && t.text == punct
+}
+
+var aplUnescaper = strings.NewReplacer(`\\`, `\`, `\'`, `'`, `\"`, `"`)
+
+// tokenizeAPL splits the given APL query into tokens, as far as needed to find
+// the dataset references in it. Whitespace and comments are skipped.
+func tokenizeAPL(apl string) []aplToken {
+	var tokens []aplToken
+	for i := 0; i < len(apl); {
+		c := apl[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			i++
+		case strings.HasPrefix(apl[i:], "//"):
+			if n := strings.IndexByte(apl[i:], '\n'); n >= 0 {
+				i += n
+			} else {
+				i = len(apl)
+			}
+		case c == '[' && i+1 < len(apl) && (apl[i+1] == '\'' || apl[i+1] == '"'):
+			end := aplStringEnd(apl, i+1)
+			if end < len(apl) && apl[end] == ']' {
+				tokens = append(tokens, aplToken{aplRef, i, end + 1, aplUnescaper.Replace(apl[i+2 : end-1])})
+				i = end + 1
+			} else {
+				tokens = append(tokens, aplToken{aplPunct, i, i + 1, "["})
+				i++
+			}
+		case c == '\'' || c == '"':
+			end := aplStringEnd(apl, i)
+			tokens = append(tokens, aplToken{aplOther, i, end, apl[i:end]})
+			i = end
+		case isAPLIdentChar(c):
+			end := i + 1
+			for end < len(apl) && isAPLIdentChar(apl[end]) {
+				end++
+			}
+			kind := aplIdent
+			if c >= '0' && c <= '9' {
+				kind = aplOther
+			}
+			tokens = append(tokens, aplToken{kind, i, end, apl[i:end]})
+			i = end
+		default:
+			tokens = append(tokens, aplToken{aplPunct, i, i + 1, apl[i : i+1]})
+			i++
+		}
+	}
+	return tokens
+}
+
+// aplStringEnd returns the offset following the string literal that starts
+// with the quote at the given offset. It returns the length of the query, if
+// the string literal is not terminated.
+func aplStringEnd(apl string, start int) int {
+	quote := apl[start]
+	for i := start + 1; i < len(apl); i++ {
+		if apl[i] == '\\' {
+			i++
+		} else if apl[i] == quote {
+			return i + 1
+		}
+	}
+	return len(apl)
+}
+
+func isAPLIdentChar(c byte) bool {
+	return c == '_' || c == '$' ||
+		(c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// remapDatasets returns the given APL query with the datasets it references
+// renamed according to the given map. All references are replaced in a single
+// pass, so a dataset mapped to the name of another mapped dataset isn't
+// replaced twice.
+//
+// Quoted references, e.g. "['logs']", are replaced wherever they appear. Bare
+// identifiers, e.g. "logs", are only replaced where a dataset is expected: as
+// the source of a query or subquery, as an operand of "union" and as the right
+// side of "join" and "lookup". Elsewhere, they can't be told apart from field
+// names and are kept as is.
+func remapDatasets(apl string, datasetMap map[string]string) string {
+	var (
+		b    strings.Builder
+		last int
+
+		// source is set, if the next token is expected to be a dataset.
+		source = true
+		// depth is the current nesting level of parentheses and unions is
+		// the set of nesting levels with an open list of union operands.
+		depth  int
+		unions = make(map[int]bool)
+	)
+	replace := func(t aplToken, name string) {
+		b.WriteString(apl[last:t.start])
+		b.WriteString(datasetReference(name))
+		last = t.end
+	}
+
+	tokens := tokenizeAPL(apl)
+	for i := 0; i < len(tokens); i++ {
+		t := tokens[i]
+		isSource := source
+		source = false
+
+		switch {
+		case t.kind == aplRef:
+			if to, ok := datasetMap[t.text]; ok {
+				replace(t, to)
+			}
+		case t.kind == aplIdent && (t.text == "union" || t.text == "join" || t.text == "lookup"):
+			source = true
+			if t.text == "union" {
+				unions[depth] = true
+			}
+		case t.kind == aplIdent && isSource && i+1 < len(tokens) && tokens[i+1].is("="):
+			// A parameter, e.g. "kind=inner", precedes the dataset.
+			source = true
+			i += 2
+		case t.kind == aplIdent && isSource:
+			if to, ok := datasetMap[t.text]; ok {
+				replace(t, to)
+			}
+		case t.is("("):
+			depth++
+			source = isSource
+		case t.is(")"):
+			delete(unions, depth)
+			depth--
+		case t.is(","):
+			source = unions[depth]
+		case t.is("|"):
+			delete(unions, depth)
+		case t.is(";"):
+			source = true
+		}
+	}
+
+	b.WriteString(apl[last:])
+
+	return b.String()
+}
@@ -0,0 +1,78 @@
+package axiom
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemapDatasets(t *testing.T) {
+	datasetMap := map[string]string{
+		"logs":      "prod-logs",
+		"prod-logs": "logs",
+		"traces":    "prod_traces",
+		"it's":      "its",
+	}
+
+	tests := []struct {
+		name string
+		apl  string
+		exp  string
+	}{
+		{
+			name: "quoted reference",
+			apl:  `['logs'] | where status == 500`,
+			exp:  `['prod-logs'] | where status == 500`,
+		},
+		{
+			name: "double quoted reference",
+			apl:  `["logs"] | count`,
+			exp:  `['prod-logs'] | count`,
+		},
+		{
+			name: "escaped reference",
+			apl:  `['it\'s'] | count`,
+			exp:  `['its'] | count`,
+		},
+		{
+			name: "bare source",
+			apl:  `logs | where traces > 1 | project logs`,
+			exp:  `['prod-logs'] | where traces > 1 | project logs`,
+		},
+		{
+			name: "union operands",
+			apl:  `traces | union logs, (['prod-logs'] | where x == 1), other | count`,
+			exp:  `['prod_traces'] | union ['prod-logs'], (['logs'] | where x == 1), other | count`,
+		},
+		{
+			name: "union statement",
+			apl:  `union withsource=src logs, traces`,
+			exp:  `union withsource=src ['prod-logs'], ['prod_traces']`,
+		},
+		{
+			name: "join and lookup",
+			apl:  `logs | join kind=inner (traces | project id) on id | lookup traces on id`,
+			exp:  `['prod-logs'] | join kind=inner (['prod_traces'] | project id) on id | lookup ['prod_traces'] on id`,
+		},
+		{
+			name: "function arguments",
+			apl:  `logs | summarize count(), avg(traces) by bin(logs, 1m)`,
+			exp:  `['prod-logs'] | summarize count(), avg(traces) by bin(logs, 1m)`,
+		},
+		{
+			name: "string literals and comments",
+			apl:  "// logs\nlogs | where msg == \"['logs']\" or msg == 'logs'",
+			exp:  "// logs\n['prod-logs'] | where msg == \"['logs']\" or msg == 'logs'",
+		},
+		{
+			name: "let statement",
+			apl:  `let threshold = 5; logs | where n > threshold`,
+			exp:  `let threshold = 5; ['prod-logs'] | where n > threshold`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.exp, remapDatasets(tt.apl, datasetMap))
+		})
+	}
+}
@@ -0,0 +1,18 @@
+// Package axiomtest provides utilities for testing code that uses the
+// [axiom.Client], without sending requests to a server.
+//
+// Usage:
+//
+//	import (
+//	    "github.com/axiomhq/axiom-go/axiom"
+//	    "github.com/axiomhq/axiom-go/axiom/axiomtest"
+//	)
+//	// ...
+//	client, transport, err := axiomtest.NewReplayClient(map[string]axiomtest.ReplayResponse{
+//	    "GET /v1/datasets/test": {Body: `{"id":"test","name":"test"}`},
+//	})
+//	// ...
+//	dataset, err := client.Datasets.Get(ctx, "test")
+//	// ...
+//	requests := transport.Requests()
+package axiomtest
@@ -0,0 +1,139 @@
+package axiomtest
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/axiomhq/axiom-go/axiom"
+)
+
+// The placeholder credentials used by the client returned by
+// [NewReplayClient]. They are never sent to a server.
+const (
+	replayToken          = "xapt-00000000-0000-0000-0000-000000000000" //nolint:gosec // Not a real token.
+	replayOrganizationID = "axiomtest"
+)
+
+// ReplayResponse is a canned response replied by a [ReplayTransport].
+type ReplayResponse struct {
+	// Status code of the response. Defaults to 200 (OK).
+	Status int
+	// Header of the response. The "Content-Type" header defaults to
+	// "application/json".
+	Header http.Header
+	// Body of the response.
+	Body string
+}
+
+// ReplayTransport is an [http.RoundTripper] that replies to requests with
+// canned responses, without sending them anywhere. Responses are looked up by
+// the method and path of a request, formatted as "METHOD /path", e.g.
+// "GET /v1/datasets". Requests without a matching response are replied with a
+// 404 (Not Found). It is safe for concurrent use.
+type ReplayTransport struct {
+	responses map[string]ReplayResponse
+
+	mtx      sync.Mutex
+	requests []*http.Request
+}
+
+// NewReplayTransport returns a new [ReplayTransport] that replies with the
+// given responses, keyed by the method and path of a request.
+func NewReplayTransport(responses map[string]ReplayResponse) *ReplayTransport {
+	return &ReplayTransport{
+		responses: responses,
+	}
+}
+
+// RoundTrip records the request and replies with the canned response for its
+// method and path.
+//
+// It implements [http.RoundTripper].
+func (t *ReplayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	// Read the request body, so it can be inspected after the request has been
+	// replied to.
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	recorded := req.Clone(req.Context())
+	recorded.Body = io.NopCloser(bytes.NewReader(body))
+
+	t.mtx.Lock()
+	t.requests = append(t.requests, recorded)
+	t.mtx.Unlock()
+
+	key := req.Method + " " + req.URL.Path
+	replay, ok := t.responses[key]
+	if !ok {
+		replay = ReplayResponse{
+			Status: http.StatusNotFound,
+			Body:   fmt.Sprintf(`{"message":"no replay response for %s"}`, key),
+		}
+	}
+
+	status := replay.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	header := replay.Header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	if header.Get("Content-Type") == "" {
+		header.Set("Content-Type", "application/json")
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(replay.Body)),
+		ContentLength: int64(len(replay.Body)),
+		Request:       req,
+	}, nil
+}
+
+// Requests returns the requests received so far, in the order they were
+// received. Their bodies can be read again.
+func (t *ReplayTransport) Requests() []*http.Request {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+
+	return append([]*http.Request(nil), t.requests...)
+}
+
+// NewReplayClient returns an [axiom.Client] that uses a [ReplayTransport]
+// replying with the given responses, together with that transport. The client
+// is configured with a placeholder personal token and organization ID and
+// doesn't read its configuration from the environment. The given options are
+// applied after that and can override the configuration.
+func NewReplayClient(responses map[string]ReplayResponse, options ...axiom.Option) (*axiom.Client, *ReplayTransport, error) {
+	transport := NewReplayTransport(responses)
+
+	client, err := axiom.NewClient(append([]axiom.Option{
+		axiom.SetNoEnv(),
+		axiom.SetToken(replayToken),
+		axiom.SetOrganizationID(replayOrganizationID),
+		axiom.SetTransport(transport),
+	}, options...)...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return client, transport, nil
+}
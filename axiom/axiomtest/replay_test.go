@@ -0,0 +1,48 @@
+package axiomtest_test
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/axiomhq/axiom-go/axiom"
+	"github.com/axiomhq/axiom-go/axiom/axiomtest"
+)
+
+func TestNewReplayClient(t *testing.T) {
+	client, transport, err := axiomtest.NewReplayClient(map[string]axiomtest.ReplayResponse{
+		"GET /v1/datasets/test": {
+			Body: `{"id":"test","name":"test","description":"A test dataset"}`,
+		},
+		"PUT /v1/datasets/test": {
+			Status: http.StatusForbidden,
+			Body:   `{"message":"forbidden"}`,
+		},
+	})
+	require.NoError(t, err)
+
+	dataset, err := client.Datasets.Get(context.Background(), "test")
+	require.NoError(t, err)
+	assert.Equal(t, "A test dataset", dataset.Description)
+
+	_, err = client.Datasets.Update(context.Background(), "test", axiom.DatasetUpdateRequest{
+		Description: "Updated",
+	})
+	assert.True(t, axiom.IsUnauthorized(err))
+
+	_, err = client.Datasets.Get(context.Background(), "unknown")
+	assert.True(t, axiom.IsNotFound(err))
+	assert.ErrorContains(t, err, "no replay response for GET /v1/datasets/unknown")
+
+	requests := transport.Requests()
+	require.Len(t, requests, 3)
+	assert.Equal(t, http.MethodPut, requests[1].Method)
+
+	body, err := io.ReadAll(requests[1].Body)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"description":"Updated"}`, string(body))
+}
@@ -0,0 +1,82 @@
+package axiom
+
+import (
+	"context"
+	"time"
+
+	"github.com/cenkalti/backoff/v4"
+)
+
+//go:generate go run golang.org/x/tools/cmd/stringer -type=Jitter -linecomment -output=backoff_string.go
+
+// Jitter is the algorithm used to randomize the delay between retries of
+// failed requests. It prevents clients that failed at the same time from
+// retrying in lockstep.
+type Jitter uint8
+
+// All available [Jitter] algorithms. The base delay starts at 200ms and doubles
+// with every retry.
+const (
+	// JitterNone uses the base delay as is.
+	JitterNone Jitter = iota + 1 // none
+	// JitterFull uses a random delay between zero and the base delay.
+	JitterFull // full
+	// JitterEqual uses half of the base delay plus a random delay between zero
+	// and the other half.
+	JitterEqual // equal
+)
+
+// jitteredBackOff applies a [Jitter] to the delays of an exponential backoff.
+// It stops, if the next retry would start after the deadline, if any.
+type jitteredBackOff struct {
+	*backoff.ExponentialBackOff
+
+	jitter   Jitter
+	rand     func() float64
+	deadline time.Time
+}
+
+// newBackOff returns the backoff used to retry failed requests. Retries stop
+// once the retry budget is spent or the deadline of the given context would
+// pass before the next retry, whichever comes first.
+func (c *Client) newBackOff(ctx context.Context) backoff.BackOff {
+	bck := backoff.NewExponentialBackOff()
+	bck.InitialInterval = time.Millisecond * 200
+	bck.MaxElapsedTime = defaultRetryBudget
+	if c.retryBudget > 0 {
+		bck.MaxElapsedTime = c.retryBudget
+	}
+	bck.Multiplier = 2.0
+	bck.RandomizationFactor = 0 // Randomization is applied by the jitter.
+	bck.Reset()
+
+	deadline, _ := ctx.Deadline()
+
+	return &jitteredBackOff{
+		ExponentialBackOff: bck,
+
+		jitter:   c.jitter,
+		rand:     c.rand,
+		deadline: deadline,
+	}
+}
+
+// NextBackOff implements [backoff.BackOff].
+func (b *jitteredBackOff) NextBackOff() time.Duration {
+	d := b.ExponentialBackOff.NextBackOff()
+	if d == backoff.Stop {
+		return d
+	}
+
+	switch b.jitter {
+	case JitterFull:
+		d = time.Duration(b.rand() * float64(d))
+	case JitterEqual:
+		d = d/2 + time.Duration(b.rand()*float64(d/2))
+	}
+
+	if !b.deadline.IsZero() && time.Now().Add(d).After(b.deadline) {
+		return backoff.Stop
+	}
+	return d
+}
@@ -0,0 +1,54 @@
+package axiom
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_newBackOff(t *testing.T) {
+	tests := []struct {
+		jitter Jitter
+		exp    []time.Duration
+	}{
+		{
+			jitter: JitterNone,
+			exp:    []time.Duration{200 * time.Millisecond, 400 * time.Millisecond, 800 * time.Millisecond},
+		},
+		{
+			jitter: JitterFull,
+			exp:    []time.Duration{50 * time.Millisecond, 100 * time.Millisecond, 200 * time.Millisecond},
+		},
+		{
+			jitter: JitterEqual,
+			exp:    []time.Duration{125 * time.Millisecond, 250 * time.Millisecond, 500 * time.Millisecond},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.jitter.String(), func(t *testing.T) {
+			client := newClient(t)
+
+			err := client.Options(SetBackoffJitter(tt.jitter))
+			require.NoError(t, err)
+
+			client.rand = func() float64 { return 0.25 }
+
+			bck := client.newBackOff(context.Background())
+			for _, exp := range tt.exp {
+				assert.Equal(t, exp, bck.NextBackOff())
+			}
+		})
+	}
+}
+
+func TestSetBackoffJitter_Invalid(t *testing.T) {
+	client := newClient(t)
+
+	err := client.Options(SetBackoffJitter(0))
+	assert.EqualError(t, err, "invalid backoff jitter: Jitter(0)")
+
+	assert.Equal(t, JitterEqual, client.jitter)
+}
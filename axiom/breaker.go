@@ -0,0 +1,148 @@
+package axiom
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+)
+
+//go:generate go run golang.org/x/tools/cmd/stringer -type=CircuitState -linecomment -output=breaker_string.go
+
+// ErrCircuitOpen is returned when a request is not sent because the circuit
+// breaker configured using [SetCircuitBreaker] is open.
+var ErrCircuitOpen = errors.New("circuit open")
+
+// CircuitState is the state of the circuit breaker configured using
+// [SetCircuitBreaker].
+type CircuitState uint8
+
+// All available [CircuitState]s.
+const (
+	// CircuitClosed lets requests pass.
+	CircuitClosed CircuitState = iota // closed
+	// CircuitOpen rejects requests with an [ErrCircuitOpen] until the cooldown
+	// has passed.
+	CircuitOpen // open
+	// CircuitHalfOpen lets a single probe request pass. If it succeeds, the
+	// circuit is closed, otherwise it is opened again.
+	CircuitHalfOpen // half-open
+)
+
+// circuitBreaker tracks consecutive request failures per host and rejects
+// requests to hosts that failed too often. A nil circuit breaker lets all
+// requests pass.
+type circuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+
+	mtx      sync.Mutex
+	circuits map[string]*circuit
+}
+
+// circuit is the state of the circuit to a single host.
+type circuit struct {
+	failures int
+	openedAt time.Time
+	probing  bool
+}
+
+func newCircuitBreaker(threshold int, cooldown time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		circuits:  make(map[string]*circuit),
+	}
+}
+
+// allow returns an [ErrCircuitOpen] if a request to the given host must not be
+// sent.
+func (b *circuitBreaker) allow(host string, now time.Time) error {
+	if b == nil {
+		return nil
+	}
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	c := b.circuit(host)
+	switch b.state(c, now) {
+	case CircuitOpen:
+		return ErrCircuitOpen
+	case CircuitHalfOpen:
+		if c.probing {
+			return ErrCircuitOpen
+		}
+		c.probing = true
+	}
+	return nil
+}
+
+// record the outcome of a request sent to the host of the request. Transport
+// errors and server errors count as failures. Requests failing because their
+// context was canceled don't count at all.
+func (b *circuitBreaker) record(req *http.Request, resp *http.Response, err error, now time.Time) {
+	if b == nil {
+		return
+	}
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	c := b.circuit(req.URL.Host)
+	wasProbing := c.probing
+	c.probing = false
+
+	switch {
+	case err != nil && req.Context().Err() != nil:
+		// Neither a success nor a failure.
+	case err != nil || resp.StatusCode >= 500:
+		c.failures++
+		if wasProbing || c.failures >= b.threshold {
+			c.openedAt = now
+		}
+	default:
+		c.failures = 0
+		c.openedAt = time.Time{}
+	}
+}
+
+// currentState returns the state of the circuit to the given host.
+func (b *circuitBreaker) currentState(host string, now time.Time) CircuitState {
+	if b == nil {
+		return CircuitClosed
+	}
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+
+	return b.state(b.circuit(host), now)
+}
+
+// state returns the state of the given circuit. The lock must be held.
+func (b *circuitBreaker) state(c *circuit, now time.Time) CircuitState {
+	switch {
+	case c.openedAt.IsZero():
+		return CircuitClosed
+	case now.Sub(c.openedAt) < b.cooldown:
+		return CircuitOpen
+	}
+	return CircuitHalfOpen
+}
+
+// circuit returns the circuit to the given host. The lock must be held.
+func (b *circuitBreaker) circuit(host string) *circuit {
+	c, ok := b.circuits[host]
+	if !ok {
+		c = new(circuit)
+		b.circuits[host] = c
+	}
+	return c
+}
+
+// CircuitState returns the state of the circuit breaker for the Axiom
+// deployment the [Client] is configured for. It is always [CircuitClosed] if
+// no circuit breaker is configured using [SetCircuitBreaker].
+func (c *Client) CircuitState() CircuitState {
+	return c.breaker.currentState(c.config.BaseURL().Host, time.Now())
+}
@@ -0,0 +1,92 @@
+package axiom
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_CircuitBreaker(t *testing.T) {
+	var (
+		calls  atomic.Int64
+		status atomic.Int64
+	)
+	status.Store(http.StatusInternalServerError)
+
+	hf := func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(int(status.Load()))
+	}
+
+	client := setup(t, "/", hf)
+
+	err := client.Options(SetNoRetry(), SetCircuitBreaker(2, time.Millisecond*100))
+	require.NoError(t, err)
+
+	call := func() error {
+		return client.Call(context.Background(), http.MethodGet, "/", nil, nil)
+	}
+
+	// The circuit opens after two consecutive failures.
+	assert.Equal(t, CircuitClosed, client.CircuitState())
+	assert.Error(t, call())
+	assert.Equal(t, CircuitClosed, client.CircuitState())
+	assert.Error(t, call())
+	assert.Equal(t, CircuitOpen, client.CircuitState())
+
+	// While open, requests are not sent.
+	assert.ErrorIs(t, call(), ErrCircuitOpen)
+	assert.EqualValues(t, 2, calls.Load())
+
+	// After the cooldown, a failing probe opens the circuit again.
+	time.Sleep(time.Millisecond * 150)
+	assert.Equal(t, CircuitHalfOpen, client.CircuitState())
+	assert.True(t, IsServerError(call()))
+	assert.Equal(t, CircuitOpen, client.CircuitState())
+	assert.ErrorIs(t, call(), ErrCircuitOpen)
+	assert.EqualValues(t, 3, calls.Load())
+
+	// A successful probe closes it.
+	time.Sleep(time.Millisecond * 150)
+	status.Store(http.StatusNoContent)
+	assert.NoError(t, call())
+	assert.Equal(t, CircuitClosed, client.CircuitState())
+	assert.NoError(t, call())
+	assert.EqualValues(t, 5, calls.Load())
+}
+
+func TestClient_CircuitBreaker_SkipsRetries(t *testing.T) {
+	var calls atomic.Int64
+	hf := func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	client := setup(t, "/", hf)
+
+	err := client.Options(SetCircuitBreaker(2, time.Minute))
+	require.NoError(t, err)
+
+	req, err := client.NewRequest(context.Background(), http.MethodPost, "/", strings.NewReader("{}"))
+	require.NoError(t, err)
+
+	_, err = client.Do(req, nil)
+	assert.ErrorIs(t, err, ErrCircuitOpen)
+	assert.EqualValues(t, 2, calls.Load())
+}
+
+func TestSetCircuitBreaker_Invalid(t *testing.T) {
+	client := newClient(t)
+
+	err := client.Options(SetCircuitBreaker(0, time.Second))
+	assert.EqualError(t, err, "invalid circuit breaker threshold: 0")
+
+	err = client.Options(SetCircuitBreaker(1, 0))
+	assert.EqualError(t, err, "invalid circuit breaker cooldown: 0s")
+}
@@ -0,0 +1,126 @@
+package axiom
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// maxPooledBufferSize is the maximum capacity of a buffer that is returned to
+// the pool. Larger buffers are left to the garbage collector, so a few large
+// requests don't pin their memory.
+const maxPooledBufferSize = 64 << 10
+
+// ingestChunkSize is the amount of encoded events collected in a buffer before
+// they are passed on to the compressor.
+const ingestChunkSize = 32 << 10
+
+// errBodyReleased is returned when the body of a request is requested again
+// after the request has been sent and its buffer was released.
+var errBodyReleased = errors.New("request body already released")
+
+// bufferPool holds the buffers the bodies of requests are encoded into.
+var bufferPool = sync.Pool{
+	New: func() any { return new(bytes.Buffer) },
+}
+
+// zstdEncoderPool holds the encoders the bodies of ingest requests are
+// compressed with.
+var zstdEncoderPool sync.Pool
+
+// getBuffer returns an empty buffer from the [bufferPool].
+func getBuffer() *bytes.Buffer {
+	buf := bufferPool.Get().(*bytes.Buffer)
+	buf.Reset()
+	return buf
+}
+
+// putBuffer returns the given buffer to the [bufferPool], unless it grew too
+// large.
+func putBuffer(buf *bytes.Buffer) {
+	if buf.Cap() <= maxPooledBufferSize {
+		bufferPool.Put(buf)
+	}
+}
+
+// getZstdEncoder returns an encoder from the [zstdEncoderPool] that writes to
+// the given writer.
+func getZstdEncoder(w io.Writer) (*zstd.Encoder, error) {
+	if enc, ok := zstdEncoderPool.Get().(*zstd.Encoder); ok {
+		enc.Reset(w)
+		return enc, nil
+	}
+	return zstd.NewWriter(w)
+}
+
+// putZstdEncoder returns the given encoder to the [zstdEncoderPool]. It must
+// have been closed.
+func putZstdEncoder(enc *zstd.Encoder) {
+	// Drop the reference to the writer.
+	enc.Reset(nil)
+	zstdEncoderPool.Put(enc)
+}
+
+// pooledBuffer is a buffer from the [bufferPool] that holds an encoded request
+// body. It is returned to the pool once the request has been sent by
+// [Client.Do] and all bodies read from it, including the ones of retries, have
+// been closed.
+type pooledBuffer struct {
+	buf *bytes.Buffer
+
+	refs     atomic.Int32
+	released atomic.Bool
+	sent     sync.Once
+}
+
+// newPooledBuffer returns a new [pooledBuffer] with an empty buffer from the
+// pool. It is referenced by the request it is created for.
+func newPooledBuffer() *pooledBuffer {
+	p := &pooledBuffer{buf: getBuffer()}
+	p.refs.Store(1)
+
+	return p
+}
+
+// body returns a new request body that reads the buffer. It references the
+// buffer until it is closed.
+func (p *pooledBuffer) body() (io.ReadCloser, error) {
+	if p.released.Load() {
+		return nil, errBodyReleased
+	}
+	p.refs.Add(1)
+	return &pooledBody{Reader: bytes.NewReader(p.buf.Bytes()), buf: p}, nil
+}
+
+// done drops the reference of the request, once it has been sent.
+func (p *pooledBuffer) done() {
+	p.sent.Do(p.release)
+}
+
+// release drops a reference and returns the buffer to the pool, once it is no
+// longer referenced.
+func (p *pooledBuffer) release() {
+	if p.refs.Add(-1) != 0 {
+		return
+	}
+	p.released.Store(true)
+	putBuffer(p.buf)
+}
+
+// pooledBody is a request body reading a [pooledBuffer].
+type pooledBody struct {
+	*bytes.Reader
+
+	buf   *pooledBuffer
+	close sync.Once
+}
+
+// Close implements [io.Closer].
+func (b *pooledBody) Close() error {
+	b.close.Do(b.buf.release)
+	return nil
+}
@@ -0,0 +1,157 @@
+package axiom
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_do_PooledBuffer(t *testing.T) {
+	var bodies []string
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		bodies = append(bodies, string(b))
+
+		if len(bodies) < 3 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	client := setup(t, "/", hf)
+
+	req, err := client.NewRequest(context.Background(), http.MethodPost, "/", map[string]string{"foo": "bar"})
+	require.NoError(t, err)
+
+	body, ok := req.Body.(*pooledBody)
+	require.True(t, ok)
+
+	_, err = client.Do(req, nil)
+	require.NoError(t, err)
+
+	// Every attempt sends the same body.
+	exp := `{"foo":"bar"}` + "\n"
+	assert.Equal(t, []string{exp, exp, exp}, bodies)
+
+	// The buffer is released once the request has been sent.
+	assert.True(t, body.buf.released.Load())
+	assert.Zero(t, body.buf.refs.Load())
+
+	_, err = req.GetBody()
+	assert.ErrorIs(t, err, errBodyReleased)
+}
+
+func TestPooledBuffer(t *testing.T) {
+	buf := newPooledBuffer()
+	_, _ = buf.buf.WriteString("foo")
+
+	body, err := buf.body()
+	require.NoError(t, err)
+
+	// The buffer is still referenced by the body.
+	buf.done()
+	buf.done()
+	assert.False(t, buf.released.Load())
+
+	b, err := io.ReadAll(body)
+	require.NoError(t, err)
+	assert.Equal(t, "foo", string(b))
+
+	require.NoError(t, body.Close())
+	require.NoError(t, body.Close())
+	assert.True(t, buf.released.Load())
+	assert.Zero(t, buf.refs.Load())
+}
+
+type noopTransport struct{}
+
+func (noopTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_, _ = io.Copy(io.Discard, req.Body)
+		_ = req.Body.Close()
+	}
+	return &http.Response{
+		StatusCode: http.StatusNoContent,
+		Header:     make(http.Header),
+		Body:       http.NoBody,
+		Request:    req,
+	}, nil
+}
+
+func BenchmarkClient_Call(b *testing.B) {
+	client, err := NewClient(
+		SetToken(personalToken),
+		SetOrganizationID(organizationID),
+		SetTransport(noopTransport{}),
+		SetNoEnv(),
+	)
+	require.NoError(b, err)
+
+	body := APITokenCreateRequest{
+		Name:        "benchmark",
+		Description: "A token created by a benchmark",
+		DatasetCapabilities: map[string][]Capability{
+			"test": {CapabilityIngest, CapabilityQuery},
+		},
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := client.Call(context.Background(), http.MethodPost, "/v2/tokens", body, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+// ingestTransport drains the request body and acknowledges the ingestion.
+type ingestTransport struct{}
+
+func (ingestTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil {
+		_, _ = io.Copy(io.Discard, req.Body)
+		_ = req.Body.Close()
+	}
+	header := make(http.Header)
+	header.Set("Content-Type", mediaTypeJSON)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     header,
+		Body:       io.NopCloser(strings.NewReader("{}")),
+		Request:    req,
+	}, nil
+}
+
+func BenchmarkIngest(b *testing.B) {
+	client, err := NewClient(
+		SetToken(apiToken),
+		SetTransport(ingestTransport{}),
+		SetNoEnv(),
+	)
+	require.NoError(b, err)
+
+	events := make([]Event, 1000)
+	for i := range events {
+		events[i] = Event{
+			"_time":   "2024-01-01T00:00:00Z",
+			"message": "This is a log message",
+			"level":   "info",
+			"count":   i,
+		}
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := client.IngestEvents(context.Background(), "test", events); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -3,16 +3,19 @@ package axiom
 import (
 	"bytes"
 	"context"
-	"encoding/json"
+	"crypto/sha256"
+	"crypto/tls"
 	"errors"
 	"fmt"
 	"io"
+	"math/rand"
 	"net"
 	"net/http"
 	"net/http/httptrace"
 	"net/url"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/cenkalti/backoff/v4"
@@ -22,6 +25,7 @@ import (
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
 	"go.opentelemetry.io/otel/trace"
 
 	"github.com/axiomhq/axiom-go/axiom/ingest"
@@ -38,17 +42,37 @@ const (
 	headerAccept      = "Accept"
 	headerContentType = "Content-Type"
 	headerUserAgent   = "User-Agent"
+	headerLocation    = "Location"
 
 	headerTraceID = "X-Axiom-Trace-Id"
 
+	headerIdempotencyKey = "Idempotency-Key"
+
 	defaultMediaType = "application/octet-stream"
 	mediaTypeJSON    = "application/json"
 	mediaTypeNDJSON  = "application/x-ndjson"
 
 	otelTracerName = "github.com/axiomhq/axiom-go/axiom"
+
+	defaultRetryBudget = time.Second * 10
+)
+
+var (
+	// validOnlyAPITokenPaths are the paths an API token can be used for,
+	// regardless of the HTTP method.
+	validOnlyAPITokenPaths = regexp.MustCompile(`^/v1/datasets/([^/]+/(ingest|query)|_apl)(\?.+)?$`)
+	// validReadOnlyAPITokenPaths are the paths an API token can read from.
+	validReadOnlyAPITokenPaths = regexp.MustCompile(`^/(v1/(datasets(/[^/]+)?|version)|v2/tokens/self)$`)
 )
 
-var validOnlyAPITokenPaths = regexp.MustCompile(`^/v1/datasets/([^/]+/(ingest|query)|_apl)(\?.+)?$`)
+// isValidAPITokenRequest returns true if an API token can be used for a request
+// with the given method and path.
+func isValidAPITokenRequest(method, path string) bool {
+	if validOnlyAPITokenPaths.MatchString(path) {
+		return true
+	}
+	return method == http.MethodGet && validReadOnlyAPITokenPaths.MatchString(path)
+}
 
 // service is the base service used by all Axiom API services.
 type service struct {
@@ -66,12 +90,42 @@ func DefaultHTTPClient() *http.Client {
 // DefaultHTTPTransport returns the default [http.Client.Transport] used by
 // [DefaultHTTPClient].
 func DefaultHTTPTransport() http.RoundTripper {
+	return newHTTPTransport(transportOptions{})
+}
+
+// defaultIdleConnTimeout is the default time an idle connection is kept open
+// by the [DefaultHTTPTransport].
+const defaultIdleConnTimeout = time.Second * 90
+
+// transportOptions configure the transport built by the [Client]. Zero values
+// use the defaults of the [DefaultHTTPTransport].
+type transportOptions struct {
+	tlsConfig *tls.Config
+
+	maxIdleConns        int
+	maxIdleConnsPerHost int
+	maxConnsPerHost     int
+	idleConnTimeout     time.Duration
+}
+
+// newHTTPTransport returns the default [http.Client.Transport] configured by
+// the given options.
+func newHTTPTransport(opts transportOptions) http.RoundTripper {
+	idleConnTimeout := opts.idleConnTimeout
+	if idleConnTimeout <= 0 {
+		idleConnTimeout = defaultIdleConnTimeout
+	}
+
 	return otelhttp.NewTransport(gzhttp.Transport(&http.Transport{
+		TLSClientConfig: opts.tlsConfig,
 		DialContext: (&net.Dialer{
 			Timeout:   time.Second * 30,
 			KeepAlive: time.Second * 30,
 		}).DialContext,
-		IdleConnTimeout:       time.Second * 90,
+		MaxIdleConns:          opts.maxIdleConns,
+		MaxIdleConnsPerHost:   opts.maxIdleConnsPerHost,
+		MaxConnsPerHost:       opts.maxConnsPerHost,
+		IdleConnTimeout:       idleConnTimeout,
 		ResponseHeaderTimeout: time.Second * 10,
 		TLSHandshakeTimeout:   time.Second * 10,
 		ExpectContinueTimeout: time.Second * 1,
@@ -79,23 +133,116 @@ func DefaultHTTPTransport() http.RoundTripper {
 	}))
 }
 
-// Client provides the Axiom HTTP API operations.
+// Client provides the Axiom HTTP API operations. A client is safe for
+// concurrent use by multiple goroutines. Only [Client.Options] must not be
+// called concurrently with other methods of the client.
 type Client struct {
 	config config.Config
 
 	httpClient *http.Client
-	userAgent  string
-	noEnv      bool
-	noRetry    bool
+	// transport configures the transport of the http client, unless a custom
+	// http client or transport is set.
+	transport        transportOptions
+	customHTTPClient bool
+
+	userAgent       string
+	userAgentSuffix string
+	noEnv           bool
+	noRetry         bool
+
+	// limitShortCircuit prevents requests bound to exceed an exhausted limit
+	// from being sent.
+	limitShortCircuit bool
 
+	noAPITokenPathCheck bool
+
+	// retryBudget caps the time spent retrying a request, including the
+	// backoff between attempts, if set. Otherwise, retries stop after
+	// [defaultRetryBudget].
+	retryBudget time.Duration
+
+	// jitter randomizes the delay between retries using rand, which returns a
+	// number in [0.0,1.0). It is replaced in tests.
+	jitter Jitter
+	rand   func() float64
+
+	// breaker rejects requests to failing hosts, if set.
+	breaker *circuitBreaker
+
+	codec          Codec
 	strictDecoding bool
 
 	tracer trace.Tracer
 
+	// propagator injects the trace context into outgoing requests. If nil, the
+	// global propagator is used.
+	propagator propagation.TextMapPropagator
+
+	// metrics observes the requests made by the client, if set.
+	metrics MetricsObserver
+
+	// maxResponseBytes limits the size of response bodies, if greater than
+	// zero.
+	maxResponseBytes int64
+
+	// checkRedirect overrides the redirect policy of the http client, if set.
+	checkRedirect func(req *http.Request, via []*http.Request) error
+
+	// headers are added to every request.
+	headers http.Header
+
+	// debugLogger is passed every request attempt, if set.
+	debugLogger func(ctx context.Context, entry DebugEntry)
+
+	// tokenProvider provides the token for every request, if set. It takes
+	// precedence over the configured token.
+	tokenProvider    *tokenProvider
+	tokenProviderTTL time.Duration
+
+	// failover switches to a secondary token, if set.
+	failover        *tokenFailover
+	failoverHandler func()
+
+	// deprecationHandler is passed the deprecation warnings sent by the
+	// server, if set.
+	deprecationHandler func(warning string)
+
+	// limits keeps track of the limits reported by the server.
+	limits limitCache
+
+	// serverInfo caches the version of the server for [Client.Supports].
+	serverInfoMtx sync.Mutex
+	serverInfo    *Version
+
+	// tokenInfo caches the API token the client is currently authenticated
+	// with for [Client.CheckCapability]. Only the SHA-256 hash of the token is
+	// kept, as tokenInfoKey. The mutex is held while the token is fetched, so
+	// concurrent checks don't fetch it more than once.
+	tokenInfoMtx sync.Mutex
+	tokenInfoKey [sha256.Size]byte
+	tokenInfo    *APIToken
+
+	// fields caches the field types of datasets, keyed by organization and
+	// dataset id, for [DatasetsService.MapFields].
+	fieldsMtx sync.Mutex
+	fields    map[fieldsKey]map[string]FieldType
+
+	// optionClaims maps groups of mutually exclusive options to the option
+	// that claimed the group during the current [Client.Options] call.
+	optionClaims map[string]string
+
 	// Services for communicating with different parts of the Axiom API.
-	Datasets      *DatasetsService
-	Organizations *OrganizationsService
-	Users         *UsersService
+	Dashboards     *DashboardsService
+	Datasets       *DatasetsService
+	Monitors       *MonitorsService
+	Notifiers      *NotifiersService
+	Organizations  *OrganizationsService
+	StarredQueries *StarredQueriesService
+	Teams          *TeamsService
+	Tokens         *TokensService
+	Users          *UsersService
+	Version        *VersionService
+	VirtualFields  *VirtualFieldsService
 }
 
 // NewClient returns a new Axiom API client. It automatically takes its
@@ -116,19 +263,29 @@ func NewClient(options ...Option) (*Client, error) {
 
 		httpClient: DefaultHTTPClient(),
 
-		userAgent: "axiom-go",
+		userAgent: version.UserAgent(),
 
-		tracer: otel.Tracer(otelTracerName),
-	}
+		codec: stdCodec{},
+
+		jitter: JitterEqual,
+		rand:   rand.Float64,
 
-	// Include module version in the user agent.
-	if v := version.Get(); v != "" {
-		client.userAgent += fmt.Sprintf("/%s", v)
+		tracer: otel.Tracer(otelTracerName),
 	}
 
+	client.Dashboards = &DashboardsService{client, "/v1/dashboards"}
 	client.Datasets = &DatasetsService{client, "/v1/datasets"}
+	client.Monitors = &MonitorsService{client, "/v2/monitors"}
+	client.Notifiers = &NotifiersService{client, "/v2/notifiers"}
 	client.Organizations = &OrganizationsService{client, "/v1/orgs"}
+	client.StarredQueries = &StarredQueriesService{client, "/v1/starred"}
+	client.Teams = &TeamsService{client, "/v1/teams"}
+	client.Tokens = &TokensService{
+		API: &APITokensService{client, "/v2/tokens"},
+	}
 	client.Users = &UsersService{client, "/v1/users"}
+	client.Version = &VersionService{client, "/v1/version"}
+	client.VirtualFields = &VirtualFieldsService{client, "/v1/vfields"}
 
 	// Apply supplied options.
 	if err := client.Options(options...); err != nil {
@@ -143,11 +300,24 @@ func NewClient(options ...Option) (*Client, error) {
 		}
 	}
 
+	// The token is only known once it is retrieved from the token provider.
+	if client.tokenProvider != nil {
+		return client, nil
+	}
+
 	return client, client.config.Validate()
 }
 
-// Options applies options to the client.
+// Options applies options to the client. Passing mutually exclusive options
+// to the same call returns an [ErrConflictingOptions] error.
 func (c *Client) Options(options ...Option) error {
+	// Mutually exclusive options are tracked for the outermost call, only.
+	// Options composed of other options call this method themselves.
+	if c.optionClaims == nil {
+		c.optionClaims = make(map[string]string)
+		defer func() { c.optionClaims = nil }()
+	}
+
 	for _, option := range options {
 		if option == nil {
 			continue
@@ -161,8 +331,13 @@ func (c *Client) Options(options ...Option) error {
 // ValidateCredentials makes sure the client can properly authenticate against
 // the configured Axiom deployment.
 func (c *Client) ValidateCredentials(ctx context.Context) error {
-	if config.IsPersonalToken(c.config.Token()) {
-		_, err := c.Users.Current(ctx)
+	token, err := c.requestToken(ctx)
+	if err != nil {
+		return err
+	}
+
+	if config.IsPersonalToken(token) {
+		_, err = c.Users.Current(ctx)
 		return err
 	}
 
@@ -172,6 +347,48 @@ func (c *Client) ValidateCredentials(ctx context.Context) error {
 	return nil
 }
 
+// Ping verifies that the server is reachable and that the [Client] is
+// authenticated, e.g. for startup or readiness probes. It sends a single cheap
+// request that is never retried. An error matching [ErrUnreachable] is
+// returned if the server can't be reached and one matching
+// [ErrUnauthenticated] if the token is not valid. A token that is valid but
+// lacks permissions for the request is considered authenticated. If the context
+// is canceled or its deadline is exceeded, the context error is returned as is.
+func (c *Client) Ping(ctx context.Context) error {
+	ctx, span := c.trace(ctx, "Ping")
+	defer span.End()
+
+	// Use an endpoint that requires authentication and that can be accessed
+	// with both kinds of tokens.
+	token, err := c.requestToken(ctx)
+	if err != nil {
+		return spanError(span, err)
+	}
+
+	path := "/v1/user"
+	if config.IsAPIToken(token) {
+		path = "/v2/tokens/self"
+	}
+
+	req, err := c.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return spanError(span, err)
+	}
+
+	var urlErr *url.Error
+	if _, err = c.Do(req, nil); errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return spanError(span, err)
+	} else if errors.As(err, &urlErr) {
+		return spanError(span, fmt.Errorf("%w: %w", ErrUnreachable, err))
+	} else if errors.Is(err, ErrUnauthorized) {
+		return nil
+	} else if err != nil {
+		return spanError(span, err)
+	}
+
+	return nil
+}
+
 // Call creates a new API request and executes it. The response body is JSON
 // decoded or directly written to v, depending on v being an [io.Writer] or not.
 func (c *Client) Call(ctx context.Context, method, path string, body, v any) error {
@@ -194,30 +411,57 @@ func (c *Client) NewRequest(ctx context.Context, method, path string, body any)
 	}
 	endpoint := c.config.BaseURL().ResolveReference(rel)
 
-	if config.IsAPIToken(c.config.Token()) && !validOnlyAPITokenPaths.MatchString(endpoint.Path) {
-		return nil, ErrUnprivilegedToken
+	orgID := c.config.OrganizationID()
+	if ctxOrgID, ok := orgIDFromContext(ctx); ok {
+		if err = config.ValidateOrganizationID(ctxOrgID); err != nil {
+			return nil, err
+		}
+		orgID = ctxOrgID
+	}
+
+	token, err := c.requestToken(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if config.IsAPIToken(token) && !c.noAPITokenPathCheck && !isValidAPITokenRequest(method, endpoint.Path) {
+		return nil, fmt.Errorf("%w: %s %s", ErrUnprivilegedToken, method, endpoint.Path)
 	}
 
+	// Encode the body into a pooled buffer which is released once the request
+	// has been sent.
 	var (
 		r        io.Reader
 		isReader bool
+		buf      *pooledBuffer
 	)
 	if body != nil {
 		if r, isReader = body.(io.Reader); !isReader {
-			buf := new(bytes.Buffer)
-			if err = json.NewEncoder(buf).Encode(body); err != nil {
+			buf = newPooledBuffer()
+			if err = c.codec.NewEncoder(buf.buf).Encode(body); err != nil {
+				buf.done()
 				return nil, err
 			}
-			r = buf
 		}
 	}
 
 	ctx = httptrace.WithClientTrace(ctx, otelhttptrace.NewClientTrace(ctx))
 	req, err := http.NewRequestWithContext(ctx, method, endpoint.String(), r)
 	if err != nil {
+		if buf != nil {
+			buf.done()
+		}
 		return nil, err
 	}
 
+	if buf != nil {
+		if req.Body, err = buf.body(); err != nil {
+			return nil, err
+		}
+		req.GetBody = buf.body
+		req.ContentLength = int64(buf.buf.Len())
+	}
+
 	// Set Content-Type.
 	if body != nil && !isReader {
 		req.Header.Set(headerContentType, mediaTypeJSON)
@@ -226,18 +470,38 @@ func (c *Client) NewRequest(ctx context.Context, method, path string, body any)
 	}
 
 	// Set authorization header, if present.
-	if c.config.Token() != "" {
-		req.Header.Set(headerAuthorization, "Bearer "+c.config.Token())
+	if token != "" {
+		req.Header.Set(headerAuthorization, "Bearer "+token)
 	}
 
-	// Set organization ID header when using a personal token.
-	if config.IsPersonalToken(c.config.Token()) && c.config.OrganizationID() != "" {
-		req.Header.Set(headerOrganizationID, c.config.OrganizationID())
+	// Set organization ID header when using a personal token. An organization
+	// ID carried by the context takes precedence over the configured one.
+	if config.IsPersonalToken(token) && orgID != "" {
+		req.Header.Set(headerOrganizationID, orgID)
 	}
 
 	// Set other headers.
 	req.Header.Set(headerAccept, mediaTypeJSON)
-	req.Header.Set(headerUserAgent, c.userAgent)
+	if c.userAgentSuffix != "" {
+		req.Header.Set(headerUserAgent, c.userAgent+" "+c.userAgentSuffix)
+	} else {
+		req.Header.Set(headerUserAgent, c.userAgent)
+	}
+
+	// Add custom headers. Reserved headers are rejected by the options that
+	// configure them, so they never override the ones set above.
+	for k, vs := range c.headers {
+		for _, v := range vs {
+			req.Header.Add(k, v)
+		}
+	}
+
+	// Propagate the trace context of the active span, if any.
+	propagator := c.propagator
+	if propagator == nil {
+		propagator = otel.GetTextMapPropagator()
+	}
+	propagator.Inject(ctx, propagation.HeaderCarrier(req.Header))
 
 	return req, nil
 }
@@ -245,24 +509,77 @@ func (c *Client) NewRequest(ctx context.Context, method, path string, body any)
 // Do sends an API request and returns the API response. The response body is
 // JSON decoded or directly written to v, depending on v being an [io.Writer] or
 // not.
+//
+// Failed requests are retried with an exponential backoff, unless disabled with
+// [SetNoRetry]. Retrying stops as soon as the context of the request is
+// canceled and no retry is started that would begin after its deadline.
+//
+// If enabled with [SetLimitShortCircuit], requests that are bound to exceed a
+// limit which the server reported as exhausted are not sent. Instead, a
+// [LimitError] is returned until the limit resets.
 func (c *Client) Do(req *http.Request, v any) (*Response, error) {
+	// Release the pooled buffer of the request body once the request has been
+	// sent, including all retries.
+	if body, ok := req.Body.(*pooledBody); ok {
+		defer body.buf.done()
+	}
+
+	// Don't send requests that are bound to exceed a limit, until it resets.
+	orgID := req.Header.Get(headerOrganizationID)
+	if c.limitShortCircuit {
+		if limit, ok := c.limits.exhausted(orgID, requestLimitType(req), time.Now()); ok {
+			status, text := limitStatus(limit)
+			return nil, LimitError{
+				HTTPError: HTTPError{
+					Status:  status,
+					Message: text,
+				},
+
+				Limit: limit,
+			}
+		}
+	}
+
+	httpClient := c.httpClient
+	if c.checkRedirect != nil {
+		redirectClient := *c.httpClient
+		redirectClient.CheckRedirect = c.checkRedirect
+		httpClient = &redirectClient
+	}
+
 	var (
 		resp *Response
 		err  error
 	)
 	if req.GetBody != nil && !c.noRetry {
-		bck := backoff.NewExponentialBackOff()
-		bck.InitialInterval = time.Millisecond * 200
-		bck.MaxElapsedTime = time.Second * 10
-		bck.Multiplier = 2.0
+		bck := c.newBackOff(req.Context())
+
+		var (
+			attempt     int
+			retryReason string
+		)
+		op := func() error {
+			if attempt++; attempt > 1 {
+				c.observeRetry(req)
+			}
+
+			// An open circuit skips the remaining retries.
+			if err = c.breaker.allow(req.URL.Host, time.Now()); err != nil {
+				return backoff.Permanent(err)
+			}
 
-		err = backoff.Retry(func() error {
 			var httpResp *http.Response
+			start := time.Now()
 			//nolint:bodyclose // The response body is closed later down below.
-			if httpResp, err = c.httpClient.Do(req); err != nil {
+			httpResp, err = httpClient.Do(req)
+			c.breaker.record(req, httpResp, err, time.Now())
+			c.observeRequest(req, httpResp, start)
+			c.logAttempt(req, httpResp, err, start, attempt, retryReason)
+			if err != nil {
+				retryReason = err.Error()
 				return err
 			}
-			resp = newResponse(httpResp)
+			resp = newResponse(httpResp, start, attempt)
 
 			// We should only retry in the case the status code is >= 500,
 			// anything below isn't worth retrying.
@@ -275,18 +592,55 @@ func (c *Client) Do(req *http.Request, v any) (*Response, error) {
 					return backoff.Permanent(err)
 				}
 
-				return fmt.Errorf("got status code %d", code)
+				retryReason = fmt.Sprintf("got status code %d", code)
+				return errors.New(retryReason)
 			}
 
 			return nil
-		}, bck)
+		}
+		err = backoff.Retry(op, backoff.WithContext(bck, req.Context()))
 	} else {
+		if err = c.breaker.allow(req.URL.Host, time.Now()); err != nil {
+			return nil, err
+		}
+
 		var httpResp *http.Response
+		start := time.Now()
 		//nolint:bodyclose // The response body is closed later down below.
-		if httpResp, err = c.httpClient.Do(req); err != nil {
+		httpResp, err = httpClient.Do(req)
+		c.breaker.record(req, httpResp, err, time.Now())
+		c.observeRequest(req, httpResp, start)
+		c.logAttempt(req, httpResp, err, start, 1, "")
+		if err != nil {
 			return nil, err
 		}
-		resp = newResponse(httpResp)
+		resp = newResponse(httpResp, start, 1)
+	}
+
+	if resp != nil {
+		c.limits.record(orgID, resp.Limit)
+
+		if c.deprecationHandler != nil {
+			if warning := deprecationWarning(req, resp.Header); warning != "" {
+				c.deprecationHandler(warning)
+			}
+		}
+	}
+
+	// Fetch a new token from the token provider for the next request, if the
+	// current one was rejected.
+	if resp != nil && resp.StatusCode == http.StatusUnauthorized && c.tokenProvider != nil {
+		c.tokenProvider.invalidate(strings.TrimPrefix(req.Header.Get(headerAuthorization), "Bearer "))
+	}
+
+	// Send the request again using the secondary token, if the primary one was
+	// rejected.
+	if err == nil && resp != nil {
+		if failoverReq := c.failoverRequest(req, resp); failoverReq != nil {
+			_, _ = io.Copy(io.Discard, resp.Body)
+			_ = resp.Body.Close()
+			return c.Do(failoverReq, v)
+		}
 	}
 
 	defer func() {
@@ -300,11 +654,24 @@ func (c *Client) Do(req *http.Request, v any) (*Response, error) {
 		return resp, err
 	}
 
+	if c.maxResponseBytes > 0 {
+		resp.Body = &limitedBody{ReadCloser: resp.Body, n: c.maxResponseBytes}
+	}
+
 	span := trace.SpanFromContext(req.Context())
 	if span.IsRecording() {
 		span.SetAttributes(attribute.String("axiom_trace_id", resp.TraceID()))
 	}
 
+	// Surface redirects that have not been followed.
+	if statusCode := resp.StatusCode; statusCode >= 300 && statusCode < 400 && resp.Header.Get(headerLocation) != "" {
+		return resp, HTTPError{
+			Status:  statusCode,
+			Message: fmt.Sprintf("%s: %s", http.StatusText(statusCode), resp.Header.Get(headerLocation)),
+			TraceID: resp.TraceID(),
+		}
+	}
+
 	if statusCode := resp.StatusCode; statusCode >= 400 {
 		httpErr := HTTPError{
 			Status:  statusCode,
@@ -314,6 +681,9 @@ func (c *Client) Do(req *http.Request, v any) (*Response, error) {
 
 		// Handle a generic HTTP error if the response is not JSON formatted.
 		if val := resp.Header.Get(headerContentType); !strings.HasPrefix(val, mediaTypeJSON) {
+			if raw, _ := io.ReadAll(io.LimitReader(resp.Body, maxRawErrorBytes)); len(raw) > 0 {
+				httpErr.Raw = string(raw)
+			}
 			return resp, httpErr
 		}
 
@@ -321,7 +691,7 @@ func (c *Client) Do(req *http.Request, v any) (*Response, error) {
 		// to inspect it further
 		var (
 			buf bytes.Buffer
-			dec = json.NewDecoder(io.TeeReader(resp.Body, &buf))
+			dec = c.codec.NewDecoder(io.TeeReader(resp.Body, &buf))
 		)
 
 		// Handle a properly JSON formatted Axiom API error response.
@@ -338,6 +708,10 @@ func (c *Client) Do(req *http.Request, v any) (*Response, error) {
 
 		// Handle special error types.
 		switch statusCode {
+		case http.StatusUnauthorized:
+			if strings.Contains(strings.ToLower(httpErr.Message), "expired") {
+				return resp, fmt.Errorf("%w: %w", ErrTokenExpired, httpErr)
+			}
 		case http.StatusTooManyRequests, httpStatusLimitExceeded:
 			return resp, LimitError{
 				HTTPError: httpErr,
@@ -356,7 +730,7 @@ func (c *Client) Do(req *http.Request, v any) (*Response, error) {
 		}
 
 		if val := resp.Header.Get(headerContentType); strings.HasPrefix(val, mediaTypeJSON) {
-			dec := json.NewDecoder(resp.Body)
+			dec := c.codec.NewDecoder(resp.Body)
 			if c.strictDecoding {
 				dec.DisallowUnknownFields()
 			}
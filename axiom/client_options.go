@@ -1,8 +1,17 @@
 package axiom
 
 import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
 	"net/http"
+	"time"
 
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
 	"go.opentelemetry.io/otel/trace/noop"
 
 	"github.com/axiomhq/axiom-go/internal/config"
@@ -14,6 +23,50 @@ import (
 // operation.
 type Option func(c *Client) error
 
+// ErrConflictingOptions is raised when options that are mutually exclusive are
+// passed to the same [NewClient] or [Client.Options] call.
+var ErrConflictingOptions = errors.New("conflicting options")
+
+// ErrInvalidOrganizationID is raised when a malformed organization ID is passed
+// to [SetOrganizationID] or exported as "AXIOM_ORG_ID".
+var ErrInvalidOrganizationID = config.ErrInvalidOrganizationID
+
+// ErrReservedHeader is raised when a custom header is configured that is
+// managed by the [Client] itself.
+var ErrReservedHeader = errors.New("reserved header")
+
+// reservedHeaders are the headers managed by the [Client] which can't be set
+// using [SetHeaders] or [AddHeader].
+var reservedHeaders = []string{
+	headerAuthorization,
+	headerOrganizationID,
+	headerAccept,
+	headerContentType,
+	headerUserAgent,
+	headerIdempotencyKey,
+}
+
+// Groups of mutually exclusive options.
+const (
+	optionGroupTokenConfig = "token config"
+	optionGroupTracing     = "tracing"
+)
+
+// exclusiveOption returns an option that conflicts with all other options of
+// the same group, identified by a different name, passed to the same
+// [Client.Options] call.
+func exclusiveOption(group, name string, option Option) Option {
+	return func(c *Client) error {
+		if c.optionClaims != nil {
+			if other, ok := c.optionClaims[group]; ok && other != name {
+				return fmt.Errorf("%w: %s and %s are mutually exclusive", ErrConflictingOptions, other, name)
+			}
+			c.optionClaims[group] = name
+		}
+		return option(c)
+	}
+}
+
 // SetURL specifies the base URL used by the [Client].
 //
 // Can also be specified using the "AXIOM_URL" environment variable.
@@ -28,6 +81,72 @@ func SetToken(accessToken string) Option {
 	return func(c *Client) error { return c.config.Options(config.SetToken(accessToken)) }
 }
 
+// ErrTokenMismatch is raised when the tokens passed to [SetAccessTokens] are
+// not of the same kind.
+var ErrTokenMismatch = errors.New("primary and secondary token must both be personal or api tokens")
+
+// SetAccessTokens specifies a primary and a secondary token used by the
+// [Client], e.g. while rotating tokens. The primary token is used until the
+// server rejects it with a 401 (Unauthorized), e.g. because it was revoked.
+// From then on, the secondary token is used and the rejected request is sent
+// again with it, if its body can be re-read. Both tokens must be of the same
+// kind, otherwise an [ErrTokenMismatch] is returned. Use
+// [SetTokenFailoverHandler] to get notified about the switch.
+func SetAccessTokens(primary, secondary string) Option {
+	return func(c *Client) error {
+		if err := c.config.Options(config.SetToken(primary)); err != nil {
+			return err
+		} else if !config.IsValidToken(secondary) {
+			return config.ErrInvalidToken
+		} else if config.IsPersonalToken(primary) != config.IsPersonalToken(secondary) {
+			return ErrTokenMismatch
+		}
+		c.failover = &tokenFailover{primary: primary, secondary: secondary}
+		return nil
+	}
+}
+
+// SetTokenProvider specifies a function that provides the token used by the
+// [Client], e.g. to use short-lived tokens minted by a secrets manager. It
+// replaces the configured token. The returned token is cached for the duration
+// set by [SetTokenProviderTTL] and fetched again once the cache expired or the
+// server rejected it. By default, the token is fetched for every request. If
+// the function fails, the request is not sent and an error wrapping
+// [ErrTokenProvider] and the underlying error is returned.
+func SetTokenProvider(provider func(ctx context.Context) (string, error)) Option {
+	return func(c *Client) error {
+		if provider == nil {
+			c.tokenProvider = nil
+			return nil
+		}
+		c.tokenProvider = &tokenProvider{provide: provider}
+		return nil
+	}
+}
+
+// SetTokenProviderTTL specifies the duration a token returned by the function
+// passed to [SetTokenProvider] is cached for.
+func SetTokenProviderTTL(ttl time.Duration) Option {
+	return func(c *Client) error {
+		if ttl < 0 {
+			return fmt.Errorf("invalid token provider ttl: %s", ttl)
+		}
+		c.tokenProviderTTL = ttl
+		return nil
+	}
+}
+
+// SetTokenFailoverHandler specifies a function that is called once the
+// [Client] switches from the primary to the secondary token configured by
+// [SetAccessTokens], e.g. to alert that the primary token needs to be
+// replaced.
+func SetTokenFailoverHandler(handler func()) Option {
+	return func(c *Client) error {
+		c.failoverHandler = handler
+		return nil
+	}
+}
+
 // SetOrganizationID specifies the organization ID used by the [Client].
 //
 // When a personal token is used, this method can be used to switch between
@@ -38,31 +157,96 @@ func SetOrganizationID(organizationID string) Option {
 	return func(c *Client) error { return c.config.Options(config.SetOrganizationID(organizationID)) }
 }
 
+// SetOrgID is an alias for [SetOrganizationID], which is the canonical name.
+func SetOrgID(organizationID string) Option {
+	return SetOrganizationID(organizationID)
+}
+
 // SetPersonalTokenConfig specifies all properties needed in order to
-// successfully connect to Axiom with a personal token.
+// successfully connect to Axiom with a personal token. It can't be combined
+// with [SetAPITokenConfig].
 func SetPersonalTokenConfig(personalToken, organizationID string) Option {
-	return func(c *Client) error {
+	return exclusiveOption(optionGroupTokenConfig, "SetPersonalTokenConfig", func(c *Client) error {
 		return c.Options(
 			SetToken(personalToken),
 			SetOrganizationID(organizationID),
 		)
-	}
+	})
 }
 
 // SetAPITokenConfig specifies all properties needed in order to successfully
-// connect to Axiom with an API token.
+// connect to Axiom with an API token. It can't be combined with
+// [SetPersonalTokenConfig].
 func SetAPITokenConfig(apiToken string) Option {
-	return SetToken(apiToken)
+	return exclusiveOption(optionGroupTokenConfig, "SetAPITokenConfig", SetToken(apiToken))
 }
 
+// ErrTooManyRedirects is returned when a request exceeds the number of redirects
+// configured using [SetMaxRedirects].
+var ErrTooManyRedirects = errors.New("too many redirects")
+
+// ErrInvalidRootCAs is raised when the PEM bundle passed to [SetRootCAs] does
+// not contain any certificate.
+var ErrInvalidRootCAs = errors.New("no certificates found in PEM bundle")
+
 // SetClient specifies the custom http client used by the [Client] to make
-// requests.
+// requests. It replaces the http client configured by [SetTLSConfig] or
+// [SetRootCAs], if they are passed before it.
 func SetClient(httpClient *http.Client) Option {
 	return func(c *Client) error {
 		if httpClient == nil {
 			return nil
 		}
 		c.httpClient = httpClient
+		c.customHTTPClient = true
+		return nil
+	}
+}
+
+// SetStrictDecoding specifies if decoding a JSON response fails when it carries
+// fields that are not present in the type it is decoded into. This helps to
+// detect changes of the API schema that are not reflected by this package,
+// yet. The downside is that any field added by the server, even one that is
+// irrelevant to the caller, breaks requests that worked before. Defaults to
+// false, which silently ignores unknown fields.
+func SetStrictDecoding(strict bool) Option {
+	return func(c *Client) error {
+		c.strictDecoding = strict
+		return nil
+	}
+}
+
+// SetCodec specifies the [Codec] used by the [Client] to encode request payloads,
+// including ingested events, and to decode responses. Defaults to the
+// [encoding/json] package of the standard library. Strict decoding enabled by
+// [SetStrictDecoding] is passed on to the decoders of the codec. Events are
+// only guaranteed to be encoded in sorted key order, as described on [Event],
+// if the codec sorts map keys like the standard library does.
+func SetCodec(codec Codec) Option {
+	return func(c *Client) error {
+		if codec == nil {
+			return errors.New("codec must not be nil")
+		}
+		c.codec = codec
+		return nil
+	}
+}
+
+// SetTransport specifies the [http.RoundTripper] used by the [Client] to make
+// requests, e.g. the replay transport provided by the
+// "github.com/axiomhq/axiom-go/axiom/axiomtest" package. It replaces the http
+// client of the [Client] with one that uses the given transport. The last one
+// of SetTransport, [SetClient], [SetTLSConfig] and [SetRootCAs] takes
+// precedence.
+func SetTransport(transport http.RoundTripper) Option {
+	return func(c *Client) error {
+		if transport == nil {
+			return nil
+		}
+		c.httpClient = &http.Client{
+			Transport: transport,
+		}
+		c.customHTTPClient = true
 		return nil
 	}
 }
@@ -75,6 +259,18 @@ func SetUserAgent(userAgent string) Option {
 	}
 }
 
+// SetUserAgentSuffix specifies a suffix that is appended to the user agent used
+// by the [Client], separated by a space. Unlike [SetUserAgent], it keeps the
+// default user agent, which allows adapters and other integrations to identify
+// themselves without losing the identity of the client. The suffix is also
+// appended to a user agent set by [SetUserAgent].
+func SetUserAgentSuffix(suffix string) Option {
+	return func(c *Client) error {
+		c.userAgentSuffix = suffix
+		return nil
+	}
+}
+
 // SetNoEnv prevents the [Client] from deriving its configuration from the
 // environment (by auto reading "AXIOM_*" environment variables).
 func SetNoEnv() Option {
@@ -93,11 +289,316 @@ func SetNoRetry() Option {
 	}
 }
 
+// SetLimitShortCircuit makes the [Client] return a [LimitError] for requests
+// that are bound to exceed a limit which the server reported as exhausted,
+// without sending them, until the limit resets. Rate limits apply to all
+// requests, ingest and query limits only to ingest and query requests,
+// respectively. Limits are tracked per organization.
+func SetLimitShortCircuit() Option {
+	return func(c *Client) error {
+		c.limitShortCircuit = true
+		return nil
+	}
+}
+
 // SetNoTracing prevents the [Client] from acquiring a tracer from the global
-// tracer provider, even if one is configured.
+// tracer provider, even if one is configured. It can't be combined with
+// [SetTracerProvider].
 func SetNoTracing() Option {
-	return func(c *Client) error {
+	return exclusiveOption(optionGroupTracing, "SetNoTracing", func(c *Client) error {
 		c.tracer = noop.Tracer{}
 		return nil
+	})
+}
+
+// SetTracerProvider specifies the tracer provider the [Client] acquires its
+// tracer from, instead of the global one. This allows isolating the spans of
+// different clients. Passing nil restores the global tracer provider. It can't
+// be combined with [SetNoTracing].
+func SetTracerProvider(tracerProvider trace.TracerProvider) Option {
+	return exclusiveOption(optionGroupTracing, "SetTracerProvider", func(c *Client) error {
+		if tracerProvider == nil {
+			tracerProvider = otel.GetTracerProvider()
+		}
+		c.tracer = tracerProvider.Tracer(otelTracerName)
+		return nil
+	})
+}
+
+// SetTracePropagation specifies the propagator used by the [Client] to inject
+// the trace context of the active span (e.g. the W3C "traceparent" and
+// "tracestate" headers) into outgoing requests. This links server-side spans to
+// the client spans. Defaults to the global propagator.
+func SetTracePropagation(propagator propagation.TextMapPropagator) Option {
+	return func(c *Client) error {
+		c.propagator = propagator
+		return nil
+	}
+}
+
+// SetMetrics specifies the [MetricsObserver] that is notified about every
+// request attempt and retry made by the [Client].
+func SetMetrics(observer MetricsObserver) Option {
+	return func(c *Client) error {
+		c.metrics = observer
+		return nil
+	}
+}
+
+// SetDebugLogger specifies a function that is passed a [DebugEntry] for every
+// request attempt made by the [Client]. It is meant to inspect the live request
+// flow during development. Tokens are redacted from the request headers. By
+// default, no debug logging takes place.
+func SetDebugLogger(logger func(ctx context.Context, entry DebugEntry)) Option {
+	return func(c *Client) error {
+		c.debugLogger = logger
+		return nil
+	}
+}
+
+// SetDeprecationHandler specifies a function that is passed a warning whenever
+// the server marks a requested endpoint as deprecated using the "Deprecation"
+// or "Sunset" response headers. This helps to notice the use of functionality
+// that is about to be removed ahead of time, e.g. by logging the warnings. By
+// default, the warnings are only passed to the debug logger, if set, as part
+// of the [DebugEntry]. See [SetDebugLogger].
+func SetDeprecationHandler(handler func(warning string)) Option {
+	return func(c *Client) error {
+		c.deprecationHandler = handler
+		return nil
+	}
+}
+
+// SetHeaders specifies custom headers that are added to every request made by
+// the [Client], e.g. for an API gateway that requires them. It replaces all
+// previously configured custom headers. Headers managed by the client, like
+// "Authorization" or "Content-Type", can't be set and cause an
+// [ErrReservedHeader].
+func SetHeaders(header http.Header) Option {
+	return func(c *Client) error {
+		for k := range header {
+			if err := checkReservedHeader(k); err != nil {
+				return err
+			}
+		}
+		c.headers = header.Clone()
+		return nil
+	}
+}
+
+// AddHeader adds a custom header that is added to every request made by the
+// [Client]. Headers managed by the client, like "Authorization" or
+// "Content-Type", can't be set and cause an [ErrReservedHeader].
+func AddHeader(key, value string) Option {
+	return func(c *Client) error {
+		if err := checkReservedHeader(key); err != nil {
+			return err
+		}
+		if c.headers == nil {
+			c.headers = make(http.Header)
+		}
+		c.headers.Add(key, value)
+		return nil
+	}
+}
+
+// checkReservedHeader returns an [ErrReservedHeader] if the given header key is
+// managed by the [Client].
+func checkReservedHeader(key string) error {
+	key = http.CanonicalHeaderKey(key)
+	for _, reserved := range reservedHeaders {
+		if key == http.CanonicalHeaderKey(reserved) {
+			return fmt.Errorf("%w: %s", ErrReservedHeader, key)
+		}
+	}
+	return nil
+}
+
+// SetTLSConfig specifies the TLS configuration used by the [Client], e.g. to
+// connect to a self-hosted Axiom instance. It replaces the http client of the
+// [Client] with the [DefaultHTTPClient] using the given TLS configuration. The
+// last one of SetTLSConfig, [SetRootCAs] and [SetClient] takes precedence.
+func SetTLSConfig(tlsConfig *tls.Config) Option {
+	return func(c *Client) error {
+		c.transport.tlsConfig = tlsConfig.Clone()
+		c.customHTTPClient = false
+		c.rebuildHTTPClient()
+		return nil
+	}
+}
+
+// SetRootCAs specifies a PEM encoded bundle of root certificate authorities the
+// [Client] uses to verify server certificates, e.g. the internal certificate
+// authority of a self-hosted Axiom instance. It is a shorthand for
+// [SetTLSConfig] and follows the same precedence rules.
+func SetRootCAs(pemCerts []byte) Option {
+	return func(c *Client) error {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pemCerts) {
+			return ErrInvalidRootCAs
+		}
+		return SetTLSConfig(&tls.Config{
+			MinVersion: tls.VersionTLS12,
+			RootCAs:    pool,
+		})(c)
+	}
+}
+
+// SetConnectionPool specifies the size of the connection pool of the
+// [Client], e.g. to sustain highly concurrent ingestion. maxIdle limits the
+// idle connections across all hosts, maxIdlePerHost the idle connections per
+// host and maxConnsPerHost all connections per host, including the ones in use.
+// A zero value uses the default of the [http.Transport], which doesn't limit
+// maxIdle and maxConnsPerHost and keeps two idle connections per host. It only
+// applies to the http client built by the [Client]: if a custom http client or
+// transport is set using [SetClient] or [SetTransport], it is ignored.
+func SetConnectionPool(maxIdle, maxIdlePerHost, maxConnsPerHost int) Option {
+	return func(c *Client) error {
+		if maxIdle < 0 || maxIdlePerHost < 0 || maxConnsPerHost < 0 {
+			return fmt.Errorf("invalid connection pool size: %d, %d, %d", maxIdle, maxIdlePerHost, maxConnsPerHost)
+		}
+		c.transport.maxIdleConns = maxIdle
+		c.transport.maxIdleConnsPerHost = maxIdlePerHost
+		c.transport.maxConnsPerHost = maxConnsPerHost
+		c.rebuildHTTPClient()
+		return nil
+	}
+}
+
+// SetIdleConnTimeout specifies how long an idle connection is kept open by the
+// [Client] before it is closed. Defaults to 90 seconds. Like
+// [SetConnectionPool], it is ignored if a custom http client or transport is
+// set.
+func SetIdleConnTimeout(timeout time.Duration) Option {
+	return func(c *Client) error {
+		if timeout <= 0 {
+			return fmt.Errorf("invalid idle connection timeout: %s", timeout)
+		}
+		c.transport.idleConnTimeout = timeout
+		c.rebuildHTTPClient()
+		return nil
+	}
+}
+
+// rebuildHTTPClient replaces the http client of the [Client] with one using
+// the configured transport options, unless a custom http client or transport
+// is set.
+func (c *Client) rebuildHTTPClient() {
+	if c.customHTTPClient {
+		return
+	}
+	c.httpClient = &http.Client{
+		Transport: newHTTPTransport(c.transport),
+	}
+}
+
+// SetMaxResponseBytes limits the size of response bodies read by the [Client]
+// to n bytes. Reading a larger response body fails with an
+// [ErrResponseTooLarge]. Error responses smaller than the limit are handled as
+// usual. By default, response bodies are not limited.
+func SetMaxResponseBytes(n int64) Option {
+	return func(c *Client) error {
+		c.maxResponseBytes = n
+		return nil
+	}
+}
+
+// SetMaxRedirects limits the number of redirects the [Client] follows for a
+// single request to n. Exceeding the limit fails the request with an
+// [ErrTooManyRedirects]. It overrides the redirect policy of the http client
+// and replaces a previous [SetFollowRedirects] option.
+func SetMaxRedirects(n int) Option {
+	return func(c *Client) error {
+		if n < 0 {
+			return fmt.Errorf("invalid number of redirects: %d", n)
+		}
+		c.checkRedirect = func(_ *http.Request, via []*http.Request) error {
+			if len(via) > n {
+				return fmt.Errorf("%w: stopped after %d redirects", ErrTooManyRedirects, n)
+			}
+			return nil
+		}
+		return nil
+	}
+}
+
+// SetFollowRedirects specifies if the [Client] follows redirects. When
+// disabled, a redirect response is returned alongside an [HTTPError] carrying
+// its status code and location instead of being followed, e.g. to detect an
+// authentication proxy redirecting to a login page. Enabling it restores the
+// redirect policy of the http client and replaces a previous
+// [SetMaxRedirects] option.
+func SetFollowRedirects(follow bool) Option {
+	return func(c *Client) error {
+		c.checkRedirect = nil
+		if !follow {
+			c.checkRedirect = func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			}
+		}
+		return nil
+	}
+}
+
+// SetNoAPITokenPathCheck prevents the [Client] from rejecting requests an API
+// token is not expected to be privileged for with an [ErrUnprivilegedToken].
+// By default, API tokens can only be used for ingest and query operations and
+// to read datasets. This is useful for API tokens that have been granted
+// additional capabilities, as the server makes the final decision based on the
+// actual capabilities of the token.
+func SetNoAPITokenPathCheck() Option {
+	return func(c *Client) error {
+		c.noAPITokenPathCheck = true
+		return nil
+	}
+}
+
+// SetRetryBudget caps the total time the [Client] spends retrying a request,
+// including the time spent on failed attempts and the backoff between them. No
+// further retry is started once the budget is exhausted and the error of the
+// last attempt is returned. A single attempt is never aborted by the budget, so
+// slow but healthy requests are not affected. A deadline of the requests
+// context takes precedence, if it is tighter: no retry is started that would
+// begin after it. Defaults to 10 seconds.
+func SetRetryBudget(d time.Duration) Option {
+	return func(c *Client) error {
+		if d <= 0 {
+			return fmt.Errorf("invalid retry budget: %s", d)
+		}
+		c.retryBudget = d
+		return nil
+	}
+}
+
+// SetBackoffJitter specifies the [Jitter] algorithm used to randomize the delay
+// between retries of failed requests. This prevents a fleet of clients from
+// retrying in lockstep. Defaults to [JitterEqual].
+func SetBackoffJitter(jitter Jitter) Option {
+	return func(c *Client) error {
+		switch jitter {
+		case JitterNone, JitterFull, JitterEqual:
+			c.jitter = jitter
+			return nil
+		}
+		return fmt.Errorf("invalid backoff jitter: %s", jitter)
+	}
+}
+
+// SetCircuitBreaker enables a circuit breaker that stops the [Client] from
+// sending requests to a host after threshold consecutive failures. Transport
+// errors and server errors count as failures. While the circuit is open,
+// requests fail immediately with an [ErrCircuitOpen] and are not retried. After
+// the cooldown, a single probe request is let through: if it succeeds, the
+// circuit closes again, otherwise it stays open for another cooldown. Use
+// [Client.CircuitState] to inspect the state of the circuit.
+func SetCircuitBreaker(threshold int, cooldown time.Duration) Option {
+	return func(c *Client) error {
+		if threshold <= 0 {
+			return fmt.Errorf("invalid circuit breaker threshold: %d", threshold)
+		} else if cooldown <= 0 {
+			return fmt.Errorf("invalid circuit breaker cooldown: %s", cooldown)
+		}
+		c.breaker = newCircuitBreaker(threshold, cooldown)
+		return nil
 	}
 }
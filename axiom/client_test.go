@@ -3,20 +3,29 @@ package axiom
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"encoding/json"
+	"encoding/pem"
+	"errors"
 	"fmt"
 	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
-	"regexp"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/axiomhq/axiom-go/internal/config"
 	"github.com/axiomhq/axiom-go/internal/test/testhelper"
@@ -29,18 +38,6 @@ const (
 	organizationID = "awkward-identifier-c3po"
 )
 
-var tokenRe = regexp.MustCompile("xa(a|p)t-[a-zA-z0-9]{8}-[a-zA-z0-9]{4}-[a-zA-z0-9]{4}-[a-zA-z0-9]{4}-[a-zA-z0-9]{12}")
-
-// SetStrictDecoding is a special testing-only client option that - when set to
-// 'true' - failes JSON response decoding if fields not present in the
-// destination struct are encountered.
-func SetStrictDecoding(b bool) Option {
-	return func(c *Client) error {
-		c.strictDecoding = b
-		return nil
-	}
-}
-
 func TestNewClient(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -178,6 +175,37 @@ func TestNewClient(t *testing.T) {
 				SetOrganizationID(organizationID),
 			},
 		},
+		{
+			name: "no environment token and self-hosted organizationID option",
+			options: []Option{
+				SetToken(personalToken),
+				SetOrganizationID("Acme_Corp.eu-1"),
+			},
+		},
+		{
+			name: "no environment token and malformed organizationID option",
+			options: []Option{
+				SetToken(personalToken),
+				SetOrganizationID("awkward identifier/c3po"),
+			},
+			err: ErrInvalidOrganizationID,
+		},
+		{
+			name: "no environment token and token as organizationID option",
+			options: []Option{
+				SetToken(personalToken),
+				SetOrganizationID(apiToken),
+			},
+			err: ErrInvalidOrganizationID,
+		},
+		{
+			name: "token and malformed organizationID environment no options",
+			environment: map[string]string{
+				"AXIOM_TOKEN":  personalToken,
+				"AXIOM_ORG_ID": "-c3po",
+			},
+			err: ErrInvalidOrganizationID,
+		},
 		{
 			name: "token and organizationID environment noEnv option",
 			environment: map[string]string{
@@ -221,16 +249,25 @@ func TestNewClient_Valid(t *testing.T) {
 	client := newClient(t)
 
 	// Are endpoints/resources present?
+	assert.NotNil(t, client.Dashboards)
 	assert.NotNil(t, client.Datasets)
+	assert.NotNil(t, client.Monitors)
+	assert.NotNil(t, client.Notifiers)
 	assert.NotNil(t, client.Organizations)
+	assert.NotNil(t, client.StarredQueries)
+	assert.NotNil(t, client.Teams)
+	assert.NotNil(t, client.Tokens)
+	assert.NotNil(t, client.Tokens.API)
 	assert.NotNil(t, client.Users)
+	assert.NotNil(t, client.Version)
+	assert.NotNil(t, client.VirtualFields)
 
 	// Is default configuration present?
 	assert.Equal(t, endpoint, client.config.BaseURL().String())
 	assert.Equal(t, personalToken, client.config.Token())
 	assert.Empty(t, client.config.OrganizationID())
 	assert.NotNil(t, client.httpClient)
-	assert.NotEmpty(t, client.userAgent)
+	assert.Regexp(t, `^axiom-go/\S+ \(go/go\S+; \w+/\w+\)$`, client.userAgent)
 	assert.False(t, client.strictDecoding)
 	assert.True(t, client.noEnv) // Disabled for testing.
 	assert.False(t, client.noRetry)
@@ -262,6 +299,48 @@ func TestClient_Options_SetClient(t *testing.T) {
 	assert.Equal(t, exp, client.httpClient)
 }
 
+func TestClient_Options_SetRootCAs(t *testing.T) {
+	hf := func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	srv := httptest.NewTLSServer(http.HandlerFunc(hf))
+	t.Cleanup(srv.Close)
+
+	newTLSClient := func(options ...Option) *Client {
+		client, err := NewClient(append([]Option{
+			SetURL(srv.URL),
+			SetToken(personalToken),
+			SetOrganizationID(organizationID),
+			SetNoEnv(),
+			SetNoRetry(),
+		}, options...)...)
+		require.NoError(t, err)
+		return client
+	}
+
+	pemCerts := pem.EncodeToMemory(&pem.Block{
+		Type:  "CERTIFICATE",
+		Bytes: srv.Certificate().Raw,
+	})
+
+	// Without the certificate of the server, the request fails.
+	err := newTLSClient().Call(context.Background(), http.MethodGet, "/", nil, nil)
+	assert.ErrorAs(t, err, new(*tls.CertificateVerificationError))
+
+	err = newTLSClient(SetRootCAs(pemCerts)).Call(context.Background(), http.MethodGet, "/", nil, nil)
+	assert.NoError(t, err)
+
+	// The last option takes precedence.
+	err = newTLSClient(SetClient(DefaultHTTPClient()), SetRootCAs(pemCerts)).Call(context.Background(), http.MethodGet, "/", nil, nil)
+	assert.NoError(t, err)
+	err = newTLSClient(SetRootCAs(pemCerts), SetClient(DefaultHTTPClient())).Call(context.Background(), http.MethodGet, "/", nil, nil)
+	assert.ErrorAs(t, err, new(*tls.CertificateVerificationError))
+
+	_, err = NewClient(SetNoEnv(), SetRootCAs([]byte("not a certificate")))
+	assert.ErrorIs(t, err, ErrInvalidRootCAs)
+}
+
 func TestClient_Options_SetPersonalTokenConfig(t *testing.T) {
 	client := newClient(t)
 
@@ -286,6 +365,69 @@ func TestClient_Options_SetAPITokenConfig(t *testing.T) {
 	assert.Empty(t, client.config.OrganizationID())
 }
 
+func TestClient_Options_Conflicting(t *testing.T) {
+	tests := []struct {
+		name    string
+		options []Option
+		err     string
+	}{
+		{
+			name: "personal and api token config",
+			options: []Option{
+				SetPersonalTokenConfig(personalToken, organizationID),
+				SetAPITokenConfig(apiToken),
+			},
+			err: "conflicting options: SetPersonalTokenConfig and SetAPITokenConfig are mutually exclusive",
+		},
+		{
+			name: "api and personal token config",
+			options: []Option{
+				SetAPITokenConfig(apiToken),
+				SetPersonalTokenConfig(personalToken, organizationID),
+			},
+			err: "conflicting options: SetAPITokenConfig and SetPersonalTokenConfig are mutually exclusive",
+		},
+		{
+			name: "no tracing and tracer provider",
+			options: []Option{
+				SetNoTracing(),
+				SetTracerProvider(sdktrace.NewTracerProvider()),
+			},
+			err: "conflicting options: SetNoTracing and SetTracerProvider are mutually exclusive",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := newClient(t)
+
+			err := client.Options(tt.options...)
+			require.ErrorIs(t, err, ErrConflictingOptions)
+			assert.EqualError(t, err, tt.err)
+
+			_, err = NewClient(append([]Option{SetNoEnv()}, tt.options...)...)
+			assert.ErrorIs(t, err, ErrConflictingOptions)
+		})
+	}
+}
+
+func TestClient_Options_ConflictingSeparateCalls(t *testing.T) {
+	client := newClient(t)
+
+	// Switching between mutually exclusive options using separate calls is
+	// fine.
+	err := client.Options(SetPersonalTokenConfig(personalToken, organizationID))
+	require.NoError(t, err)
+
+	err = client.Options(SetAPITokenConfig(apiToken))
+	require.NoError(t, err)
+
+	assert.Equal(t, apiToken, client.config.Token())
+
+	// Repeating the same option is fine as well.
+	err = client.Options(SetAPITokenConfig(apiToken), SetAPITokenConfig(apiToken))
+	require.NoError(t, err)
+}
+
 func TestClient_Options_SetOrganizationID(t *testing.T) {
 	client := newClient(t)
 
@@ -298,6 +440,18 @@ func TestClient_Options_SetOrganizationID(t *testing.T) {
 	assert.Equal(t, exp, client.config.OrganizationID())
 }
 
+func TestClient_Options_SetOrgID(t *testing.T) {
+	client1, client2 := newClient(t), newClient(t)
+
+	err := client1.Options(SetOrganizationID(organizationID))
+	require.NoError(t, err)
+
+	err = client2.Options(SetOrgID(organizationID))
+	require.NoError(t, err)
+
+	assert.Equal(t, client1.config, client2.config)
+}
+
 func TestClient_Options_SetURL(t *testing.T) {
 	client := newClient(t)
 
@@ -322,6 +476,298 @@ func TestClient_Options_SetUserAgent(t *testing.T) {
 	assert.Equal(t, exp, client.userAgent)
 }
 
+func TestClient_Options_SetStrictDecoding(t *testing.T) {
+	client := newClient(t)
+
+	err := client.Options(SetStrictDecoding(true))
+	require.NoError(t, err)
+
+	assert.True(t, client.strictDecoding)
+}
+
+func TestClient_Options_SetTransport(t *testing.T) {
+	client := newClient(t)
+
+	transport := http.DefaultTransport
+	err := client.Options(SetTransport(transport))
+	require.NoError(t, err)
+
+	assert.Equal(t, transport, client.httpClient.Transport)
+}
+
+func TestClient_Options_SetConnectionPool(t *testing.T) {
+	var (
+		conns    atomic.Int64
+		inFlight = make(chan struct{})
+	)
+	hf := func(w http.ResponseWriter, _ *http.Request) {
+		<-inFlight
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(hf))
+	srv.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			conns.Add(1)
+		}
+	}
+	srv.Start()
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient(
+		SetURL(srv.URL),
+		SetToken(personalToken),
+		SetNoEnv(),
+		SetConnectionPool(10, 10, 1),
+		SetIdleConnTimeout(time.Minute),
+	)
+	require.NoError(t, err)
+
+	// Concurrent requests share the single connection allowed per host.
+	var g errgroup.Group
+	for i := 0; i < 4; i++ {
+		g.Go(func() error {
+			return client.Call(context.Background(), http.MethodGet, "/", nil, nil)
+		})
+	}
+	for i := 0; i < 4; i++ {
+		inFlight <- struct{}{}
+	}
+	require.NoError(t, g.Wait())
+
+	assert.EqualValues(t, 1, conns.Load())
+
+	// Custom http clients are left untouched.
+	custom := &http.Client{Timeout: time.Second}
+	err = client.Options(SetClient(custom), SetConnectionPool(1, 1, 1), SetIdleConnTimeout(time.Second))
+	require.NoError(t, err)
+	assert.Same(t, custom, client.httpClient)
+
+	assert.Error(t, client.Options(SetConnectionPool(-1, 0, 0)))
+	assert.Error(t, client.Options(SetIdleConnTimeout(0)))
+}
+
+func TestClient_Options_SetUserAgentSuffix(t *testing.T) {
+	var userAgents []string
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		userAgents = append(userAgents, r.Header.Get("User-Agent"))
+		if len(userAgents) == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(hf))
+	t.Cleanup(srv.Close)
+
+	client, err := NewClient(
+		SetURL(srv.URL),
+		SetToken(personalToken),
+		SetUserAgentSuffix("my-adapter/1.0.0"),
+		SetNoEnv(),
+	)
+	require.NoError(t, err)
+
+	req, err := client.NewRequest(context.Background(), http.MethodPost, "/", strings.NewReader("{}"))
+	require.NoError(t, err)
+
+	_, err = client.Do(req, nil)
+	require.NoError(t, err)
+
+	// The suffix is kept on retries.
+	exp := client.userAgent + " my-adapter/1.0.0"
+	assert.Equal(t, []string{exp, exp}, userAgents)
+}
+
+func TestClient_Options_SetTracePropagation(t *testing.T) {
+	client := newClient(t)
+
+	exp := propagation.TraceContext{}
+	opt := SetTracePropagation(exp)
+
+	err := client.Options(opt)
+	assert.NoError(t, err)
+
+	assert.Equal(t, exp, client.propagator)
+}
+
+func TestClient_Options_SetHeaders(t *testing.T) {
+	client := newClient(t)
+
+	err := client.Options(SetHeaders(http.Header{"X-Tenant": []string{"acme"}}))
+	require.NoError(t, err)
+
+	err = client.Options(AddHeader("x-region", "eu"))
+	require.NoError(t, err)
+
+	req, err := client.NewRequest(context.Background(), http.MethodPost, "/", strings.NewReader("{}"))
+	require.NoError(t, err)
+
+	assert.Equal(t, "acme", req.Header.Get("X-Tenant"))
+	assert.Equal(t, "eu", req.Header.Get("X-Region"))
+	assert.Equal(t, defaultMediaType, req.Header.Get("Content-Type"))
+
+	// Setting the headers replaces all custom headers.
+	err = client.Options(SetHeaders(http.Header{"X-Tenant": []string{"axiom"}}))
+	require.NoError(t, err)
+
+	req, err = client.NewRequest(context.Background(), http.MethodGet, "/", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"axiom"}, req.Header.Values("X-Tenant"))
+	assert.Empty(t, req.Header.Get("X-Region"))
+}
+
+func TestClient_Options_SetHeaders_Reserved(t *testing.T) {
+	client := newClient(t)
+
+	err := client.Options(SetHeaders(http.Header{"Authorization": []string{"Bearer abc"}}))
+	assert.ErrorIs(t, err, ErrReservedHeader)
+	assert.EqualError(t, err, "reserved header: Authorization")
+
+	err = client.Options(AddHeader("content-type", "text/plain"))
+	assert.ErrorIs(t, err, ErrReservedHeader)
+	assert.EqualError(t, err, "reserved header: Content-Type")
+
+	assert.Empty(t, client.headers)
+}
+
+func TestClient_newRequest_TracePropagation(t *testing.T) {
+	client := newClient(t)
+
+	err := client.Options(SetTracePropagation(propagation.TraceContext{}))
+	require.NoError(t, err)
+
+	spanCtx := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x4b, 0xf9, 0x2f, 0x35, 0x77, 0xb3, 0x4d, 0xa6, 0xa3, 0xce, 0x92, 0x9d, 0x0e, 0x0e, 0x47, 0x36},
+		SpanID:     trace.SpanID{0x00, 0xf0, 0x67, 0xaa, 0x0b, 0xa9, 0x02, 0xb7},
+		TraceFlags: trace.FlagsSampled,
+	})
+	ctx := trace.ContextWithSpanContext(context.Background(), spanCtx)
+
+	req, err := client.NewRequest(ctx, http.MethodGet, "/", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01", req.Header.Get("traceparent"))
+
+	// Without an active span, no trace context is propagated.
+	req, err = client.NewRequest(context.Background(), http.MethodGet, "/", nil)
+	require.NoError(t, err)
+
+	assert.Empty(t, req.Header.Get("traceparent"))
+}
+
+func TestClient_Ping(t *testing.T) {
+	tests := []struct {
+		name   string
+		token  string
+		path   string
+		status int
+		err    error
+	}{
+		{
+			name:   "personal token",
+			token:  personalToken,
+			path:   "/v1/user",
+			status: http.StatusOK,
+		},
+		{
+			name:   "api token",
+			token:  apiToken,
+			path:   "/v2/tokens/self",
+			status: http.StatusOK,
+		},
+		{
+			name:   "unauthenticated",
+			token:  personalToken,
+			path:   "/v1/user",
+			status: http.StatusUnauthorized,
+			err:    ErrUnauthenticated,
+		},
+		{
+			name:   "unauthorized",
+			token:  apiToken,
+			path:   "/v2/tokens/self",
+			status: http.StatusForbidden,
+		},
+		{
+			name:   "server error",
+			token:  personalToken,
+			path:   "/v1/user",
+			status: http.StatusServiceUnavailable,
+			err:    newHTTPError(http.StatusServiceUnavailable),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var calls int
+			hf := func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodGet, r.Method)
+				calls++
+
+				w.Header().Set("Content-Type", mediaTypeJSON)
+				w.WriteHeader(tt.status)
+				_, _ = fmt.Fprint(w, `{}`)
+			}
+
+			client := setup(t, tt.path, hf)
+
+			err := client.Options(SetToken(tt.token))
+			require.NoError(t, err)
+
+			err = client.Ping(context.Background())
+			if tt.err != nil {
+				assert.ErrorIs(t, err, tt.err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, 1, calls)
+		})
+	}
+}
+
+func TestClient_Ping_Unreachable(t *testing.T) {
+	srv := httptest.NewServer(http.NotFoundHandler())
+	srv.Close()
+
+	client, err := NewClient(
+		SetURL(srv.URL),
+		SetToken(personalToken),
+		SetNoEnv(),
+	)
+	require.NoError(t, err)
+
+	err = client.Ping(context.Background())
+	assert.ErrorIs(t, err, ErrUnreachable)
+	assert.NotErrorIs(t, err, ErrUnauthenticated)
+}
+
+func TestClient_newRequest_ContextOrgID(t *testing.T) {
+	client := newClient(t)
+
+	err := client.Options(SetOrganizationID(organizationID))
+	require.NoError(t, err)
+
+	req, err := client.NewRequest(context.Background(), http.MethodGet, "/", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, organizationID, req.Header.Get(headerOrganizationID))
+
+	// The organization ID carried by the context overrides the configured one.
+	ctx := ContextWithOrgID(context.Background(), "other-org")
+	req, err = client.NewRequest(ctx, http.MethodGet, "/", nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "other-org", req.Header.Get(headerOrganizationID))
+	assert.Equal(t, organizationID, client.config.OrganizationID())
+
+	// A malformed organization ID is rejected.
+	ctx = ContextWithOrgID(context.Background(), personalToken)
+	_, err = client.NewRequest(ctx, http.MethodGet, "/", nil)
+	assert.ErrorIs(t, err, ErrInvalidOrganizationID)
+}
+
 func TestClient_newRequest_BadURL(t *testing.T) {
 	client := newClient(t)
 
@@ -394,6 +840,30 @@ func TestClient_do_ioWriter(t *testing.T) {
 	assert.Equal(t, content, buf.String())
 }
 
+func TestClient_do_Header(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Header().Set("Deprecation", "true")
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	client := setup(t, "/", hf)
+
+	req, err := client.NewRequest(context.Background(), http.MethodGet, "/", nil)
+	require.NoError(t, err)
+
+	resp, err := client.Do(req, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, "max-age=60", resp.Header.Get("Cache-Control"))
+	assert.Equal(t, "true", resp.Header.Get("Deprecation"))
+	assert.Empty(t, resp.Header.Get("Authorization"))
+
+	// The header is a copy of the header of the underlying response.
+	resp.Header.Set("Deprecation", "false")
+	assert.Equal(t, "true", resp.Response.Header.Get("Deprecation"))
+}
+
 func TestClient_do_HTTPError(t *testing.T) {
 	hf := func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("X-Axiom-Trace-Id", "abc")
@@ -410,32 +880,92 @@ func TestClient_do_HTTPError(t *testing.T) {
 		Status:  http.StatusBadRequest,
 		Message: http.StatusText(http.StatusBadRequest),
 	}) {
-		assert.EqualError(t, err, "API error 400: Bad Request")
+		assert.EqualError(t, err, "API error 400: Bad Request (trace id: abc)")
 		assert.Equal(t, "abc", err.(HTTPError).TraceID)
+		assert.Equal(t, http.StatusText(http.StatusBadRequest), err.(HTTPError).Raw)
 	}
 }
 
-func TestClient_do_HTTPError_Typed(t *testing.T) {
+func TestClient_do_HTTPError_RawTruncated(t *testing.T) {
+	page := "<html>" + strings.Repeat("a", maxRawErrorBytes) + "</html>"
+
 	hf := func(w http.ResponseWriter, r *http.Request) {
-		w.WriteHeader(http.StatusForbidden)
-		_, _ = w.Write([]byte(http.StatusText(http.StatusForbidden)))
+		w.Header().Set("Content-Type", "text/html")
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte(page))
 	}
 
 	client := setup(t, "/", hf)
+	require.NoError(t, client.Options(SetNoRetry()))
 
 	req, err := client.NewRequest(context.Background(), http.MethodGet, "/", nil)
 	require.NoError(t, err)
 
-	if _, err = client.Do(req, nil); assert.ErrorIs(t, err, ErrUnauthorized) {
-		assert.EqualError(t, err, "API error 403: Forbidden")
-	}
+	_, err = client.Do(req, nil)
+	var httpErr HTTPError
+	require.ErrorAs(t, err, &httpErr)
+
+	assert.Equal(t, http.StatusBadGateway, httpErr.Status)
+	assert.Len(t, httpErr.Raw, maxRawErrorBytes)
+	assert.Equal(t, page[:maxRawErrorBytes], httpErr.Raw)
 }
 
-func TestClient_do_HTTPError_JSON(t *testing.T) {
+func TestClient_do_MaxResponseBytes(t *testing.T) {
 	hf := func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Content-Type", mediaTypeJSON)
-		w.Header().Set("X-Axiom-Trace-Id", "abc")
-		w.WriteHeader(http.StatusBadRequest)
+		if r.URL.Query().Has("fail") {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = fmt.Fprint(w, `{"message":"bad request"}`)
+			return
+		}
+		_, _ = fmt.Fprintf(w, `{"foo":%q}`, strings.Repeat("a", 1024))
+	}
+
+	client := setup(t, "/", hf)
+
+	err := client.Options(SetMaxResponseBytes(128))
+	require.NoError(t, err)
+
+	var v map[string]string
+	err = client.Call(context.Background(), http.MethodGet, "/", nil, &v)
+	assert.ErrorIs(t, err, ErrResponseTooLarge)
+
+	err = client.Call(context.Background(), http.MethodGet, "/", nil, new(bytes.Buffer))
+	assert.ErrorIs(t, err, ErrResponseTooLarge)
+
+	// Small error responses are still decoded.
+	err = client.Call(context.Background(), http.MethodGet, "/?fail", nil, &v)
+	assert.EqualError(t, err, "API error 400: bad request")
+
+	// A body exactly the size of the limit is fine.
+	err = client.Options(SetMaxResponseBytes(1024 + 10))
+	require.NoError(t, err)
+
+	err = client.Call(context.Background(), http.MethodGet, "/", nil, &v)
+	assert.NoError(t, err)
+}
+
+func TestClient_do_HTTPError_Typed(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+		_, _ = w.Write([]byte(http.StatusText(http.StatusForbidden)))
+	}
+
+	client := setup(t, "/", hf)
+
+	req, err := client.NewRequest(context.Background(), http.MethodGet, "/", nil)
+	require.NoError(t, err)
+
+	if _, err = client.Do(req, nil); assert.ErrorIs(t, err, ErrUnauthorized) {
+		assert.EqualError(t, err, "API error 403: Forbidden")
+	}
+}
+
+func TestClient_do_HTTPError_JSON(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		w.Header().Set("X-Axiom-Trace-Id", "abc")
+		w.WriteHeader(http.StatusBadRequest)
 
 		assert.NoError(t, json.NewEncoder(w).Encode(HTTPError{
 			Message: "This is a Bad Request error",
@@ -451,7 +981,7 @@ func TestClient_do_HTTPError_JSON(t *testing.T) {
 		Status:  http.StatusBadRequest,
 		Message: "This is a Bad Request error",
 	}) {
-		assert.EqualError(t, err, "API error 400: This is a Bad Request error")
+		assert.EqualError(t, err, "API error 400: This is a Bad Request error (trace id: abc)")
 		assert.Equal(t, "abc", err.(HTTPError).TraceID)
 	}
 }
@@ -473,6 +1003,27 @@ func TestClient_do_HTTPError_Unauthenticated(t *testing.T) {
 
 	_, err = client.Do(req, nil)
 	assert.ErrorIs(t, err, ErrUnauthenticated)
+	assert.NotErrorIs(t, err, ErrTokenExpired)
+}
+
+func TestClient_do_HTTPError_TokenExpired(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		w.WriteHeader(http.StatusUnauthorized)
+
+		assert.NoError(t, json.NewEncoder(w).Encode(HTTPError{
+			Message: "Token expired",
+		}))
+	}
+
+	client := setup(t, "/", hf)
+
+	req, err := client.NewRequest(context.Background(), http.MethodGet, "/", nil)
+	require.NoError(t, err)
+
+	_, err = client.Do(req, nil)
+	assert.ErrorIs(t, err, ErrTokenExpired)
+	assert.ErrorIs(t, err, ErrUnauthenticated)
 }
 
 func TestClient_do_RateLimit(t *testing.T) {
@@ -520,10 +1071,305 @@ func TestClient_do_RateLimit(t *testing.T) {
 	resp, err := client.Do(req, nil)
 
 	if assert.ErrorIs(t, err, expErr) {
-		assert.EqualError(t, err, "rate limit exceeded: try again in 59m59s")
+		assert.EqualError(t, err, "rate limit exceeded: try again in 59m59s (trace id: abc)")
 		assert.Equal(t, "abc", err.(LimitError).TraceID)
 	}
 	assert.Equal(t, expErr.Limit, resp.Limit)
+	assert.Positive(t, resp.Duration)
+	assert.Equal(t, 1, resp.Attempts)
+	assert.False(t, resp.Retried)
+}
+
+func TestClient_do_RateLimit_ClockSkew(t *testing.T) {
+	// The server clock is an hour ahead of the local one and the limit resets
+	// an hour later, as seen by the server.
+	serverNow := time.Now().Add(time.Hour).Truncate(time.Second)
+	reset := serverNow.Add(time.Hour)
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Date", serverNow.UTC().Format(http.TimeFormat))
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		w.Header().Set(headerRateScope, "anonymous")
+		w.Header().Set(headerRateLimit, "1000")
+		w.Header().Set(headerRateRemaining, "0")
+		w.Header().Set(headerRateReset, strconv.FormatInt(reset.Unix(), 10))
+		w.WriteHeader(http.StatusTooManyRequests)
+		assert.NoError(t, json.NewEncoder(w).Encode(HTTPError{
+			Message: "limit exceeded",
+		}))
+	}
+
+	client := setup(t, "/", hf)
+
+	req, err := client.NewRequest(context.Background(), http.MethodGet, "/", nil)
+	require.NoError(t, err)
+
+	resp, err := client.Do(req, nil)
+	assert.EqualError(t, err, "rate limit exceeded: try again in 59m59s")
+	assert.Equal(t, reset, resp.Limit.Reset)
+	assert.InDelta(t, time.Hour, resp.Limit.clockSkew, float64(time.Second))
+
+	// The short-circuit corrects for the skew as well.
+	_, err = client.Do(req, nil)
+	assert.EqualError(t, err, "rate limit exceeded: try again in 59m59s")
+}
+
+func TestParseClockSkew(t *testing.T) {
+	now := time.Date(2023, 3, 21, 13, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		date string
+		want time.Duration
+	}{
+		{
+			name: "no date",
+		},
+		{
+			name: "malformed date",
+			date: "yesterday",
+		},
+		{
+			name: "in sync",
+			date: now.Format(http.TimeFormat),
+		},
+		{
+			name: "below resolution",
+			date: now.Add(-time.Second).Format(http.TimeFormat),
+		},
+		{
+			name: "server ahead",
+			date: now.Add(time.Minute).Format(http.TimeFormat),
+			want: time.Minute,
+		},
+		{
+			name: "server behind",
+			date: now.Add(-time.Minute).Format(http.TimeFormat),
+			want: -time.Minute,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{Header: make(http.Header)}
+			if tt.date != "" {
+				resp.Header.Set("Date", tt.date)
+			}
+			assert.Equal(t, tt.want, parseClockSkew(resp, now))
+		})
+	}
+}
+
+func TestClient_do_RateLimit_ShortCircuit(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	var calls int
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		w.Header().Set(headerRateScope, "anonymous")
+		w.Header().Set(headerRateLimit, "1000")
+		w.Header().Set(headerRateRemaining, "0")
+		w.Header().Set(headerRateReset, strconv.FormatInt(reset.Unix(), 10))
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	client := setup(t, "/", hf)
+
+	err := client.Options(SetLimitShortCircuit())
+	require.NoError(t, err)
+
+	req, err := client.NewRequest(context.Background(), http.MethodGet, "/", nil)
+	require.NoError(t, err)
+
+	// The first request exhausts the limit and succeeds.
+	_, err = client.Do(req, nil)
+	require.NoError(t, err)
+
+	// Subsequent requests of any kind must not be sent until the limit resets.
+	for _, path := range []string{"/", "/v1/datasets/test/ingest", "/v1/datasets/_apl"} {
+		req, err = client.NewRequest(context.Background(), http.MethodPost, path, nil)
+		require.NoError(t, err)
+
+		_, err = client.Do(req, nil)
+		var limitErr LimitError
+		if assert.ErrorAs(t, err, &limitErr) {
+			assert.Equal(t, http.StatusTooManyRequests, limitErr.Status)
+			assert.Equal(t, "Too Many Requests", limitErr.Message)
+			assert.Equal(t, reset, limitErr.Limit.Reset)
+		}
+	}
+
+	assert.Equal(t, 1, calls)
+}
+
+func TestClient_do_RateLimit_NoShortCircuit(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	var calls int
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		calls++
+
+		w.Header().Set(headerRateScope, "anonymous")
+		w.Header().Set(headerRateLimit, "1000")
+		w.Header().Set(headerRateRemaining, "0")
+		w.Header().Set(headerRateReset, strconv.FormatInt(reset.Unix(), 10))
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	client := setup(t, "/", hf)
+
+	// Without SetLimitShortCircuit, requests are sent regardless of the
+	// exhausted limit.
+	for i := 0; i < 2; i++ {
+		req, err := client.NewRequest(context.Background(), http.MethodGet, "/", nil)
+		require.NoError(t, err)
+
+		_, err = client.Do(req, nil)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, 2, calls)
+}
+
+func TestClient_do_RateLimit_ShortCircuit_LimitType(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	var ingestCalls, queryCalls int
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1/datasets/_apl":
+			queryCalls++
+			w.Header().Set(headerQueryLimit, "1000")
+			w.Header().Set(headerQueryRemaining, "0")
+			w.Header().Set(headerQueryReset, strconv.FormatInt(reset.Unix(), 10))
+		case "/v1/datasets/test/ingest":
+			ingestCalls++
+			w.Header().Set(headerIngestLimit, "1000")
+			w.Header().Set(headerIngestRemaining, "999")
+			w.Header().Set(headerIngestReset, strconv.FormatInt(reset.Unix(), 10))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	client := setup(t, "/", hf)
+
+	err := client.Options(SetLimitShortCircuit())
+	require.NoError(t, err)
+
+	do := func(path string) error {
+		req, err := client.NewRequest(context.Background(), http.MethodPost, path, nil)
+		require.NoError(t, err)
+
+		_, err = client.Do(req, nil)
+		return err
+	}
+
+	// Exhaust the query limit.
+	require.NoError(t, do("/v1/datasets/_apl"))
+
+	// Queries are short-circuited...
+	err = do("/v1/datasets/_apl")
+	var limitErr LimitError
+	if assert.ErrorAs(t, err, &limitErr) {
+		assert.Equal(t, httpStatusLimitExceeded, limitErr.Status)
+		assert.Equal(t, "Limit Exceeded", limitErr.Message)
+		assert.EqualError(t, err, "query limit exceeded: try again in 59m59s")
+	}
+	assert.Equal(t, 1, queryCalls)
+
+	// ...but ingestion still proceeds.
+	require.NoError(t, do("/v1/datasets/test/ingest"))
+	require.NoError(t, do("/v1/datasets/test/ingest"))
+	assert.Equal(t, 2, ingestCalls)
+}
+
+// TestClient_Concurrent fires concurrent requests to make sure the limit
+// bookkeeping is free of data races. Run with the "-race" flag.
+func TestClient_Concurrent(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	var calls atomic.Int64
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		n := calls.Add(1)
+
+		w.Header().Set(headerRateScope, "user")
+		w.Header().Set(headerRateLimit, "1000")
+		w.Header().Set(headerRateRemaining, strconv.FormatInt(1000-n, 10))
+		w.Header().Set(headerRateReset, strconv.FormatInt(reset.Unix(), 10))
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	client := setup(t, "/", hf)
+
+	const n = 100
+
+	var wg sync.WaitGroup
+	wg.Add(n)
+	for i := 0; i < n; i++ {
+		go func() {
+			defer wg.Done()
+
+			req, err := client.NewRequest(context.Background(), http.MethodGet, "/", nil)
+			if !assert.NoError(t, err) {
+				return
+			}
+
+			_, err = client.Do(req, nil)
+			assert.NoError(t, err)
+
+			_ = client.LastLimit()
+			_, _ = client.LimitRemaining(LimitScopeUser)
+		}()
+	}
+	wg.Wait()
+
+	assert.EqualValues(t, n, calls.Load())
+
+	remaining, _ := client.LimitRemaining(LimitScopeUser)
+	assert.GreaterOrEqual(t, remaining, 1000-n)
+	assert.Less(t, remaining, 1000)
+}
+
+func TestClient_LastLimit(t *testing.T) {
+	reset := time.Now().Add(time.Hour).Truncate(time.Second)
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set(headerRateScope, "user")
+		w.Header().Set(headerRateLimit, "1000")
+		w.Header().Set(headerRateRemaining, "5")
+		w.Header().Set(headerRateReset, strconv.FormatInt(reset.Unix(), 10))
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	client := setup(t, "/", hf)
+
+	// Nothing is known before the first request.
+	assert.Zero(t, client.LastLimit())
+	remaining, limitReset := client.LimitRemaining(LimitScopeUser)
+	assert.Equal(t, -1, remaining)
+	assert.Zero(t, limitReset)
+
+	req, err := client.NewRequest(context.Background(), http.MethodGet, "/", nil)
+	require.NoError(t, err)
+
+	_, err = client.Do(req, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, Limit{
+		Scope:     LimitScopeUser,
+		Limit:     1000,
+		Remaining: 5,
+		Reset:     reset,
+
+		limitType: limitRate,
+	}, client.LastLimit())
+
+	remaining, limitReset = client.LimitRemaining(LimitScopeUser)
+	assert.Equal(t, 5, remaining)
+	assert.Equal(t, reset, limitReset)
+
+	remaining, _ = client.LimitRemaining(LimitScopeOrganization)
+	assert.Equal(t, -1, remaining)
 }
 
 func TestClient_do_UnprivilegedToken(t *testing.T) {
@@ -534,6 +1380,18 @@ func TestClient_do_UnprivilegedToken(t *testing.T) {
 
 	_, err = client.NewRequest(context.Background(), http.MethodGet, "/", nil)
 	require.ErrorIs(t, err, ErrUnprivilegedToken)
+
+	// The error names the offending operation.
+	_, err = client.Users.Current(context.Background())
+	require.ErrorIs(t, err, ErrUnprivilegedToken)
+	assert.EqualError(t, err, "using API token for non-ingest or non-query operation: GET /v1/user")
+
+	// Unless the check is disabled.
+	err = client.Options(SetNoAPITokenPathCheck())
+	require.NoError(t, err)
+
+	_, err = client.NewRequest(context.Background(), http.MethodGet, "/", nil)
+	require.NoError(t, err)
 }
 
 func TestClient_do_RedirectLoop(t *testing.T) {
@@ -550,6 +1408,64 @@ func TestClient_do_RedirectLoop(t *testing.T) {
 	assert.IsType(t, new(url.Error), err)
 }
 
+func TestClient_do_MaxRedirects(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		hops, _ := strconv.Atoi(r.URL.Query().Get("hops"))
+		if hops > 0 {
+			http.Redirect(w, r, fmt.Sprintf("/?hops=%d", hops-1), http.StatusFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	client := setup(t, "/", hf)
+
+	err := client.Options(SetMaxRedirects(2))
+	require.NoError(t, err)
+
+	err = client.Call(context.Background(), http.MethodGet, "/?hops=2", nil, nil)
+	assert.NoError(t, err)
+
+	err = client.Call(context.Background(), http.MethodGet, "/?hops=3", nil, nil)
+	assert.ErrorIs(t, err, ErrTooManyRedirects)
+
+	err = client.Options(SetMaxRedirects(-1))
+	assert.EqualError(t, err, "invalid number of redirects: -1")
+}
+
+func TestClient_do_FollowRedirects(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/login" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		http.Redirect(w, r, "/login", http.StatusFound)
+	}
+
+	client := setup(t, "/", hf)
+
+	err := client.Options(SetFollowRedirects(false))
+	require.NoError(t, err)
+
+	req, err := client.NewRequest(context.Background(), http.MethodGet, "/", nil)
+	require.NoError(t, err)
+
+	resp, err := client.Do(req, nil)
+	require.Error(t, err)
+
+	assert.Equal(t, http.StatusFound, resp.StatusCode)
+	assert.Equal(t, "/login", resp.Header.Get("Location"))
+	assert.ErrorAs(t, err, new(HTTPError))
+	assert.EqualError(t, err, "API error 302: Found: /login")
+
+	// Following redirects again restores the default behaviour.
+	err = client.Options(SetFollowRedirects(true))
+	require.NoError(t, err)
+
+	err = client.Call(context.Background(), http.MethodGet, "/", nil, nil)
+	assert.NoError(t, err)
+}
+
 func TestClient_do_ValidOnlyAPITokenPaths(t *testing.T) {
 	hf := func(w http.ResponseWriter, r *http.Request) {}
 
@@ -627,6 +1543,428 @@ func TestClient_do_Backoff(t *testing.T) {
 	assert.True(t, badGatewayCalled)
 	assert.True(t, gatewayTimeoutCalled)
 	assert.Equal(t, 3, getBodyCounter)
+	assert.Positive(t, resp.Duration)
+	assert.Equal(t, 4, resp.Attempts)
+	assert.True(t, resp.Retried)
+}
+
+type observedRequest struct {
+	method, path string
+	status       int
+}
+
+type testMetricsObserver struct {
+	mtx      sync.Mutex
+	requests []observedRequest
+	retries  []string
+}
+
+func (o *testMetricsObserver) ObserveRequest(method, path string, status int, dur time.Duration) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	o.requests = append(o.requests, observedRequest{method, path, status})
+}
+
+func (o *testMetricsObserver) ObserveRetry(path string) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	o.retries = append(o.retries, path)
+}
+
+func TestClient_do_Metrics(t *testing.T) {
+	var calls int
+	hf := func(w http.ResponseWriter, _ *http.Request) {
+		if calls++; calls == 1 {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	client := setup(t, "/v1/test", hf)
+
+	var observer testMetricsObserver
+	err := client.Options(SetMetrics(&observer))
+	require.NoError(t, err)
+
+	req, err := client.NewRequest(context.Background(), http.MethodPost, "/v1/test", strings.NewReader("{}"))
+	require.NoError(t, err)
+
+	_, err = client.Do(req, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []observedRequest{
+		{http.MethodPost, "/v1/test", http.StatusInternalServerError},
+		{http.MethodPost, "/v1/test", http.StatusNoContent},
+	}, observer.requests)
+	assert.Equal(t, []string{"/v1/test"}, observer.retries)
+}
+
+func TestClient_do_DebugLogger(t *testing.T) {
+	var calls int
+	hf := func(w http.ResponseWriter, _ *http.Request) {
+		if calls++; calls == 1 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	client := setup(t, "/v1/test", hf)
+
+	var entries []DebugEntry
+	err := client.Options(SetDebugLogger(func(_ context.Context, entry DebugEntry) {
+		entries = append(entries, entry)
+	}))
+	require.NoError(t, err)
+
+	req, err := client.NewRequest(context.Background(), http.MethodPost, "/v1/test", strings.NewReader("{}"))
+	require.NoError(t, err)
+
+	_, err = client.Do(req, nil)
+	require.NoError(t, err)
+
+	require.Len(t, entries, 2)
+
+	assert.Equal(t, http.MethodPost, entries[0].Method)
+	assert.Equal(t, req.URL.String(), entries[0].URL)
+	assert.Equal(t, http.StatusBadGateway, entries[0].Status)
+	assert.Equal(t, 1, entries[0].Attempt)
+	assert.Empty(t, entries[0].RetryReason)
+	assert.NoError(t, entries[0].Err)
+
+	assert.Equal(t, http.StatusNoContent, entries[1].Status)
+	assert.Equal(t, 2, entries[1].Attempt)
+	assert.Equal(t, "got status code 502", entries[1].RetryReason)
+
+	for _, entry := range entries {
+		assert.Equal(t, "Bearer xapt-REDACTED", entry.Header.Get("Authorization"))
+	}
+
+	// The original request must not be altered.
+	assert.Equal(t, "Bearer "+personalToken, req.Header.Get("Authorization"))
+}
+
+func TestClient_do_TokenFailover(t *testing.T) {
+	const secondaryToken = "xapt-YYYYYYYY-YYYY-YYYY-YYYY-YYYYYYYYYYYY" //nolint:gosec // Chill, it's just testing.
+
+	var tokens []string
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		b, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Equal(t, "{}", string(b))
+
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		tokens = append(tokens, token)
+		if token != secondaryToken {
+			w.Header().Set("Content-Type", mediaTypeJSON)
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = fmt.Fprint(w, `{"message":"token revoked"}`)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	client := setup(t, "/v1/test", hf)
+
+	var switches int
+	err := client.Options(
+		SetAccessTokens(personalToken, secondaryToken),
+		SetTokenFailoverHandler(func() { switches++ }),
+	)
+	require.NoError(t, err)
+
+	for i := 0; i < 2; i++ {
+		req, err := client.NewRequest(context.Background(), http.MethodPost, "/v1/test", strings.NewReader("{}"))
+		require.NoError(t, err)
+
+		_, err = client.Do(req, nil)
+		require.NoError(t, err)
+	}
+
+	assert.Equal(t, []string{personalToken, secondaryToken, secondaryToken}, tokens)
+	assert.Equal(t, 1, switches)
+
+	// Configuring another token disables the failover.
+	err = client.Options(SetToken("xapt-ZZZZZZZZ-ZZZZ-ZZZZ-ZZZZ-ZZZZZZZZZZZZ"))
+	require.NoError(t, err)
+
+	req, err := client.NewRequest(context.Background(), http.MethodPost, "/v1/test", strings.NewReader("{}"))
+	require.NoError(t, err)
+
+	_, err = client.Do(req, nil)
+	require.ErrorIs(t, err, ErrUnauthenticated)
+}
+
+func TestSetAccessTokens(t *testing.T) {
+	_, err := NewClient(SetNoEnv(), SetOrganizationID(organizationID), SetAccessTokens(personalToken, apiToken))
+	assert.ErrorIs(t, err, ErrTokenMismatch)
+
+	_, err = NewClient(SetNoEnv(), SetAccessTokens(apiToken, "invalid"))
+	assert.Error(t, err)
+
+	_, err = NewClient(SetNoEnv(), SetAccessTokens(apiToken, apiToken))
+	assert.NoError(t, err)
+}
+
+func TestClient_newRequest_TokenProvider(t *testing.T) {
+	const rotatedToken = "xapt-YYYYYYYY-YYYY-YYYY-YYYY-YYYYYYYYYYYY" //nolint:gosec // Chill, it's just testing.
+
+	var tokens []string
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		tokens = append(tokens, token)
+		if token != rotatedToken {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	client := setup(t, "/v1/test", hf)
+
+	var (
+		calls    int
+		provided = personalToken
+	)
+	err := client.Options(
+		SetTokenProvider(func(context.Context) (string, error) {
+			calls++
+			return provided, nil
+		}),
+		SetTokenProviderTTL(time.Hour),
+	)
+	require.NoError(t, err)
+
+	send := func() error {
+		req, err := client.NewRequest(context.Background(), http.MethodGet, "/v1/test", nil)
+		if err != nil {
+			return err
+		}
+		_, err = client.Do(req, nil)
+		return err
+	}
+
+	// The rejected token is fetched again for the next request.
+	require.ErrorIs(t, send(), ErrUnauthenticated)
+	provided = rotatedToken
+	require.NoError(t, send())
+	require.NoError(t, send())
+
+	assert.Equal(t, []string{personalToken, rotatedToken, rotatedToken}, tokens)
+	assert.Equal(t, 2, calls)
+
+	// Provider errors abort the request.
+	providerErr := errors.New("secrets manager unavailable")
+	err = client.Options(SetTokenProvider(func(context.Context) (string, error) {
+		return "", providerErr
+	}))
+	require.NoError(t, err)
+
+	err = send()
+	assert.ErrorIs(t, err, ErrTokenProvider)
+	assert.ErrorIs(t, err, providerErr)
+	assert.Len(t, tokens, 3)
+}
+
+func TestNewClient_TokenProvider(t *testing.T) {
+	client, err := NewClient(
+		SetNoEnv(),
+		SetTokenProvider(func(context.Context) (string, error) { return apiToken, nil }),
+	)
+	require.NoError(t, err)
+
+	_, err = client.NewRequest(context.Background(), http.MethodGet, "/v1/user", nil)
+	assert.ErrorIs(t, err, ErrUnprivilegedToken)
+
+	err = client.Options(SetTokenProviderTTL(-time.Second))
+	assert.Error(t, err)
+}
+
+func TestClient_do_Deprecation(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/v1/old" {
+			w.Header().Set("Deprecation", "true")
+			w.Header().Set("Sunset", "Wed, 11 Nov 2026 23:59:59 GMT")
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	client := setup(t, "/", hf)
+
+	var (
+		warnings []string
+		entries  []DebugEntry
+	)
+	err := client.Options(
+		SetDeprecationHandler(func(warning string) {
+			warnings = append(warnings, warning)
+		}),
+		SetDebugLogger(func(_ context.Context, entry DebugEntry) {
+			entries = append(entries, entry)
+		}),
+	)
+	require.NoError(t, err)
+
+	for _, path := range []string{"/v1/old", "/v1/new"} {
+		req, err := client.NewRequest(context.Background(), http.MethodGet, path, nil)
+		require.NoError(t, err)
+
+		_, err = client.Do(req, nil)
+		require.NoError(t, err)
+	}
+
+	const exp = "GET /v1/old is deprecated and will be removed after Wed, 11 Nov 2026 23:59:59 GMT"
+	assert.Equal(t, []string{exp}, warnings)
+	if assert.Len(t, entries, 2) {
+		assert.Equal(t, exp, entries[0].Deprecation)
+		assert.Empty(t, entries[1].Deprecation)
+	}
+}
+
+func TestDeprecationWarning(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/v1/datasets/test/ingest", nil)
+
+	tests := []struct {
+		deprecation string
+		sunset      string
+		exp         string
+	}{
+		{"", "", ""},
+		{"false", "", ""},
+		{"true", "", "POST /v1/datasets/test/ingest is deprecated"},
+		{"@1688169599", "", "POST /v1/datasets/test/ingest is deprecated since @1688169599"},
+		{"", "Wed, 11 Nov 2026 23:59:59 GMT", "POST /v1/datasets/test/ingest is deprecated and will be removed after Wed, 11 Nov 2026 23:59:59 GMT"},
+	}
+	for _, tt := range tests {
+		header := make(http.Header)
+		if tt.deprecation != "" {
+			header.Set("Deprecation", tt.deprecation)
+		}
+		if tt.sunset != "" {
+			header.Set("Sunset", tt.sunset)
+		}
+		assert.Equal(t, tt.exp, deprecationWarning(req, header))
+	}
+}
+
+func TestClient_do_Backoff_RetryBudget(t *testing.T) {
+	var calls atomic.Int64
+	hf := func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	client := setup(t, "/", hf)
+
+	err := client.Options(SetRetryBudget(time.Millisecond * 500))
+	require.NoError(t, err)
+
+	req, err := client.NewRequest(context.Background(), http.MethodPost, "/", strings.NewReader("{}"))
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = client.Do(req, nil)
+	require.EqualError(t, err, "got status code 503")
+
+	assert.Less(t, time.Since(start), time.Second*2)
+	assert.Greater(t, calls.Load(), int64(1))
+
+	err = client.Options(SetRetryBudget(0))
+	assert.EqualError(t, err, "invalid retry budget: 0s")
+}
+
+func TestClient_do_Backoff_RetryBudget_SlowAttempt(t *testing.T) {
+	hf := func(w http.ResponseWriter, _ *http.Request) {
+		// Respond slower than the retry budget.
+		time.Sleep(time.Millisecond * 300)
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	client := setup(t, "/", hf)
+
+	err := client.Options(SetRetryBudget(time.Millisecond * 100))
+	require.NoError(t, err)
+
+	// A slow but healthy attempt is not aborted by the budget.
+	req, err := client.NewRequest(context.Background(), http.MethodPost, "/", strings.NewReader("{}"))
+	require.NoError(t, err)
+
+	_, err = client.Do(req, nil)
+	require.NoError(t, err)
+}
+
+func TestClient_do_Backoff_RetryBudget_ContextDeadline(t *testing.T) {
+	var calls atomic.Int64
+	hf := func(w http.ResponseWriter, _ *http.Request) {
+		calls.Add(1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	client := setup(t, "/", hf)
+
+	// The deadline of the context is tighter than the default budget, so no
+	// retry is started that would begin after it and the error of the last
+	// attempt is returned.
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*500)
+	defer cancel()
+
+	req, err := client.NewRequest(ctx, http.MethodPost, "/", strings.NewReader("{}"))
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = client.Do(req, nil)
+	require.EqualError(t, err, "got status code 503")
+
+	assert.Less(t, time.Since(start), time.Millisecond*500)
+	assert.Greater(t, calls.Load(), int64(1))
+}
+
+func TestClient_do_Backoff_ContextCanceled(t *testing.T) {
+	var calls int
+	hf := func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	client := setup(t, "/", hf)
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond*300)
+	defer cancel()
+
+	req, err := client.NewRequest(ctx, http.MethodPost, "/", strings.NewReader("{}"))
+	require.NoError(t, err)
+
+	start := time.Now()
+	_, err = client.Do(req, nil)
+	require.Error(t, err)
+
+	// Retrying must stop as soon as the context is done.
+	assert.Less(t, time.Since(start), time.Second)
+	assert.Less(t, calls, 4)
+}
+
+func TestClient_do_Backoff_ContextCanceledDuringBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls int
+	hf := func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		// Cancel while the client is waiting for the next retry.
+		cancel()
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+
+	client := setup(t, "/", hf)
+
+	req, err := client.NewRequest(ctx, http.MethodPost, "/", strings.NewReader("{}"))
+	require.NoError(t, err)
+
+	_, err = client.Do(req, nil)
+	require.ErrorIs(t, err, context.Canceled)
+
+	assert.Equal(t, 1, calls)
 }
 
 func TestClient_do_Backoff_NoRetryOn400(t *testing.T) {
@@ -650,49 +1988,109 @@ func TestClient_do_Backoff_NoRetryOn400(t *testing.T) {
 
 func TestAPITokenPathRegex(t *testing.T) {
 	tests := []struct {
-		input string
-		match bool
+		method string
+		input  string
+		match  bool
 	}{
 		{
-			input: "/v1/datasets/test/ingest",
-			match: true,
+			method: http.MethodPost,
+			input:  "/v1/datasets/test/ingest",
+			match:  true,
 		},
 		{
-			input: "/v1/datasets/test/ingest?timestamp-format=unix",
-			match: true,
+			method: http.MethodPost,
+			input:  "/v1/datasets/test/ingest?timestamp-format=unix",
+			match:  true,
 		},
 		{
-			input: "/v1/datasets/test/query",
-			match: true,
+			method: http.MethodPost,
+			input:  "/v1/datasets/test/query",
+			match:  true,
 		},
 		{
-			input: "/v1/datasets/_apl",
-			match: true,
+			method: http.MethodPost,
+			input:  "/v1/datasets/_apl",
+			match:  true,
 		},
 		{
-			input: "/v1/datasets/test/query?nocache=true",
-			match: true,
+			method: http.MethodPost,
+			input:  "/v1/datasets/test/query?nocache=true",
+			match:  true,
 		},
 		{
-			input: "/v1/datasets/_apl?nocache=true",
-			match: true,
+			method: http.MethodPost,
+			input:  "/v1/datasets/_apl?nocache=true",
+			match:  true,
 		},
 		{
-			input: "/v1/datasets//query",
-			match: false,
+			method: http.MethodPost,
+			input:  "/v1/datasets//query",
+			match:  false,
 		},
 		{
-			input: "/v1/datasets/query",
-			match: false,
+			method: http.MethodPost,
+			input:  "/v1/datasets/query",
+			match:  false,
 		},
 		{
-			input: "/v1/datasets/test/elastic",
-			match: false,
+			method: http.MethodPost,
+			input:  "/v1/datasets/test/elastic",
+			match:  false,
+		},
+		{
+			method: http.MethodGet,
+			input:  "/v1/datasets",
+			match:  true,
+		},
+		{
+			method: http.MethodGet,
+			input:  "/v1/datasets/test",
+			match:  true,
+		},
+		{
+			method: http.MethodGet,
+			input:  "/v1/version",
+			match:  true,
+		},
+		{
+			method: http.MethodGet,
+			input:  "/v2/tokens/self",
+			match:  true,
+		},
+		{
+			method: http.MethodDelete,
+			input:  "/v2/tokens/self",
+			match:  false,
+		},
+		{
+			method: http.MethodGet,
+			input:  "/v2/tokens",
+			match:  false,
+		},
+		{
+			method: http.MethodPost,
+			input:  "/v1/datasets",
+			match:  false,
+		},
+		{
+			method: http.MethodDelete,
+			input:  "/v1/datasets/test",
+			match:  false,
+		},
+		{
+			method: http.MethodGet,
+			input:  "/v1/datasets/test/elastic",
+			match:  false,
+		},
+		{
+			method: http.MethodGet,
+			input:  "/v1/user",
+			match:  false,
 		},
 	}
 	for _, tt := range tests {
-		t.Run(tt.input, func(t *testing.T) {
-			assert.Equal(t, tt.match, validOnlyAPITokenPaths.MatchString(tt.input))
+		t.Run(tt.method+" "+tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.match, isValidAPITokenRequest(tt.method, tt.input))
 		})
 	}
 }
@@ -707,7 +2105,7 @@ func setup(t *testing.T, path string, handler http.HandlerFunc) *Client {
 	r.HandleFunc(path, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		assert.NotEmpty(t, r.Header.Get("Authorization"), "no authorization header present on the request")
 		assert.Equal(t, mediaTypeJSON, r.Header.Get("Accept"), "bad accept header present on the request")
-		assert.Equal(t, "axiom-go", r.Header.Get("User-Agent"), "bad user-agent header present on the request")
+		assert.True(t, strings.HasPrefix(r.Header.Get("User-Agent"), "axiom-go/"), "bad user-agent header present on the request")
 		if organizationIDHeader := r.Header.Get("X-Axiom-Org-Id"); organizationIDHeader != "" {
 			assert.Equal(t, organizationID, organizationIDHeader, "bad x-axiom-org-id header present on the request")
 		}
@@ -749,3 +2147,26 @@ func newClient(t *testing.T) *Client {
 
 	return client
 }
+
+func TestClient_Ping_Context(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}
+
+	client := setup(t, "/v1/user", hf)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := client.Ping(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NotErrorIs(t, err, ErrUnreachable)
+
+	ctx, cancel = context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	err = client.Ping(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.NotErrorIs(t, err, ErrUnreachable)
+}
@@ -0,0 +1,53 @@
+package axiom
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Codec encodes and decodes the JSON payloads of requests and responses. It
+// allows for replacing the [encoding/json] package of the standard library,
+// which is used by default, with a faster JSON library. Set it using
+// [SetCodec]. Implementations must produce and accept the same JSON as the
+// standard library and be safe for concurrent use.
+type Codec interface {
+	// NewEncoder returns a new [Encoder] that writes to w.
+	NewEncoder(w io.Writer) Encoder
+	// NewDecoder returns a new [Decoder] that reads from r.
+	NewDecoder(r io.Reader) Decoder
+}
+
+// Encoder encodes JSON values to an output stream. It is implemented by
+// [json.Encoder].
+type Encoder interface {
+	// Encode writes the JSON encoding of v to the stream, followed by a
+	// newline character.
+	Encode(v any) error
+}
+
+// Decoder decodes a JSON value from an input stream. It is implemented by
+// [json.Decoder].
+type Decoder interface {
+	// Decode reads the next JSON value from the input and stores it in the
+	// value pointed to by v.
+	Decode(v any) error
+	// DisallowUnknownFields causes the Decoder to return an error when the
+	// destination is a struct and the input contains object keys which do not
+	// match any non-ignored, exported fields in the destination. It is used
+	// for [SetStrictDecoding].
+	DisallowUnknownFields()
+}
+
+// stdCodec is the default [Codec] which uses the [encoding/json] package of the
+// standard library.
+type stdCodec struct{}
+
+// NewEncoder implements [Codec].
+func (stdCodec) NewEncoder(w io.Writer) Encoder {
+	return json.NewEncoder(w)
+}
+
+// NewDecoder implements [Codec].
+func (stdCodec) NewDecoder(r io.Reader) Decoder {
+	return json.NewDecoder(r)
+}
@@ -0,0 +1,70 @@
+package axiom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testCodec struct {
+	encoders, decoders atomic.Int64
+	strict             atomic.Bool
+}
+
+func (c *testCodec) NewEncoder(w io.Writer) Encoder {
+	c.encoders.Add(1)
+	return json.NewEncoder(w)
+}
+
+func (c *testCodec) NewDecoder(r io.Reader) Decoder {
+	c.decoders.Add(1)
+	return &testDecoder{Decoder: json.NewDecoder(r), codec: c}
+}
+
+type testDecoder struct {
+	*json.Decoder
+
+	codec *testCodec
+}
+
+func (d *testDecoder) DisallowUnknownFields() {
+	d.codec.strict.Store(true)
+	d.Decoder.DisallowUnknownFields()
+}
+
+func TestClient_Options_SetCodec(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		var req struct{ A string }
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+		assert.Equal(t, "a", req.A)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, _ = fmt.Fprint(w, `{"A":"b"}`)
+	}
+
+	client := setup(t, "/", hf)
+
+	codec := new(testCodec)
+	err := client.Options(SetCodec(codec))
+	require.NoError(t, err)
+
+	var res struct{ A string }
+	err = client.Call(context.Background(), http.MethodPost, "/", struct{ A string }{"a"}, &res)
+	require.NoError(t, err)
+
+	assert.Equal(t, "b", res.A)
+	assert.EqualValues(t, 1, codec.encoders.Load())
+	assert.EqualValues(t, 1, codec.decoders.Load())
+	assert.True(t, codec.strict.Load())
+
+	err = client.Options(SetCodec(nil))
+	assert.EqualError(t, err, "codec must not be nil")
+}
@@ -0,0 +1,22 @@
+package axiom
+
+import "context"
+
+type orgIDContextKey struct{}
+
+// ContextWithOrgID returns a copy of the given context that carries an
+// organization ID. Requests created with that context are sent on behalf of
+// the given organization instead of the one the [Client] is configured with.
+// This allows a single [Client] using a personal token to talk to multiple
+// organizations. The organization ID is validated when the request is created
+// and an [ErrInvalidOrganizationID] is returned if it is malformed.
+func ContextWithOrgID(ctx context.Context, orgID string) context.Context {
+	return context.WithValue(ctx, orgIDContextKey{}, orgID)
+}
+
+// orgIDFromContext returns the organization ID carried by the given context,
+// if any.
+func orgIDFromContext(ctx context.Context) (string, bool) {
+	orgID, ok := ctx.Value(orgIDContextKey{}).(string)
+	return orgID, ok && orgID != ""
+}
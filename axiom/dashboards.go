@@ -0,0 +1,414 @@
+package axiom
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+//go:generate go run golang.org/x/tools/cmd/stringer -type=ChangeType -linecomment -output=dashboards_string.go
+
+// dashboardApplyMaxAttempts is the maximum amount of times
+// [DashboardsService.Apply] looks up and writes a dashboard when it conflicts
+// with a concurrent modification.
+const dashboardApplyMaxAttempts = 3
+
+// Dashboard represents a dashboard.
+type Dashboard struct {
+	// ID is the unique ID of the dashboard.
+	ID string `json:"id,omitempty"`
+	// Name of the dashboard.
+	Name string `json:"name"`
+	// Description of the dashboard.
+	Description string `json:"description,omitempty"`
+	// Owner is the ID of the user or team that owns the dashboard.
+	Owner string `json:"owner,omitempty"`
+	// Version of the dashboard. It is incremented by the server on every
+	// update.
+	Version int64 `json:"version,omitempty"`
+	// Charts of the dashboard.
+	Charts []DashboardChart `json:"charts"`
+	// Layout of the charts on the dashboard.
+	Layout []DashboardLayout `json:"layout"`
+}
+
+// DashboardChart is a chart of a [Dashboard].
+type DashboardChart struct {
+	// ID of the chart. It is unique within the dashboard.
+	ID string `json:"id"`
+	// Name of the chart.
+	Name string `json:"name"`
+	// Type of the chart, e.g. "TimeSeries" or "Table".
+	Type string `json:"type"`
+	// Query is the APL query of the chart.
+	Query string `json:"query"`
+}
+
+// DashboardLayout is the position and size of a [DashboardChart] on a
+// [Dashboard], in grid units.
+type DashboardLayout struct {
+	// ChartID is the ID of the chart the layout applies to.
+	ChartID string `json:"i"`
+	// X is the horizontal position of the chart.
+	X int `json:"x"`
+	// Y is the vertical position of the chart.
+	Y int `json:"y"`
+	// W is the width of the chart.
+	W int `json:"w"`
+	// H is the height of the chart.
+	H int `json:"h"`
+}
+
+// ChangeType is the type of a [DashboardChange].
+type ChangeType uint8
+
+// All available [DashboardChange] types.
+const (
+	ChangeAdded    ChangeType = iota // added
+	ChangeRemoved                    // removed
+	ChangeModified                   // modified
+)
+
+// DashboardChange is a difference between two dashboards, as returned by
+// [DashboardsService.Diff].
+type DashboardChange struct {
+	// Type of the change.
+	Type ChangeType
+	// Path identifies the changed element by the JSON names of the fields that
+	// lead to it. Charts and layouts are identified by their chart ID, e.g.
+	// "name", "charts[cpu]", "charts[cpu].query" or "layout[cpu].w".
+	Path string
+	// Old is the value before the change. It is nil for added elements.
+	Old any
+	// New is the value after the change. It is nil for removed elements.
+	New any
+}
+
+// String returns a human readable representation of the change, suitable for
+// reviewing it, e.g. `~ charts[cpu].query: "['a']" -> "['b']"`.
+func (c DashboardChange) String() string {
+	switch c.Type {
+	case ChangeAdded:
+		return "+ " + c.Path
+	case ChangeRemoved:
+		return "- " + c.Path
+	case ChangeModified:
+		return fmt.Sprintf("~ %s: %q -> %q", c.Path, fmt.Sprint(c.Old), fmt.Sprint(c.New))
+	}
+	return c.Type.String() + " " + c.Path
+}
+
+// DashboardsService handles communication with the dashboard related
+// operations of the Axiom API.
+//
+// Axiom API Reference: /v1/dashboards
+type DashboardsService service
+
+// List all available dashboards.
+func (s *DashboardsService) List(ctx context.Context) ([]*Dashboard, error) {
+	ctx, span := s.client.trace(ctx, "Dashboards.List")
+	defer span.End()
+
+	var res []*Dashboard
+	if err := s.client.Call(ctx, http.MethodGet, s.basePath, nil, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return res, nil
+}
+
+// Get a dashboard by id.
+func (s *DashboardsService) Get(ctx context.Context, id string) (*Dashboard, error) {
+	ctx, span := s.client.trace(ctx, "Dashboards.Get", trace.WithAttributes(
+		attribute.String("axiom.dashboard_id", id),
+	))
+	defer span.End()
+
+	path, err := url.JoinPath(s.basePath, id)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	var res Dashboard
+	if err := s.client.Call(ctx, http.MethodGet, path, nil, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return &res, nil
+}
+
+// Create a dashboard with the given properties.
+func (s *DashboardsService) Create(ctx context.Context, req Dashboard) (*Dashboard, error) {
+	ctx, span := s.client.trace(ctx, "Dashboards.Create", trace.WithAttributes(
+		attribute.String("axiom.param.name", req.Name),
+	))
+	defer span.End()
+
+	req.ID = ""
+
+	var res Dashboard
+	if err := s.client.Call(ctx, http.MethodPost, s.basePath, req, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return &res, nil
+}
+
+// Update the dashboard identified by the given id with the given properties.
+func (s *DashboardsService) Update(ctx context.Context, id string, req Dashboard) (*Dashboard, error) {
+	ctx, span := s.client.trace(ctx, "Dashboards.Update", trace.WithAttributes(
+		attribute.String("axiom.dashboard_id", id),
+	))
+	defer span.End()
+
+	path, err := url.JoinPath(s.basePath, id)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	req.ID = ""
+
+	var res Dashboard
+	if err := s.client.Call(ctx, http.MethodPut, path, req, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return &res, nil
+}
+
+// Delete the dashboard identified by the given id.
+func (s *DashboardsService) Delete(ctx context.Context, id string) error {
+	ctx, span := s.client.trace(ctx, "Dashboards.Delete", trace.WithAttributes(
+		attribute.String("axiom.dashboard_id", id),
+	))
+	defer span.End()
+
+	path, err := url.JoinPath(s.basePath, id)
+	if err != nil {
+		return spanError(span, err)
+	}
+
+	if err := s.client.Call(ctx, http.MethodDelete, path, nil, nil); err != nil {
+		return spanError(span, err)
+	}
+
+	return nil
+}
+
+// Export the dashboard identified by the given id as a portable JSON document.
+// The ID, owner and version of the dashboard are stripped as they are specific
+// to the organization the dashboard lives in. Use [DashboardsService.Import]
+// to create the dashboard in another organization.
+func (s *DashboardsService) Export(ctx context.Context, id string) ([]byte, error) {
+	ctx, span := s.client.trace(ctx, "Dashboards.Export", trace.WithAttributes(
+		attribute.String("axiom.dashboard_id", id),
+	))
+	defer span.End()
+
+	dashboard, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	dashboard.ID = ""
+	dashboard.Owner = ""
+	dashboard.Version = 0
+
+	b, err := json.MarshalIndent(dashboard, "", "  ")
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return b, nil
+}
+
+// Import creates the dashboard exported by [DashboardsService.Export]. As
+// datasets might be named differently in the target organization, the
+// optional datasetMap maps the dataset names referenced by the chart queries
+// to the ones to use for the created dashboard. Datasets not present in the
+// map are kept as is. Quoted references, e.g. "['logs']", are renamed wherever
+// they appear in a query. Bare names, e.g. "logs", are only renamed as the
+// source of a query or subquery, as an operand of "union" and as the right side
+// of "join" and "lookup", as elsewhere they can't be told apart from field
+// names.
+func (s *DashboardsService) Import(ctx context.Context, data []byte, datasetMap map[string]string) (*Dashboard, error) {
+	ctx, span := s.client.trace(ctx, "Dashboards.Import")
+	defer span.End()
+
+	var dashboard Dashboard
+	if err := json.Unmarshal(data, &dashboard); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	dashboard.ID = ""
+	dashboard.Owner = ""
+	dashboard.Version = 0
+
+	if len(datasetMap) > 0 {
+		charts := make([]DashboardChart, len(dashboard.Charts))
+		for i, chart := range dashboard.Charts {
+			chart.Query = remapDatasets(chart.Query, datasetMap)
+			charts[i] = chart
+		}
+		dashboard.Charts = charts
+	}
+
+	res, err := s.Create(ctx, dashboard)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return res, nil
+}
+
+// Apply creates the given dashboard, if no dashboard with the same name exists,
+// or updates the existing one otherwise. It returns the resulting dashboard and
+// whether it was created. If the existing dashboard doesn't differ from the
+// given one, as reported by [DashboardsService.Diff], it is returned as is. If
+// multiple dashboards share the name, the first one is updated. A conflict
+// with a concurrent modification, e.g. by another Apply creating the same
+// dashboard, is resolved by retrying the lookup.
+func (s *DashboardsService) Apply(ctx context.Context, dashboard *Dashboard) (*Dashboard, bool, error) {
+	ctx, span := s.client.trace(ctx, "Dashboards.Apply", trace.WithAttributes(
+		attribute.String("axiom.param.name", dashboard.Name),
+	))
+	defer span.End()
+
+	for attempt := 1; ; attempt++ {
+		res, created, err := s.apply(ctx, *dashboard)
+		if errors.Is(err, ErrExists) && attempt < dashboardApplyMaxAttempts {
+			continue
+		} else if err != nil {
+			return nil, false, spanError(span, err)
+		}
+
+		span.SetAttributes(attribute.Bool("axiom.dashboard.created", created))
+
+		return res, created, nil
+	}
+}
+
+func (s *DashboardsService) apply(ctx context.Context, dashboard Dashboard) (*Dashboard, bool, error) {
+	dashboards, err := s.List(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var existing *Dashboard
+	for _, d := range dashboards {
+		if d.Name == dashboard.Name {
+			existing = d
+			break
+		}
+	}
+
+	if existing == nil {
+		res, err := s.Create(ctx, dashboard)
+		if err != nil {
+			return nil, false, err
+		}
+		return res, true, nil
+	}
+
+	if len(s.Diff(existing, &dashboard)) == 0 {
+		return existing, false, nil
+	}
+
+	// Send the version the update is based on, so the server rejects it if
+	// the dashboard was modified in the meantime.
+	if dashboard.Owner == "" {
+		dashboard.Owner = existing.Owner
+	}
+	dashboard.Version = existing.Version
+
+	res, err := s.Update(ctx, existing.ID, dashboard)
+	if err != nil {
+		return nil, false, err
+	}
+	return res, false, nil
+}
+
+// Diff returns the changes that turn dashboard a into dashboard b. Charts and
+// layouts are matched by their chart ID and compared field by field. The ID,
+// owner and version are not compared as they are specific to the organization
+// the dashboard lives in, so an exported dashboard can be compared to the one
+// it is about to replace. A nil dashboard is treated as an empty one. The diff
+// is computed on the client and doesn't call the API.
+func (s *DashboardsService) Diff(a, b *Dashboard) []DashboardChange {
+	if a == nil {
+		a = &Dashboard{}
+	}
+	if b == nil {
+		b = &Dashboard{}
+	}
+
+	var changes []DashboardChange
+	modified := func(path string, from, to any) {
+		if from != to {
+			changes = append(changes, DashboardChange{Type: ChangeModified, Path: path, Old: from, New: to})
+		}
+	}
+
+	modified("name", a.Name, b.Name)
+	modified("description", a.Description, b.Description)
+
+	newCharts := make(map[string]DashboardChart, len(b.Charts))
+	for _, chart := range b.Charts {
+		newCharts[chart.ID] = chart
+	}
+	oldCharts := make(map[string]bool, len(a.Charts))
+	for _, old := range a.Charts {
+		oldCharts[old.ID] = true
+		path := "charts[" + old.ID + "]"
+		if upd, ok := newCharts[old.ID]; !ok {
+			changes = append(changes, DashboardChange{Type: ChangeRemoved, Path: path, Old: old})
+		} else {
+			modified(path+".name", old.Name, upd.Name)
+			modified(path+".type", old.Type, upd.Type)
+			modified(path+".query", old.Query, upd.Query)
+		}
+	}
+	for _, upd := range b.Charts {
+		if !oldCharts[upd.ID] {
+			changes = append(changes, DashboardChange{Type: ChangeAdded, Path: "charts[" + upd.ID + "]", New: upd})
+		}
+	}
+
+	newLayouts := make(map[string]DashboardLayout, len(b.Layout))
+	for _, layout := range b.Layout {
+		newLayouts[layout.ChartID] = layout
+	}
+	oldLayouts := make(map[string]bool, len(a.Layout))
+	for _, old := range a.Layout {
+		oldLayouts[old.ChartID] = true
+		path := "layout[" + old.ChartID + "]"
+		if upd, ok := newLayouts[old.ChartID]; !ok {
+			changes = append(changes, DashboardChange{Type: ChangeRemoved, Path: path, Old: old})
+		} else {
+			modified(path+".x", old.X, upd.X)
+			modified(path+".y", old.Y, upd.Y)
+			modified(path+".w", old.W, upd.W)
+			modified(path+".h", old.H, upd.H)
+		}
+	}
+	for _, upd := range b.Layout {
+		if !oldLayouts[upd.ChartID] {
+			changes = append(changes, DashboardChange{Type: ChangeAdded, Path: "layout[" + upd.ChartID + "]", New: upd})
+		}
+	}
+
+	return changes
+}
+
+// datasetReference returns the APL reference to the dataset with the given
+// name, e.g. "['logs']".
+func datasetReference(name string) string {
+	return "['" + strings.ReplaceAll(name, "'", `\'`) + "']"
+}
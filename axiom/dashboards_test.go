@@ -0,0 +1,380 @@
+package axiom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const actDashboardResp = `{
+	"id": "test",
+	"name": "Service Health",
+	"description": "Health of all services",
+	"owner": "e9cffaad-60e7-4b04-8d27-185e1808c38c",
+	"version": 3,
+	"charts": [
+		{
+			"id": "errors",
+			"name": "Errors",
+			"type": "TimeSeries",
+			"query": "['logs'] | where level == \"error\" | summarize count() by bin_auto(_time)"
+		}
+	],
+	"layout": [
+		{
+			"i": "errors",
+			"x": 0,
+			"y": 0,
+			"w": 6,
+			"h": 4
+		}
+	]
+}`
+
+var expDashboard = &Dashboard{
+	ID:          "test",
+	Name:        "Service Health",
+	Description: "Health of all services",
+	Owner:       "e9cffaad-60e7-4b04-8d27-185e1808c38c",
+	Version:     3,
+	Charts: []DashboardChart{
+		{
+			ID:    "errors",
+			Name:  "Errors",
+			Type:  "TimeSeries",
+			Query: `['logs'] | where level == "error" | summarize count() by bin_auto(_time)`,
+		},
+	},
+	Layout: []DashboardLayout{
+		{ChartID: "errors", X: 0, Y: 0, W: 6, H: 4},
+	},
+}
+
+func TestDashboardsService_List(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, "["+actDashboardResp+"]")
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/dashboards", hf)
+
+	res, err := client.Dashboards.List(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, []*Dashboard{expDashboard}, res)
+}
+
+func TestDashboardsService_Get(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, actDashboardResp)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/dashboards/test", hf)
+
+	res, err := client.Dashboards.Get(context.Background(), "test")
+	require.NoError(t, err)
+
+	assert.Equal(t, expDashboard, res)
+}
+
+func TestDashboardsService_Create(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, mediaTypeJSON, r.Header.Get("Content-Type"))
+
+		var req map[string]any
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+
+		assert.NotContains(t, req, "id")
+		assert.Equal(t, "Service Health", req["name"])
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err = fmt.Fprint(w, actDashboardResp)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/dashboards", hf)
+
+	req := *expDashboard
+	req.ID = "ignored"
+
+	res, err := client.Dashboards.Create(context.Background(), req)
+	require.NoError(t, err)
+
+	assert.Equal(t, expDashboard, res)
+}
+
+func TestDashboardsService_Update(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, mediaTypeJSON, r.Header.Get("Content-Type"))
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, actDashboardResp)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/dashboards/test", hf)
+
+	res, err := client.Dashboards.Update(context.Background(), "test", *expDashboard)
+	require.NoError(t, err)
+
+	assert.Equal(t, expDashboard, res)
+}
+
+func TestDashboardsService_Delete(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	client := setup(t, "/v1/dashboards/test", hf)
+
+	err := client.Dashboards.Delete(context.Background(), "test")
+	require.NoError(t, err)
+}
+
+func TestDashboardsService_Export(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, actDashboardResp)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/dashboards/test", hf)
+
+	b, err := client.Dashboards.Export(context.Background(), "test")
+	require.NoError(t, err)
+
+	var act map[string]any
+	require.NoError(t, json.Unmarshal(b, &act))
+
+	assert.NotContains(t, act, "id")
+	assert.NotContains(t, act, "owner")
+	assert.NotContains(t, act, "version")
+	assert.Equal(t, "Service Health", act["name"])
+	assert.Len(t, act["charts"], 1)
+	assert.Len(t, act["layout"], 1)
+}
+
+func TestDashboardsService_Import(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+
+		var req Dashboard
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+
+		assert.Empty(t, req.ID)
+		assert.Empty(t, req.Owner)
+		assert.Empty(t, req.Version)
+		if assert.Len(t, req.Charts, 3) {
+			// Dataset references are swapped in a single pass.
+			assert.Equal(t, "['prod-logs'] | union ['logs'] | count", req.Charts[0].Query)
+			assert.Equal(t, "['traces'] | count", req.Charts[1].Query)
+			assert.Equal(t, "['prod-logs'] | join kind=inner (['logs']) on id", req.Charts[2].Query)
+		}
+
+		req.ID = "new"
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		err = json.NewEncoder(w).Encode(req)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/dashboards", hf)
+
+	data := []byte(`{
+		"id": "test",
+		"name": "Service Health",
+		"owner": "e9cffaad-60e7-4b04-8d27-185e1808c38c",
+		"version": 3,
+		"charts": [
+			{
+				"id": "a",
+				"name": "A",
+				"type": "Statistic",
+				"query": "['logs'] | union ['prod-logs'] | count"
+			},
+			{
+				"id": "b",
+				"name": "B",
+				"type": "Statistic",
+				"query": "['traces'] | count"
+			},
+			{
+				"id": "c",
+				"name": "C",
+				"type": "Table",
+				"query": "logs | join kind=inner (['prod-logs']) on id"
+			}
+		],
+		"layout": []
+	}`)
+
+	res, err := client.Dashboards.Import(context.Background(), data, map[string]string{
+		"logs":      "prod-logs",
+		"prod-logs": "logs",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "new", res.ID)
+	assert.Equal(t, "Service Health", res.Name)
+}
+
+func TestDashboardsService_Diff(t *testing.T) {
+	client := newClient(t)
+
+	b := *expDashboard
+	b.ID = ""
+	b.Owner = ""
+	b.Version = 0
+	b.Description = "Health of the checkout service"
+	b.Charts = []DashboardChart{
+		{
+			ID:    "errors",
+			Name:  "Errors",
+			Type:  "TimeSeries",
+			Query: `['logs'] | where level == "error" | summarize count() by bin(_time, 1m)`,
+		},
+		{
+			ID:    "latency",
+			Name:  "Latency",
+			Type:  "TimeSeries",
+			Query: `['traces'] | summarize avg(duration) by bin_auto(_time)`,
+		},
+	}
+	b.Layout = []DashboardLayout{
+		{ChartID: "errors", X: 0, Y: 0, W: 12, H: 4},
+		{ChartID: "latency", X: 0, Y: 4, W: 12, H: 4},
+	}
+
+	changes := client.Dashboards.Diff(expDashboard, &b)
+	assert.Equal(t, []DashboardChange{
+		{Type: ChangeModified, Path: "description", Old: "Health of all services", New: "Health of the checkout service"},
+		{Type: ChangeModified, Path: "charts[errors].query", Old: expDashboard.Charts[0].Query, New: b.Charts[0].Query},
+		{Type: ChangeAdded, Path: "charts[latency]", New: b.Charts[1]},
+		{Type: ChangeModified, Path: "layout[errors].w", Old: 6, New: 12},
+		{Type: ChangeAdded, Path: "layout[latency]", New: b.Layout[1]},
+	}, changes)
+
+	assert.Equal(t, `~ layout[errors].w: "6" -> "12"`, changes[3].String())
+	assert.Equal(t, "+ charts[latency]", changes[2].String())
+
+	// Reversing the diff removes what was added.
+	changes = client.Dashboards.Diff(&b, expDashboard)
+	if assert.Len(t, changes, 5) {
+		assert.Equal(t, DashboardChange{Type: ChangeRemoved, Path: "charts[latency]", Old: b.Charts[1]}, changes[2])
+		assert.Equal(t, "- charts[latency]", changes[2].String())
+	}
+
+	assert.Empty(t, client.Dashboards.Diff(expDashboard, expDashboard))
+	assert.Len(t, client.Dashboards.Diff(nil, expDashboard), 4)
+}
+
+func TestDashboardsService_Apply(t *testing.T) {
+	var (
+		stored  []*Dashboard
+		creates int
+		updates int
+	)
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", mediaTypeJSON)
+
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/v1/dashboards":
+			err := json.NewEncoder(w).Encode(stored)
+			assert.NoError(t, err)
+		case r.Method == http.MethodPost && r.URL.Path == "/v1/dashboards":
+			creates++
+
+			var req Dashboard
+			err := json.NewDecoder(r.Body).Decode(&req)
+			require.NoError(t, err)
+
+			// Simulate a concurrent Apply that created the dashboard first.
+			if creates == 1 {
+				req.ID, req.Version = "test", 1
+				stored = append(stored, &req)
+				w.WriteHeader(http.StatusConflict)
+				_, _ = fmt.Fprint(w, `{"message":"dashboard exists"}`)
+				return
+			}
+
+			err = json.NewEncoder(w).Encode(req)
+			assert.NoError(t, err)
+		case r.Method == http.MethodPut && r.URL.Path == "/v1/dashboards/test":
+			updates++
+
+			var req Dashboard
+			err := json.NewDecoder(r.Body).Decode(&req)
+			require.NoError(t, err)
+
+			assert.Equal(t, stored[0].Version, req.Version)
+			req.ID = "test"
+			req.Version++
+			stored[0] = &req
+
+			err = json.NewEncoder(w).Encode(req)
+			assert.NoError(t, err)
+		default:
+			t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		}
+	}
+
+	client := setup(t, "/", hf)
+
+	dashboard := Dashboard{
+		Name:   "Service Health",
+		Charts: expDashboard.Charts,
+		Layout: expDashboard.Layout,
+	}
+
+	// The dashboard is created concurrently, so it is updated instead.
+	res, created, err := client.Dashboards.Apply(context.Background(), &dashboard)
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, "test", res.ID)
+	assert.Equal(t, 1, creates)
+	assert.Equal(t, 0, updates)
+
+	// Applying a changed dashboard updates it.
+	dashboard.Description = "Health of all services"
+	res, created, err = client.Dashboards.Apply(context.Background(), &dashboard)
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.EqualValues(t, 2, res.Version)
+	assert.Equal(t, "Health of all services", res.Description)
+	assert.Equal(t, 1, updates)
+
+	// Applying the same dashboard again is a no-op.
+	_, created, err = client.Dashboards.Apply(context.Background(), &dashboard)
+	require.NoError(t, err)
+	assert.False(t, created)
+	assert.Equal(t, 1, updates)
+
+	// A dashboard with a new name is created.
+	dashboard.Name = "Other"
+	res, created, err = client.Dashboards.Apply(context.Background(), &dashboard)
+	require.NoError(t, err)
+	assert.True(t, created)
+	assert.Equal(t, "Other", res.Name)
+	assert.Equal(t, 2, creates)
+}
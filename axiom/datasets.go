@@ -4,18 +4,25 @@ import (
 	"bufio"
 	"bytes"
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"hash"
 	"io"
 	"net/http"
 	"net/url"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 	"unicode"
 
-	"github.com/klauspost/compress/zstd"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/sync/errgroup"
 
 	"github.com/axiomhq/axiom-go/axiom/ingest"
 	"github.com/axiomhq/axiom-go/axiom/query"
@@ -27,10 +34,112 @@ import (
 // ErrUnknownContentType is raised when the given [ContentType] is not valid.
 var ErrUnknownContentType = errors.New("unknown content type")
 
+// ErrInvalidRawEvent is raised when an event passed to
+// [DatasetsService.IngestRaw] is not a JSON object.
+var ErrInvalidRawEvent = errors.New("invalid raw event")
+
 // ErrUnknownContentEncoding is raised when the given [ContentEncoding] is not
 // valid.
 var ErrUnknownContentEncoding = errors.New("unknown content encoding")
 
+// ErrUnsupportedIngestOption is raised when an ingest option that is resolved
+// on the client, like [ingest.SetFieldRedactor], is passed to
+// [DatasetsService.Ingest] or [DatasetsService.IngestRaw], which send the
+// events as they are.
+var ErrUnsupportedIngestOption = errors.New("unsupported ingest option")
+
+// queryBatchConcurrency is the maximum number of queries of a batch that are
+// run concurrently by [DatasetsService.QueryBatch].
+const queryBatchConcurrency = 4
+
+// ingestMultiConcurrency is the maximum number of datasets that are ingested
+// into concurrently by [DatasetsService.IngestMulti].
+const ingestMultiConcurrency = 4
+
+// ingestMultiMaxAttempts is the maximum number of times
+// [DatasetsService.IngestMulti] tries to ingest into a dataset that is rate
+// limited.
+const ingestMultiMaxAttempts = 3
+
+// ingestMultiPause is the time [DatasetsService.IngestMulti] pauses all
+// ingests for, after hitting a limit without a known reset time.
+var ingestMultiPause = time.Second
+
+// IngestMultiResult is the outcome of ingesting events into a single dataset
+// using [DatasetsService.IngestMulti].
+type IngestMultiResult struct {
+	// Status of the ingestion, if it succeeded.
+	Status *ingest.Status
+	// Err is the error the ingestion failed with, if any.
+	Err error
+}
+
+// ingestPause pauses all ingests of [DatasetsService.IngestMulti] after one of
+// them hit a limit. It is safe for concurrent use.
+type ingestPause struct {
+	mtx   sync.Mutex
+	until time.Time
+}
+
+// extend the pause until the limit that caused the given error resets.
+func (p *ingestPause) extend(err error) {
+	now := time.Now()
+	until := now.Add(ingestMultiPause)
+	var limitErr LimitError
+	if errors.As(err, &limitErr) && !limitErr.Limit.Reset.IsZero() {
+		until = now.Add(limitErr.Limit.until(now))
+	}
+
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if until.After(p.until) {
+		p.until = until
+	}
+}
+
+// wait blocks until the pause is over or the context is canceled.
+func (p *ingestPause) wait(ctx context.Context) error {
+	p.mtx.Lock()
+	d := time.Until(p.until)
+	p.mtx.Unlock()
+
+	if d <= 0 {
+		return nil
+	}
+
+	t := time.NewTimer(d)
+	defer t.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-t.C:
+		return nil
+	}
+}
+
+// QueryBatchError is returned by [DatasetsService.QueryBatch] for each query of
+// a batch that failed.
+type QueryBatchError struct {
+	// Index of the failed query in the batch.
+	Index int
+	// Err is the error the query failed with.
+	Err error
+}
+
+// Error returns the string representation of the query batch error.
+//
+// It implements error.
+func (e QueryBatchError) Error() string {
+	return fmt.Sprintf("query %d: %s", e.Index, e.Err)
+}
+
+// Unwrap returns the error the query failed with.
+func (e QueryBatchError) Unwrap() error {
+	return e.Err
+}
+
 // ContentType describes the content type of the data to ingest.
 type ContentType uint8
 
@@ -65,6 +174,11 @@ const (
 // field that carries the timestamp by passing [ingest.SetTimestampField] to
 // [DatasetsService.Ingest], [DatasetsService.IngestEvents] or
 // [DatasetsService.IngestChannel] as an [Option].
+//
+// With the default [Codec], events are encoded with their fields, including
+// the ones of nested maps, in sorted key order. The encoded events are
+// therefore deterministic which makes requests reproducible, e.g. for snapshot
+// tests or caching. A custom codec set by [SetCodec] might not sort keys.
 type Event map[string]any
 
 // Dataset represents an Axiom dataset.
@@ -155,23 +269,165 @@ type aplQueryResponse struct {
 		GroupBy []string `json:"groupBy"`
 	} `json:"request"`
 	FieldsMeta any `json:"fieldsMetaMap"`
+
+	// numberMode specifies how numbers in the result rows are decoded.
+	numberMode query.NumberMode
 }
 
 // UnmarshalJSON implements [json.Unmarshaler]. It is in place to unmarshal the
 // groupBy field of the legacy request that is part of the response into the
-// actual [query.Result.GroupBy] field.
+// actual [query.Result.GroupBy] field, to populate the [query.Result.Fields],
+// [query.Result.Cursor] and [query.Result.More] fields and to decode the
+// numbers in the result rows according to the requested [query.NumberMode].
 func (r *aplQueryResponse) UnmarshalJSON(b []byte) error {
 	type localResponse *aplQueryResponse
 
-	if err := json.Unmarshal(b, localResponse(r)); err != nil {
-		return err
+	if r.numberMode == query.NumberModeFloat64 {
+		if err := json.Unmarshal(b, localResponse(r)); err != nil {
+			return err
+		}
+	} else {
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+		if err := dec.Decode(localResponse(r)); err != nil {
+			return err
+		}
 	}
 
 	r.GroupBy = r.LegacyRequest.GroupBy
 
+	// The order of the fields is lost when decoding the matches into maps, so
+	// it is recovered from the raw response.
+	if len(r.Matches) > 0 {
+		var err error
+		if r.Fields, err = matchFields(b); err != nil {
+			return err
+		}
+	}
+
+	// The number of matched rows can't tell if there is more to page through,
+	// as it is not limited by the cursor of the request or the "take"
+	// operator. Instead, there is more if the last match doesn't mark the end
+	// of the rows the server has seen.
+	if n := len(r.Matches); n > 0 {
+		r.Cursor = r.Matches[n-1].RowID
+		r.More = r.Status.MinCursor != "" && r.Status.MaxCursor != "" &&
+			r.Cursor != r.Status.MinCursor && r.Cursor != r.Status.MaxCursor
+	}
+	r.More = r.More || r.Status.IsPartial
+
+	if r.numberMode == query.NumberModeInt64 {
+		convertResultNumbers(&r.Result)
+	}
+
 	return nil
 }
 
+// matchFields returns the names of the fields of the matches in the given query
+// response, in the order they first appear in.
+func matchFields(b []byte) ([]string, error) {
+	var res struct {
+		Matches []struct {
+			Data fieldNames `json:"data"`
+		} `json:"matches"`
+	}
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, err
+	}
+
+	var (
+		fields []string
+		seen   = make(map[string]bool)
+	)
+	for _, match := range res.Matches {
+		for _, name := range match.Data {
+			if !seen[name] {
+				seen[name] = true
+				fields = append(fields, name)
+			}
+		}
+	}
+
+	return fields, nil
+}
+
+// fieldNames are the keys of a JSON object, in the order they appear in.
+type fieldNames []string
+
+// UnmarshalJSON implements [json.Unmarshaler]. It is in place to collect the
+// keys of the object without decoding their values.
+func (f *fieldNames) UnmarshalJSON(b []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	if tok, err := dec.Token(); err != nil || tok == nil {
+		// The object is null.
+		return err
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+
+		var value json.RawMessage
+		if err = dec.Decode(&value); err != nil {
+			return err
+		}
+
+		*f = append(*f, key)
+	}
+
+	return nil
+}
+
+// convertResultNumbers converts the [json.Number] values in the rows of the
+// given result into int64 values, if they are integral, or float64 values.
+func convertResultNumbers(res *query.Result) {
+	convertGroups := func(groups []query.EntryGroup) {
+		for _, g := range groups {
+			convertNumbers(g.Group)
+			for i, agg := range g.Aggregations {
+				g.Aggregations[i].Value = convertNumbers(agg.Value)
+			}
+		}
+	}
+
+	for _, m := range res.Matches {
+		convertNumbers(m.Data)
+	}
+	for _, interval := range res.Buckets.Series {
+		convertGroups(interval.Groups)
+	}
+	convertGroups(res.Buckets.Totals)
+}
+
+// convertNumbers converts the given value, if it is a [json.Number], into an
+// int64 value, if it is integral, or a float64 value. Maps and slices are
+// converted in place. Numbers that can't be represented by either type are
+// kept as they are.
+func convertNumbers(v any) any {
+	switch v := v.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return i
+		} else if strings.ContainsAny(string(v), ".eE") {
+			if f, err := v.Float64(); err == nil {
+				return f
+			}
+		}
+	case map[string]any:
+		for k, e := range v {
+			v[k] = convertNumbers(e)
+		}
+	case []any:
+		for i, e := range v {
+			v[i] = convertNumbers(e)
+		}
+	}
+	return v
+}
+
 // DatasetsService handles communication with the dataset related operations of
 // the Axiom API.
 //
@@ -196,6 +452,27 @@ func (s *DatasetsService) List(ctx context.Context) ([]*Dataset, error) {
 	return datasets, nil
 }
 
+// ListPager returns a [Pager] over all available datasets.
+func (s *DatasetsService) ListPager() *Pager[*Dataset] {
+	return newPager(s.basePath, func(ctx context.Context, path string) ([]*Dataset, string, error) {
+		ctx, span := s.client.trace(ctx, "Datasets.ListPager")
+		defer span.End()
+
+		var res []*wrappedDataset
+		next, err := s.client.getPage(ctx, path, &res)
+		if err != nil {
+			return nil, "", spanError(span, err)
+		}
+
+		datasets := make([]*Dataset, len(res))
+		for i, r := range res {
+			datasets[i] = r.Dataset
+		}
+
+		return datasets, next, nil
+	})
+}
+
 // Get a dataset by id.
 func (s *DatasetsService) Get(ctx context.Context, id string) (*Dataset, error) {
 	ctx, span := s.client.trace(ctx, "Datasets.Get", trace.WithAttributes(
@@ -216,6 +493,24 @@ func (s *DatasetsService) Get(ctx context.Context, id string) (*Dataset, error)
 	return res.Dataset, nil
 }
 
+// Exists returns true if a dataset with the given id exists. It only returns
+// an error if checking for the datasets existence failed for any other reason
+// than the dataset not being found.
+func (s *DatasetsService) Exists(ctx context.Context, id string) (bool, error) {
+	ctx, span := s.client.trace(ctx, "Datasets.Exists", trace.WithAttributes(
+		attribute.String("axiom.dataset_id", id),
+	))
+	defer span.End()
+
+	if _, err := s.Get(ctx, id); errors.Is(err, ErrNotFound) {
+		return false, nil
+	} else if err != nil {
+		return false, spanError(span, err)
+	}
+
+	return true, nil
+}
+
 // Create a dataset with the given properties.
 func (s *DatasetsService) Create(ctx context.Context, req DatasetCreateRequest) (*Dataset, error) {
 	ctx, span := s.client.trace(ctx, "Datasets.Create", trace.WithAttributes(
@@ -232,6 +527,30 @@ func (s *DatasetsService) Create(ctx context.Context, req DatasetCreateRequest)
 	return res.Dataset, nil
 }
 
+// EnsureExists creates a dataset with the given properties, unless a dataset
+// with the same name already exists. In that case, the existing dataset is
+// returned. The returned bool reports whether the dataset was newly created.
+func (s *DatasetsService) EnsureExists(ctx context.Context, req DatasetCreateRequest) (*Dataset, bool, error) {
+	ctx, span := s.client.trace(ctx, "Datasets.EnsureExists", trace.WithAttributes(
+		attribute.String("axiom.param.name", req.Name),
+		attribute.String("axiom.param.description", req.Description),
+	))
+	defer span.End()
+
+	dataset, err := s.Create(ctx, req)
+	if err == nil {
+		return dataset, true, nil
+	} else if !errors.Is(err, ErrExists) {
+		return nil, false, spanError(span, err)
+	}
+
+	if dataset, err = s.Get(ctx, req.Name); err != nil {
+		return nil, false, spanError(span, err)
+	}
+
+	return dataset, false, nil
+}
+
 // Update the dataset identified by the given id with the given properties.
 func (s *DatasetsService) Update(ctx context.Context, id string, req DatasetUpdateRequest) (*Dataset, error) {
 	ctx, span := s.client.trace(ctx, "Datasets.Update", trace.WithAttributes(
@@ -313,6 +632,12 @@ func (s *DatasetsService) Trim(ctx context.Context, id string, maxDuration time.
 // The reader is streamed to the server until EOF is reached on a single
 // connection. Keep that in mind when dealing with slow readers.
 //
+// The options that are resolved on the client, like
+// [ingest.SetTimestampSource], [ingest.SetFlatten], [ingest.SetFieldRedactor]
+// and [ingest.SetReceiptWriter], require decoding the events. They are not
+// supported and an [ErrUnsupportedIngestOption] is returned, so events are
+// never sent unredacted by accident.
+//
 // [our documentation]: https://www.axiom.co/docs/usage/field-restrictions
 func (s *DatasetsService) Ingest(ctx context.Context, id string, r io.Reader, typ ContentType, enc ContentEncoding, options ...ingest.Option) (*ingest.Status, error) {
 	ctx, span := s.client.trace(ctx, "Datasets.Ingest", trace.WithAttributes(
@@ -330,6 +655,10 @@ func (s *DatasetsService) Ingest(ctx context.Context, id string, r io.Reader, ty
 		}
 	}
 
+	if err := checkClientIngestOptions(opts); err != nil {
+		return nil, spanError(span, err)
+	}
+
 	path, err := url.JoinPath(s.basePath, id, "ingest")
 	if err != nil {
 		return nil, spanError(span, err)
@@ -344,6 +673,8 @@ func (s *DatasetsService) Ingest(ctx context.Context, id string, r io.Reader, ty
 
 	if err = setEventLabels(req, opts.EventLabels); err != nil {
 		return nil, spanError(span, err)
+	} else if err = s.setIdempotencyKey(req, opts.IdempotencyKey); err != nil {
+		return nil, spanError(span, err)
 	}
 
 	switch typ {
@@ -388,6 +719,10 @@ func (s *DatasetsService) Ingest(ctx context.Context, id string, r io.Reader, ty
 // Restrictions for field names (JSON object keys) can be reviewed in
 // [our documentation].
 //
+// If a receipt writer is set by [ingest.SetReceiptWriter], a receipt is written
+// after the events have been ingested. Failing to write it results in an error,
+// even though the events have been ingested.
+//
 // For ingesting large amounts of data, consider using the
 // [DatasetsService.Ingest] or [DatasetsService.IngestChannel] method.
 //
@@ -411,6 +746,14 @@ func (s *DatasetsService) IngestEvents(ctx context.Context, id string, events []
 		return &ingest.Status{}, nil
 	}
 
+	// The timestamp source is resolved on the client and the result is always
+	// set as the default timestamp field. The custom timestamp field is then
+	// only used to resolve the timestamp and not passed on to the server.
+	var timestampField string
+	if opts.TimestampSource.IsSet() {
+		timestampField, opts.TimestampField = opts.TimestampField, ""
+	}
+
 	path, err := url.JoinPath(s.basePath, id, "ingest")
 	if err != nil {
 		return nil, spanError(span, err)
@@ -418,23 +761,216 @@ func (s *DatasetsService) IngestEvents(ctx context.Context, id string, events []
 		return nil, spanError(span, err)
 	}
 
+	// Prepare the events for ingestion without modifying the ones passed by
+	// the caller. Events are flattened first, so the timestamp can be resolved
+	// from a flattened field, and redacted last, so the redactor sees the
+	// fields as they are sent. The ingestion time is captured once so retries
+	// send the very same events.
+	now := time.Now()
+	prepareEvent := func(event Event) Event {
+		if opts.FlattenSeparator != "" {
+			event = ingest.Flatten(event, opts.FlattenSeparator, opts.FlattenArrays)
+		}
+
+		if opts.TimestampSource.IsSet() {
+			if ts, ok := opts.TimestampSource.Timestamp(event, timestampField, now); ok {
+				prepared := make(Event, len(event)+1)
+				for k, v := range event {
+					prepared[k] = v
+				}
+				prepared[ingest.TimestampField] = ts
+				event = prepared
+			}
+		}
+
+		if opts.FieldRedactor != nil {
+			redacted := make(Event, len(event))
+			for k, v := range event {
+				if v, ok := opts.FieldRedactor(k, v); ok {
+					redacted[k] = v
+				}
+			}
+			event = redacted
+		}
+
+		return event
+	}
+
+	// If a receipt is requested, the body is hashed while it is written, so
+	// the receipt covers the exact bytes sent with the last attempt.
+	var sentHash *bodyHash
 	getBody := func() (io.ReadCloser, error) {
 		pr, pw := io.Pipe()
 
-		zsw, wErr := zstd.NewWriter(pw)
+		zsw, wErr := getZstdEncoder(pw)
 		if wErr != nil {
 			_ = pr.Close()
 			_ = pw.Close()
 			return nil, wErr
 		}
 
+		var w io.Writer = zsw
+		if opts.ReceiptWriter != nil {
+			sentHash = newBodyHash()
+			w = io.MultiWriter(zsw, sentHash.h)
+		}
+		attemptHash := sentHash
+
 		go func() {
+			defer putZstdEncoder(zsw)
+
+			// Events are collected in chunks before they are compressed, to
+			// reduce the amount of writes.
+			buf := getBuffer()
+			defer putBuffer(buf)
+
 			var (
-				enc    = json.NewEncoder(zsw)
+				enc    = s.client.codec.NewEncoder(buf)
 				encErr error
 			)
+			for i, event := range events {
+				if encErr = enc.Encode(prepareEvent(event)); encErr != nil {
+					break
+				}
+				if buf.Len() >= ingestChunkSize || i == len(events)-1 {
+					if _, encErr = w.Write(buf.Bytes()); encErr != nil {
+						break
+					}
+					buf.Reset()
+				}
+			}
+
+			if closeErr := zsw.Close(); encErr == nil {
+				// If we have no error from encoding but from closing, capture
+				// that one.
+				encErr = closeErr
+			}
+			if attemptHash != nil {
+				attemptHash.finish(encErr)
+			}
+			_ = pw.CloseWithError(encErr)
+		}()
+
+		return pr, nil
+	}
+
+	r, err := getBody()
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	req, err := s.client.NewRequest(ctx, http.MethodPost, path, r)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+	req.GetBody = getBody
+
+	if err = setEventLabels(req, opts.EventLabels); err != nil {
+		return nil, spanError(span, err)
+	} else if err = s.setIdempotencyKey(req, opts.IdempotencyKey); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	req.Header.Set("Content-Type", NDJSON.String())
+	req.Header.Set("Content-Encoding", Zstd.String())
+
+	var (
+		res  ingest.Status
+		resp *Response
+	)
+	if resp, err = s.client.Do(req, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+	res.TraceID = resp.TraceID()
+
+	setIngestResultOnSpan(span, res)
+
+	if opts.ReceiptWriter != nil {
+		if err = writeIngestReceipt(ctx, opts.ReceiptWriter, sentHash, len(events), now, res); err != nil {
+			return nil, spanError(span, err)
+		}
+	}
+
+	return &res, nil
+}
+
+// IngestRaw ingests already JSON encoded events into the dataset identified by
+// its id. The events are sent as they are, without decoding and encoding them
+// again, which makes it well suited for proxies that pass events on. Every
+// event must be a JSON object, otherwise an [ErrInvalidRawEvent] is returned
+// and no event is ingested. Events spanning multiple lines are compacted.
+//
+// The options that are resolved on the client, like
+// [ingest.SetTimestampSource], [ingest.SetFlatten], [ingest.SetFieldRedactor]
+// and [ingest.SetReceiptWriter], require decoding the events. They are not
+// supported and an [ErrUnsupportedIngestOption] is returned, so events are
+// never sent unredacted by accident.
+func (s *DatasetsService) IngestRaw(ctx context.Context, id string, events []json.RawMessage, options ...ingest.Option) (*ingest.Status, error) {
+	ctx, span := s.client.trace(ctx, "Datasets.IngestRaw", trace.WithAttributes(
+		attribute.String("axiom.dataset_id", id),
+		attribute.Int("axiom.events_to_ingest", len(events)),
+	))
+	defer span.End()
+
+	// Apply supplied options.
+	var opts ingest.Options
+	for _, option := range options {
+		if option != nil {
+			option(&opts)
+		}
+	}
+
+	if err := checkClientIngestOptions(opts); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	if len(events) == 0 {
+		return &ingest.Status{}, nil
+	}
+
+	for i, event := range events {
+		if err := validateRawEvent(event); err != nil {
+			return nil, spanError(span, fmt.Errorf("%w at index %d: %s", ErrInvalidRawEvent, i, err))
+		}
+	}
+
+	path, err := url.JoinPath(s.basePath, id, "ingest")
+	if err != nil {
+		return nil, spanError(span, err)
+	} else if path, err = AddURLOptions(path, opts); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	getBody := func() (io.ReadCloser, error) {
+		pr, pw := io.Pipe()
+
+		zsw, wErr := getZstdEncoder(pw)
+		if wErr != nil {
+			_ = pr.Close()
+			_ = pw.Close()
+			return nil, wErr
+		}
+
+		go func() {
+			defer putZstdEncoder(zsw)
+
+			buf := getBuffer()
+			defer putBuffer(buf)
+
+			var encErr error
 			for _, event := range events {
-				if encErr = enc.Encode(event); encErr != nil {
+				// Events spanning multiple lines would break the newline
+				// delimited format.
+				if bytes.ContainsAny(event, "\r\n") {
+					buf.Reset()
+					if encErr = json.Compact(buf, event); encErr != nil {
+						break
+					}
+					event = buf.Bytes()
+				}
+				if _, encErr = zsw.Write(event); encErr != nil {
+					break
+				} else if _, encErr = zsw.Write([]byte{'\n'}); encErr != nil {
 					break
 				}
 			}
@@ -463,6 +999,8 @@ func (s *DatasetsService) IngestEvents(ctx context.Context, id string, events []
 
 	if err = setEventLabels(req, opts.EventLabels); err != nil {
 		return nil, spanError(span, err)
+	} else if err = s.setIdempotencyKey(req, opts.IdempotencyKey); err != nil {
+		return nil, spanError(span, err)
 	}
 
 	req.Header.Set("Content-Type", NDJSON.String())
@@ -482,6 +1020,158 @@ func (s *DatasetsService) IngestEvents(ctx context.Context, id string, events []
 	return &res, nil
 }
 
+// checkClientIngestOptions returns an [ErrUnsupportedIngestOption] if any of
+// the given options is resolved on the client and thus can't be honored by
+// ingest methods that send the events as they are.
+func checkClientIngestOptions(opts ingest.Options) error {
+	var option string
+	switch {
+	case opts.TimestampSource.IsSet():
+		option = "timestamp source"
+	case opts.FlattenSeparator != "":
+		option = "flatten"
+	case opts.FieldRedactor != nil:
+		option = "field redactor"
+	case opts.ReceiptWriter != nil:
+		option = "receipt writer"
+	default:
+		return nil
+	}
+	return fmt.Errorf("%w: %s is only supported by IngestEvents and IngestChannel", ErrUnsupportedIngestOption, option)
+}
+
+// validateRawEvent returns an error if the given event is not a JSON object.
+func validateRawEvent(event json.RawMessage) error {
+	if trimmed := bytes.TrimSpace(event); len(trimmed) == 0 || trimmed[0] != '{' {
+		return errors.New("not a JSON object")
+	} else if !json.Valid(trimmed) {
+		return errors.New("malformed JSON")
+	}
+	return nil
+}
+
+// bodyHash is the SHA-256 hash of a request body, computed while the body is
+// written.
+type bodyHash struct {
+	h    hash.Hash
+	done chan struct{}
+	sum  []byte
+	err  error
+}
+
+func newBodyHash() *bodyHash {
+	return &bodyHash{
+		h:    sha256.New(),
+		done: make(chan struct{}),
+	}
+}
+
+// finish marks the body as completely written, or failed with the given error.
+func (bh *bodyHash) finish(err error) {
+	if err == nil {
+		bh.sum = bh.h.Sum(nil)
+	}
+	bh.err = err
+	close(bh.done)
+}
+
+// wait blocks until the body has been completely written and returns its hash.
+func (bh *bodyHash) wait(ctx context.Context) ([]byte, error) {
+	select {
+	case <-bh.done:
+		return bh.sum, bh.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// writeIngestReceipt writes a receipt for a batch of the given amount of events
+// to the writer, using the hash of the body sent to the server.
+func writeIngestReceipt(ctx context.Context, w io.Writer, bh *bodyHash, events int, now time.Time, status ingest.Status) error {
+	sum, err := bh.wait(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to hash events for ingest receipt: %w", err)
+	}
+
+	if err := json.NewEncoder(w).Encode(ingest.Receipt{
+		Hash:     hex.EncodeToString(sum),
+		Events:   events,
+		Ingested: status.Ingested,
+		Failed:   status.Failed,
+		Time:     now,
+		TraceID:  status.TraceID,
+	}); err != nil {
+		return fmt.Errorf("failed to write ingest receipt: %w", err)
+	}
+
+	return nil
+}
+
+// IngestMulti ingests events into multiple datasets, identified by their id.
+// The datasets are ingested into concurrently by a bounded number of workers.
+// If the ingestion into any dataset hits a limit, all ingests are paused until
+// the limit resets and the rate limited ingestion is tried again. The given
+// options apply to all datasets.
+//
+// The outcome is reported for every dataset. If the ingestion into any dataset
+// failed, an error that joins the errors of all failed datasets is returned as
+// well.
+func (s *DatasetsService) IngestMulti(ctx context.Context, events map[string][]Event, options ...ingest.Option) (map[string]IngestMultiResult, error) {
+	ctx, span := s.client.trace(ctx, "Datasets.IngestMulti", trace.WithAttributes(
+		attribute.Int("axiom.datasets", len(events)),
+	))
+	defer span.End()
+
+	var (
+		mtx     sync.Mutex
+		results = make(map[string]IngestMultiResult, len(events))
+		pause   ingestPause
+		g       errgroup.Group
+	)
+	g.SetLimit(ingestMultiConcurrency)
+	for id, datasetEvents := range events {
+		id, datasetEvents := id, datasetEvents
+		g.Go(func() error {
+			var res IngestMultiResult
+			for attempt := 1; ; attempt++ {
+				if res.Err = pause.wait(ctx); res.Err != nil {
+					break
+				}
+				res.Status, res.Err = s.IngestEvents(ctx, id, datasetEvents, options...)
+				if !IsRateLimited(res.Err) || attempt == ingestMultiMaxAttempts {
+					break
+				}
+				pause.extend(res.Err)
+			}
+
+			mtx.Lock()
+			results[id] = res
+			mtx.Unlock()
+
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	ids := make([]string, 0, len(results))
+	for id := range results {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var errs []error
+	for _, id := range ids {
+		if err := results[id].Err; err != nil {
+			errs = append(errs, fmt.Errorf("dataset %q: %w", id, err))
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return results, spanError(span, err)
+	}
+	return results, nil
+}
+
 // IngestChannel ingests events from a channel into the dataset identified by
 // its id.
 //
@@ -497,7 +1187,8 @@ func (s *DatasetsService) IngestEvents(ctx context.Context, id string, events []
 // Events are ingested in batches. A batch is either 1000 events for unbuffered
 // channels or the capacity of the channel for buffered channels. The maximum
 // batch size is 1000. A batch is sent to the server as soon as it is full,
-// after one second or when the channel is closed.
+// after one second (configurable using [ingest.SetFlushInterval]) or when the
+// channel is closed.
 //
 // The method returns with an error when the context is marked as done or an
 // error occurs when sending the events to the server. A partial ingestion is
@@ -507,6 +1198,11 @@ func (s *DatasetsService) IngestEvents(ctx context.Context, id string, events []
 // The method returns without an error if the channel is closed and the buffered
 // events are successfully sent to the server.
 //
+// The method also returns once the shutdown signal specified by the
+// [ingest.SetShutdownSignal] option fires. In that case, all pending events,
+// including the ones buffered in the channel at that time, are sent to the
+// server before returning. Events sent to the channel afterwards are not.
+//
 // The returned ingest status does not contain a trace ID as the underlying
 // implementation possibly sends multiple requests to the server thus generating
 // multiple trace IDs.
@@ -519,6 +1215,20 @@ func (s *DatasetsService) IngestChannel(ctx context.Context, id string, events <
 	))
 	defer span.End()
 
+	// Apply supplied options.
+	var opts ingest.Options
+	for _, option := range options {
+		if option != nil {
+			option(&opts)
+		}
+	}
+
+	// Every batch is a distinct request and must not share an idempotency key,
+	// so a key is generated for each batch instead.
+	if opts.IdempotencyKey != "" {
+		options = append(options[:len(options):len(options)], ingest.SetIdempotencyKey(""))
+	}
+
 	// Batch is either 1000 events for unbuffered channels or the capacity of
 	// the channel for buffered channels. The maximum batch size is 1000.
 	batchSize := 1000
@@ -527,8 +1237,11 @@ func (s *DatasetsService) IngestChannel(ctx context.Context, id string, events <
 	}
 	batch := make([]Event, 0, batchSize)
 
-	// Flush on a per second basis.
-	const flushInterval = time.Second
+	// Flush on a per second basis, unless configured otherwise.
+	flushInterval := time.Second
+	if opts.FlushInterval > 0 {
+		flushInterval = opts.FlushInterval
+	}
 	t := time.NewTicker(flushInterval)
 	defer t.Stop()
 
@@ -537,7 +1250,7 @@ func (s *DatasetsService) IngestChannel(ctx context.Context, id string, events <
 		setIngestResultOnSpan(span, ingestStatus)
 	}()
 
-	flush := func() error {
+	flush := func(ctx context.Context) error {
 		if len(batch) == 0 {
 			return nil
 		}
@@ -553,26 +1266,63 @@ func (s *DatasetsService) IngestChannel(ctx context.Context, id string, events <
 		return nil
 	}
 
+	// shutdown sends all pending events to the server, bounded by the
+	// configured grace period.
+	shutdown := func() error {
+		flushCtx := ctx
+		if opts.ShutdownGracePeriod > 0 {
+			var cancel context.CancelFunc
+			flushCtx, cancel = context.WithTimeout(ctx, opts.ShutdownGracePeriod)
+			defer cancel()
+		}
+
+		// Drain the events that are already buffered in the channel without
+		// blocking on new ones. Events sent after the shutdown signal fired
+		// are left in the channel, so a busy producer can't keep the drain
+		// going forever.
+		for pending := len(events); pending > 0; pending-- {
+			select {
+			case event, ok := <-events:
+				if !ok {
+					return flush(flushCtx)
+				}
+				batch = append(batch, event)
+
+				if len(batch) >= batchSize {
+					if err := flush(flushCtx); err != nil {
+						return err
+					}
+				}
+			default:
+				return flush(flushCtx)
+			}
+		}
+		return flush(flushCtx)
+	}
+
 	for {
 		select {
 		case <-ctx.Done():
 
 			return &ingestStatus, spanError(span, context.Cause(ctx))
+		case <-opts.ShutdownSignal:
+			err := shutdown()
+			return &ingestStatus, spanError(span, err)
 		case event, ok := <-events:
 			if !ok {
 				// Channel is closed.
-				err := flush()
+				err := flush(ctx)
 				return &ingestStatus, spanError(span, err)
 			}
 			batch = append(batch, event)
 
 			if len(batch) >= batchSize {
-				if err := flush(); err != nil {
+				if err := flush(ctx); err != nil {
 					return &ingestStatus, spanError(span, err)
 				}
 			}
 		case <-t.C:
-			if err := flush(); err != nil {
+			if err := flush(ctx); err != nil {
 				return &ingestStatus, spanError(span, err)
 			}
 		}
@@ -602,12 +1352,16 @@ func (s *DatasetsService) Query(ctx context.Context, apl string, options ...quer
 	))
 	defer span.End()
 
-	// The only query parameters supported can be hardcoded as they are not
-	// configurable as of now.
+	if !opts.StartTime.IsZero() && !opts.EndTime.IsZero() && !opts.StartTime.Before(opts.EndTime) {
+		return nil, spanError(span, query.ErrInvalidRange)
+	}
+
 	queryParams := struct {
-		Format string `url:"format"`
+		Format  string `url:"format"`
+		NoCache bool   `url:"nocache,omitempty"`
 	}{
-		Format: "legacy", // Hardcode legacy APL format for now.
+		Format:  "legacy", // Hardcode legacy APL format for now.
+		NoCache: opts.NoCache,
 	}
 
 	path, err := url.JoinPath(s.basePath, "_apl")
@@ -627,19 +1381,58 @@ func (s *DatasetsService) Query(ctx context.Context, apl string, options ...quer
 	}
 
 	var (
-		res  aplQueryResponse
+		res  = aplQueryResponse{numberMode: opts.NumberMode}
 		resp *Response
 	)
 	if resp, err = s.client.Do(req, &res); err != nil {
 		return nil, spanError(span, err)
 	}
 	res.TraceID = resp.TraceID()
+	res.StartTime, res.EndTime = opts.StartTime, opts.EndTime
 
 	setQueryResultOnSpan(span, res.Result)
 
 	return &res.Result, nil
 }
 
+// QueryBatch executes the given queries specified using the Axiom Processing
+// Language (APL) concurrently and returns their results in the order of the
+// requests. A failing query doesn't affect the others: the results of all
+// successful queries are returned together with an error that joins a
+// [QueryBatchError] for each query that failed. The result at the index of a
+// failed query is empty.
+func (s *DatasetsService) QueryBatch(ctx context.Context, requests []query.Request) ([]query.Result, error) {
+	ctx, span := s.client.trace(ctx, "Datasets.QueryBatch", trace.WithAttributes(
+		attribute.Int("axiom.param.queries", len(requests)),
+	))
+	defer span.End()
+
+	var (
+		results = make([]query.Result, len(requests))
+		errs    = make([]error, len(requests))
+		g       errgroup.Group
+	)
+	g.SetLimit(queryBatchConcurrency)
+	for i, r := range requests {
+		i, r := i, r
+		g.Go(func() error {
+			res, err := s.Query(ctx, r.APL, r.Options...)
+			if err != nil {
+				errs[i] = QueryBatchError{Index: i, Err: err}
+				return nil
+			}
+			results[i] = *res
+			return nil
+		})
+	}
+	_ = g.Wait()
+
+	if err := errors.Join(errs...); err != nil {
+		return results, spanError(span, err)
+	}
+	return results, nil
+}
+
 // QueryLegacy executes the given legacy query on the dataset identified by its
 // id.
 //
@@ -810,3 +1603,28 @@ func setEventLabels(req *http.Request, labels map[string]any) error {
 
 	return nil
 }
+
+// setIdempotencyKey sets the "Idempotency-Key" header of the request. If no key
+// is given, a random one is generated if the request can be retried by the
+// client. The header is kept across retries of the request.
+func (s *DatasetsService) setIdempotencyKey(req *http.Request, key string) error {
+	if key == "" {
+		if req.GetBody == nil || s.client.noRetry {
+			return nil
+		}
+
+		var b [16]byte
+		if _, err := rand.Read(b[:]); err != nil {
+			return fmt.Errorf("generate idempotency key: %w", err)
+		}
+
+		// Format as version 4 UUID, see RFC 4122.
+		b[6] = (b[6] & 0x0f) | 0x40
+		b[8] = (b[8] & 0x3f) | 0x80
+		key = fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+	}
+
+	req.Header.Set(headerIdempotencyKey, key)
+
+	return nil
+}
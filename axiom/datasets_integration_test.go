@@ -134,6 +134,16 @@ func (s *DatasetsTestSuite) Test() {
 
 	s.Contains(datasets, s.dataset)
 
+	// Make sure the dataset is reported as existing while a non-existing one
+	// is not.
+	exists, err := s.client.Datasets.Exists(s.ctx, s.dataset.ID)
+	s.Require().NoError(err)
+	s.True(exists)
+
+	exists, err = s.client.Datasets.Exists(s.ctx, s.dataset.ID+"-nope")
+	s.Require().NoError(err)
+	s.False(exists)
+
 	// Let's ingest some data from a reader source...
 	var (
 		ingested bytes.Buffer
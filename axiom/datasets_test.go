@@ -1,12 +1,17 @@
 package axiom
 
 import (
+	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"regexp"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
@@ -181,11 +186,13 @@ var (
 				},
 			},
 		},
+		Fields: []string{"agent", "bytes", "referrer", "remote_ip", "remote_user", "request", "response", "time"},
 		Buckets: query.Timeseries{
 			Series: []query.Interval{},
 			Totals: []query.EntryGroup{},
 		},
 		TraceID: "abc",
+		Cursor:  "c776x1uafnvq-4918f6cb9000095-1",
 	}
 
 	expLegacyQueryRes = &querylegacy.Result{
@@ -305,6 +312,49 @@ func TestDatasetsService_Get(t *testing.T) {
 	assert.Equal(t, exp, res)
 }
 
+func TestDatasetsService_Exists(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+
+		if r.URL.Path != "/v1/datasets/test" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `{
+			"id": "test",
+			"name": "test",
+			"description": "This is a test description",
+			"who": "f83e245a-afdc-47ad-a765-4addd1994321",
+			"created": "2020-11-17T22:29:00.521238198Z"
+		}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/datasets/", hf)
+
+	exists, err := client.Datasets.Exists(context.Background(), "test")
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = client.Datasets.Exists(context.Background(), "unknown")
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
+
+func TestDatasetsService_Exists_Error(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}
+
+	client := setup(t, "/v1/datasets/test", hf)
+
+	exists, err := client.Datasets.Exists(context.Background(), "test")
+	require.ErrorIs(t, err, ErrUnauthorized)
+	assert.False(t, exists)
+}
+
 func TestDatasetsService_Create(t *testing.T) {
 	exp := &Dataset{
 		ID:          "test",
@@ -340,6 +390,75 @@ func TestDatasetsService_Create(t *testing.T) {
 	assert.Equal(t, exp, res)
 }
 
+func TestDatasetsService_EnsureExists(t *testing.T) {
+	exp := &Dataset{
+		ID:          "test",
+		Name:        "test",
+		Description: "This is a test description",
+		CreatedBy:   "f83e245a-afdc-47ad-a765-4addd1994321",
+		CreatedAt:   testhelper.MustTimeParse(t, time.RFC3339Nano, "2020-11-18T21:30:20.623322799Z"),
+	}
+
+	const body = `{
+		"id": "test",
+		"name": "test",
+		"description": "This is a test description",
+		"who": "f83e245a-afdc-47ad-a765-4addd1994321",
+		"created": "2020-11-18T21:30:20.623322799Z"
+	}`
+
+	tests := []struct {
+		name        string
+		exists      bool
+		wantCreated bool
+	}{
+		{
+			name:        "created",
+			exists:      false,
+			wantCreated: true,
+		},
+		{
+			name:        "exists",
+			exists:      true,
+			wantCreated: false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hf := func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodPost && r.URL.Path == "/v1/datasets":
+					if tt.exists {
+						w.WriteHeader(http.StatusConflict)
+						return
+					}
+				case r.Method == http.MethodGet && r.URL.Path == "/v1/datasets/test":
+					assert.True(t, tt.exists, "dataset should not be fetched after creation")
+				default:
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+
+				w.Header().Set("Content-Type", mediaTypeJSON)
+				_, err := fmt.Fprint(w, body)
+				assert.NoError(t, err)
+			}
+
+			client := setup(t, "/", hf)
+
+			res, created, err := client.Datasets.EnsureExists(context.Background(), DatasetCreateRequest{
+				Name:        "test",
+				Description: "This is a test description",
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, exp, res)
+			assert.Equal(t, tt.wantCreated, created)
+		})
+	}
+}
+
 func TestDatasetsService_Update(t *testing.T) {
 	exp := &Dataset{
 		ID:          "test",
@@ -643,6 +762,255 @@ func TestDatasetsService_IngestEvents_Retry(t *testing.T) {
 	assert.True(t, hasErrored)
 }
 
+func TestDatasetsService_IngestEvents_IdempotencyKey(t *testing.T) {
+	uuidRe := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+	tests := []struct {
+		name    string
+		options []ingest.Option
+		check   func(t *testing.T, key string)
+	}{
+		{
+			name: "generated",
+			check: func(t *testing.T, key string) {
+				assert.Regexp(t, uuidRe, key)
+			},
+		},
+		{
+			name:    "provided",
+			options: []ingest.Option{ingest.SetIdempotencyKey("my-key")},
+			check: func(t *testing.T, key string) {
+				assert.Equal(t, "my-key", key)
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var keys []string
+			hf := func(w http.ResponseWriter, r *http.Request) {
+				keys = append(keys, r.Header.Get("Idempotency-Key"))
+
+				// Fail the first request to trigger a retry.
+				if len(keys) == 1 {
+					w.WriteHeader(http.StatusInternalServerError)
+					return
+				}
+
+				w.Header().Set("Content-Type", mediaTypeJSON)
+				_, err := fmt.Fprint(w, `{"ingested": 1}`)
+				assert.NoError(t, err)
+			}
+
+			client := setup(t, "/v1/datasets/test/ingest", hf)
+
+			_, err := client.Datasets.IngestEvents(context.Background(), "test", []Event{{"foo": "bar"}}, tt.options...)
+			require.NoError(t, err)
+
+			// Retries must carry the same key.
+			require.Len(t, keys, 2)
+			assert.Equal(t, keys[0], keys[1])
+			tt.check(t, keys[0])
+		})
+	}
+}
+
+func TestDatasetsService_IngestEvents_IdempotencyKey_NoRetry(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		// Without retries, there are no duplicates to discard.
+		assert.Empty(t, r.Header.Get("Idempotency-Key"))
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `{"ingested": 1}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/datasets/test/ingest", hf)
+	require.NoError(t, client.Options(SetNoRetry()))
+
+	_, err := client.Datasets.IngestEvents(context.Background(), "test", []Event{{"foo": "bar"}})
+	require.NoError(t, err)
+}
+
+func TestDatasetsService_IngestChannel_IdempotencyKey(t *testing.T) {
+	var keys []string
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `{"ingested": 1}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/datasets/test/ingest", hf)
+
+	// A channel with a capacity of one results in a batch per event.
+	eventCh := make(chan Event, 1)
+	go func() {
+		eventCh <- Event{"foo": "bar"}
+		eventCh <- Event{"foo": "baz"}
+		close(eventCh)
+	}()
+
+	_, err := client.Datasets.IngestChannel(context.Background(), "test", eventCh, ingest.SetIdempotencyKey("my-key"))
+	require.NoError(t, err)
+
+	// Every batch must carry its own key.
+	require.Len(t, keys, 2)
+	assert.NotEqual(t, "my-key", keys[0])
+	assert.NotEqual(t, keys[0], keys[1])
+}
+
+func TestDatasetsService_IngestEvents_TimestampSource(t *testing.T) {
+	const (
+		timeValue  = "2023-01-01T00:00:00Z"
+		fieldValue = "2023-02-02T00:00:00Z"
+	)
+
+	tests := []struct {
+		name   string
+		source ingest.TimestampSource
+		// exp are the expected values of the "_time" field of the two events
+		// ingested. An empty string expects the time of ingestion.
+		exp []string
+	}{
+		{
+			name:   "prefer time",
+			source: ingest.PreferTime,
+			exp:    []string{timeValue, fieldValue},
+		},
+		{
+			name:   "prefer field",
+			source: ingest.PreferField("ts"),
+			exp:    []string{fieldValue, fieldValue},
+		},
+		{
+			name:   "now",
+			source: ingest.Now,
+			exp:    []string{"", ""},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+
+			hf := func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Empty(t, r.URL.Query().Get("timestamp-field"))
+
+				zsr, err := zstd.NewReader(r.Body)
+				require.NoError(t, err)
+
+				events := assertValidJSON(t, zsr)
+				zsr.Close()
+				require.Len(t, events, len(tt.exp))
+
+				for i, exp := range tt.exp {
+					act := events[i].(map[string]any)[ingest.TimestampField]
+					if exp != "" {
+						assert.Equal(t, exp, act)
+						continue
+					}
+					ts, err := time.Parse(time.RFC3339Nano, act.(string))
+					if assert.NoError(t, err) {
+						assert.False(t, ts.Before(start.Truncate(time.Second)))
+					}
+				}
+
+				w.Header().Set("Content-Type", mediaTypeJSON)
+				_, err = fmt.Fprint(w, `{
+					"ingested": 2,
+					"failed": 0,
+					"failures": [],
+					"processedBytes": 630,
+					"blocksCreated": 0,
+					"walLength": 2
+				}`)
+				assert.NoError(t, err)
+			}
+
+			client := setup(t, "/v1/datasets/test/ingest", hf)
+
+			events := []Event{
+				{
+					ingest.TimestampField: timeValue,
+					"ts":                  fieldValue,
+				},
+				{
+					"ts": fieldValue,
+				},
+			}
+
+			_, err := client.Datasets.IngestEvents(context.Background(), "test", events,
+				ingest.SetTimestampField("ts"),
+				ingest.SetTimestampSource(tt.source),
+			)
+			require.NoError(t, err)
+
+			// The events passed in must not be modified.
+			assert.Equal(t, timeValue, events[0][ingest.TimestampField])
+			assert.NotContains(t, events[1], ingest.TimestampField)
+		})
+	}
+}
+
+func TestDatasetsService_IngestEvents_ReceiptWriter(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+		defer zsr.Close()
+
+		// The hash must match the body received by the server.
+		b, err := io.ReadAll(zsr)
+		require.NoError(t, err)
+		assert.Equal(t, "{\"a\":1,\"b\":2}\n{\"c\":\"3\"}\n", string(b))
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		w.Header().Set("X-Axiom-Trace-Id", "abc")
+		_, err = fmt.Fprint(w, `{
+			"ingested": 2,
+			"failed": 0,
+			"failures": [],
+			"processedBytes": 630,
+			"blocksCreated": 0,
+			"walLength": 2
+		}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/datasets/test/ingest", hf)
+
+	events := []Event{
+		{"b": 2, "a": 1},
+		{"c": "3"},
+	}
+
+	var buf bytes.Buffer
+	for i := 0; i < 2; i++ {
+		_, err := client.Datasets.IngestEvents(context.Background(), "test", events,
+			ingest.SetReceiptWriter(&buf),
+		)
+		require.NoError(t, err)
+	}
+
+	// The hash is computed over the NDJSON sent to the server.
+	sum := sha256.Sum256([]byte("{\"a\":1,\"b\":2}\n{\"c\":\"3\"}\n"))
+	expHash := hex.EncodeToString(sum[:])
+
+	dec := json.NewDecoder(&buf)
+	for i := 0; i < 2; i++ {
+		var receipt ingest.Receipt
+		require.NoError(t, dec.Decode(&receipt))
+
+		assert.Equal(t, expHash, receipt.Hash)
+		assert.Equal(t, 2, receipt.Events)
+		assert.EqualValues(t, 2, receipt.Ingested)
+		assert.Zero(t, receipt.Failed)
+		assert.Equal(t, "abc", receipt.TraceID)
+		assert.False(t, receipt.Time.IsZero())
+	}
+	assert.False(t, dec.More())
+}
+
 func TestDatasetsService_IngestChannel_Unbuffered(t *testing.T) {
 	exp := &ingest.Status{
 		Ingested:       2,
@@ -955,6 +1323,125 @@ func TestDatasetsService_IngestChannel_BufferedSlow(t *testing.T) {
 	assert.Equal(t, 2, handlerInvokedCount)
 }
 
+// TestDatasetsService_IngestChannel_ShutdownSignal makes sure all pending
+// events, including the ones still buffered in the channel, are flushed when the
+// shutdown signal fires even though the channel is never closed.
+func TestDatasetsService_IngestChannel_ShutdownSignal(t *testing.T) {
+	exp := &ingest.Status{
+		Ingested:       3,
+		Failed:         0,
+		ProcessedBytes: 1890,
+		BlocksCreated:  0,
+		WALLength:      3,
+	}
+
+	handlerInvokedCount := 0
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		handlerInvokedCount++
+
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, mediaTypeNDJSON, r.Header.Get("Content-Type"))
+		assert.Equal(t, "zstd", r.Header.Get("Content-Encoding"))
+
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		events := assertValidJSON(t, zsr)
+		assert.Len(t, events, 3)
+		zsr.Close()
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		w.Header().Set("X-Axiom-Trace-Id", "abc")
+		_, err = fmt.Fprintf(w, `{
+			"ingested": %d,
+			"failed": 0,
+			"failures": [],
+			"processedBytes": %d,
+			"blocksCreated": 0,
+			"walLength": 3
+		}`, len(events), len(events)*630)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/datasets/test/ingest", hf)
+
+	eventCh := make(chan Event, 10)
+	for i := 0; i < 3; i++ {
+		eventCh <- Event{
+			"time":        "17/May/2015:08:05:32 +0000",
+			"remote_ip":   "93.180.71.3",
+			"remote_user": "-",
+			"request":     "GET /downloads/product_1 HTTP/1.1",
+			"response":    304,
+			"bytes":       0,
+			"referrer":    "-",
+			"agent":       "Debian APT-HTTP/1.3 (0.8.16~exp12ubuntu10.21)",
+		}
+	}
+
+	// Fire the shutdown signal right away. The channel is never closed.
+	shutdownCh := make(chan struct{})
+	close(shutdownCh)
+
+	res, err := client.Datasets.IngestChannel(context.Background(), "test", eventCh,
+		ingest.SetShutdownSignal(shutdownCh, time.Second*5),
+	)
+	require.NoError(t, err)
+
+	assert.Equal(t, exp, res)
+	assert.Equal(t, 1, handlerInvokedCount)
+}
+
+// TestDatasetsService_IngestChannel_ShutdownSignal_BusyProducer makes sure the
+// shutdown only drains the events buffered in the channel when the signal
+// fires, even if a producer keeps sending events and no grace period is set.
+func TestDatasetsService_IngestChannel_ShutdownSignal_BusyProducer(t *testing.T) {
+	eventCh := make(chan Event, 10)
+	refill := func() {
+		for len(eventCh) < cap(eventCh) {
+			eventCh <- Event{"foo": "bar"}
+		}
+	}
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+
+		events := assertValidJSON(t, zsr)
+		zsr.Close()
+
+		// The producer refills the channel while the events are ingested.
+		refill()
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err = fmt.Fprintf(w, `{"ingested": %d}`, len(events))
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/datasets/test/ingest", hf)
+
+	refill()
+
+	// Fire the shutdown signal right away. The channel is never closed.
+	shutdownCh := make(chan struct{})
+	close(shutdownCh)
+
+	doneCh := make(chan error, 1)
+	go func() {
+		_, err := client.Datasets.IngestChannel(context.Background(), "test", eventCh,
+			ingest.SetShutdownSignal(shutdownCh, 0),
+		)
+		doneCh <- err
+	}()
+
+	select {
+	case err := <-doneCh:
+		require.NoError(t, err)
+	case <-time.After(time.Second * 5):
+		t.Fatal("shutdown did not return")
+	}
+}
+
 // TODO(lukasmalkmus): Write an ingest test that contains some failures in the
 // server response.
 
@@ -979,13 +1466,694 @@ func TestDatasetsService_Query(t *testing.T) {
 
 	client := setup(t, "/v1/datasets/_apl", hf)
 
+	start := time.Now().Add(-time.Minute * 5)
 	res, err := client.Datasets.Query(context.Background(),
 		"['test'] | where response == 304",
-		query.SetStartTime(time.Now().Add(-time.Minute*5)),
+		query.SetStartTime(start),
 	)
 	require.NoError(t, err)
 
-	assert.Equal(t, expQueryRes, res)
+	exp := *expQueryRes
+	exp.StartTime = start
+	assert.Equal(t, &exp, res)
+}
+
+func TestDatasetsService_IngestEvents_DeterministicEncoding(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+		defer zsr.Close()
+
+		b, err := io.ReadAll(zsr)
+		require.NoError(t, err)
+
+		assert.Equal(t, `{"a":1,"b":{"x":true,"y":false},"c":"foo"}`+"\n", string(b))
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, _ = fmt.Fprint(w, `{
+			"ingested": 1,
+			"failed": 0,
+			"failures": [],
+			"processedBytes": 100,
+			"blocksCreated": 0,
+			"walLength": 1
+		}`)
+	}
+
+	client := setup(t, "/v1/datasets/test/ingest", hf)
+
+	for i := 0; i < 10; i++ {
+		_, err := client.Datasets.IngestEvents(context.Background(), "test", []Event{
+			{"c": "foo", "b": map[string]any{"y": false, "x": true}, "a": 1},
+		})
+		require.NoError(t, err)
+	}
+}
+
+func TestDatasetsService_IngestEvents_Flatten(t *testing.T) {
+	tests := []struct {
+		name        string
+		indexArrays bool
+		exp         string
+	}{
+		{
+			name: "arrays intact",
+			exp:  `{"_time":"2023-03-21T13:00:00Z","http.request.method":"GET","http.time":"2023-03-21T13:00:00Z","tags":["a",{"b":1}]}`,
+		},
+		{
+			name:        "arrays indexed",
+			indexArrays: true,
+			exp:         `{"_time":"2023-03-21T13:00:00Z","http.request.method":"GET","http.time":"2023-03-21T13:00:00Z","tags.0":"a","tags.1.b":1}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hf := func(w http.ResponseWriter, r *http.Request) {
+				assert.Empty(t, r.URL.Query().Get("timestamp-field"))
+
+				zsr, err := zstd.NewReader(r.Body)
+				require.NoError(t, err)
+				defer zsr.Close()
+
+				b, err := io.ReadAll(zsr)
+				require.NoError(t, err)
+
+				assert.Equal(t, tt.exp+"\n", string(b))
+
+				w.Header().Set("Content-Type", mediaTypeJSON)
+				_, _ = fmt.Fprint(w, `{
+					"ingested": 1,
+					"failed": 0,
+					"failures": [],
+					"processedBytes": 100,
+					"blocksCreated": 0,
+					"walLength": 1
+				}`)
+			}
+
+			client := setup(t, "/v1/datasets/test/ingest", hf)
+
+			event := Event{
+				"http": map[string]any{
+					"request": map[string]any{"method": "GET"},
+					"time":    "2023-03-21T13:00:00Z",
+				},
+				"tags": []any{"a", map[string]any{"b": 1}},
+			}
+
+			_, err := client.Datasets.IngestEvents(context.Background(), "test", []Event{event},
+				ingest.SetFlatten("."),
+				ingest.SetFlattenArrays(tt.indexArrays),
+				ingest.SetTimestampField("http.time"),
+				ingest.SetTimestampSource(ingest.PreferTime),
+			)
+			require.NoError(t, err)
+
+			// The event passed by the caller is not modified.
+			assert.Len(t, event, 2)
+		})
+	}
+}
+
+func TestDatasetsService_IngestEvents_FieldRedactor(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+		defer zsr.Close()
+
+		b, err := io.ReadAll(zsr)
+		require.NoError(t, err)
+
+		assert.Equal(t, `{"user.email":"REDACTED","user.name":"alice"}`+"\n", string(b))
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, _ = fmt.Fprint(w, `{
+			"ingested": 1,
+			"failed": 0,
+			"failures": [],
+			"processedBytes": 100,
+			"blocksCreated": 0,
+			"walLength": 1
+		}`)
+	}
+
+	client := setup(t, "/v1/datasets/test/ingest", hf)
+
+	event := Event{
+		"user": map[string]any{
+			"name":     "alice",
+			"email":    "alice@example.com",
+			"password": "secret",
+		},
+	}
+
+	redactor := func(key string, val any) (any, bool) {
+		switch key {
+		case "user.password":
+			return nil, false
+		case "user.email":
+			return "REDACTED", true
+		}
+		return val, true
+	}
+
+	_, err := client.Datasets.IngestEvents(context.Background(), "test", []Event{event},
+		ingest.SetFlatten("."),
+		ingest.SetFieldRedactor(redactor),
+	)
+	require.NoError(t, err)
+
+	// The event passed by the caller is not modified.
+	assert.Equal(t, "secret", event["user"].(map[string]any)["password"])
+}
+
+func TestFlatten_Unflatten(t *testing.T) {
+	event := map[string]any{
+		"http": map[string]any{
+			"request": map[string]any{"method": "GET"},
+			"status":  200,
+		},
+		"empty": map[string]any{},
+		"tags":  []any{"a"},
+	}
+
+	flat := ingest.Flatten(event, "_", false)
+	assert.Equal(t, map[string]any{
+		"http_request_method": "GET",
+		"http_status":         200,
+		"empty":               map[string]any{},
+		"tags":                []any{"a"},
+	}, flat)
+
+	assert.Equal(t, event, ingest.Unflatten(flat, "_"))
+}
+
+func TestDatasetsService_IngestRaw(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, mediaTypeNDJSON, r.Header.Get("Content-Type"))
+		assert.Equal(t, "zstd", r.Header.Get("Content-Encoding"))
+		assert.Equal(t, "unix", r.URL.Query().Get("timestamp-format"))
+
+		zsr, err := zstd.NewReader(r.Body)
+		require.NoError(t, err)
+		defer zsr.Close()
+
+		b, err := io.ReadAll(zsr)
+		require.NoError(t, err)
+
+		assert.Equal(t, `{"foo":"bar"}`+"\n"+`{"a":1,"b":[1,2]}`+"\n", string(b))
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		w.Header().Set("X-Axiom-Trace-Id", "abc")
+		_, _ = fmt.Fprint(w, `{
+			"ingested": 2,
+			"failed": 0,
+			"failures": [],
+			"processedBytes": 100,
+			"blocksCreated": 0,
+			"walLength": 2
+		}`)
+	}
+
+	client := setup(t, "/v1/datasets/test/ingest", hf)
+
+	res, err := client.Datasets.IngestRaw(context.Background(), "test", []json.RawMessage{
+		json.RawMessage(`{"foo":"bar"}`),
+		json.RawMessage("{\n  \"a\": 1,\n  \"b\": [1, 2]\n}"),
+	}, ingest.SetTimestampFormat("unix"))
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 2, res.Ingested)
+	assert.Equal(t, "abc", res.TraceID)
+}
+
+func TestDatasetsService_IngestRaw_Invalid(t *testing.T) {
+	client := newClient(t)
+
+	tests := []struct {
+		name   string
+		event  string
+		errMsg string
+	}{
+		{
+			name:   "empty",
+			event:  "",
+			errMsg: "invalid raw event at index 1: not a JSON object",
+		},
+		{
+			name:   "array",
+			event:  `[{"foo":"bar"}]`,
+			errMsg: "invalid raw event at index 1: not a JSON object",
+		},
+		{
+			name:   "malformed",
+			event:  `{"foo":}`,
+			errMsg: "invalid raw event at index 1: malformed JSON",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := client.Datasets.IngestRaw(context.Background(), "test", []json.RawMessage{
+				json.RawMessage(`{"foo":"bar"}`),
+				json.RawMessage(tt.event),
+			})
+			assert.ErrorIs(t, err, ErrInvalidRawEvent)
+			assert.EqualError(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestDatasetsService_Ingest_ClientOptions(t *testing.T) {
+	client := newClient(t)
+
+	redactor := func(_ string, v any) (any, bool) { return v, true }
+
+	tests := []struct {
+		name   string
+		option ingest.Option
+		errMsg string
+	}{
+		{
+			name:   "timestamp source",
+			option: ingest.SetTimestampSource(ingest.PreferTime),
+			errMsg: "unsupported ingest option: timestamp source is only supported by IngestEvents and IngestChannel",
+		},
+		{
+			name:   "flatten",
+			option: ingest.SetFlatten("."),
+			errMsg: "unsupported ingest option: flatten is only supported by IngestEvents and IngestChannel",
+		},
+		{
+			name:   "field redactor",
+			option: ingest.SetFieldRedactor(redactor),
+			errMsg: "unsupported ingest option: field redactor is only supported by IngestEvents and IngestChannel",
+		},
+		{
+			name:   "receipt writer",
+			option: ingest.SetReceiptWriter(io.Discard),
+			errMsg: "unsupported ingest option: receipt writer is only supported by IngestEvents and IngestChannel",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := strings.NewReader(`{"foo":"bar"}`)
+			_, err := client.Datasets.Ingest(context.Background(), "test", r, NDJSON, Identity, tt.option)
+			assert.ErrorIs(t, err, ErrUnsupportedIngestOption)
+			assert.EqualError(t, err, tt.errMsg)
+
+			_, err = client.Datasets.IngestRaw(context.Background(), "test", []json.RawMessage{
+				json.RawMessage(`{"foo":"bar"}`),
+			}, tt.option)
+			assert.ErrorIs(t, err, ErrUnsupportedIngestOption)
+			assert.EqualError(t, err, tt.errMsg)
+		})
+	}
+}
+
+func TestDatasetsService_IngestMulti(t *testing.T) {
+	defer func(pause time.Duration) { ingestMultiPause = pause }(ingestMultiPause)
+	ingestMultiPause = time.Millisecond * 10
+
+	var (
+		mtx   sync.Mutex
+		calls = make(map[string]int)
+	)
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/v1/datasets/"), "/ingest")
+
+		mtx.Lock()
+		calls[id]++
+		n := calls[id]
+		mtx.Unlock()
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		switch {
+		case id == "limited" && n == 1:
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = fmt.Fprint(w, `{"message":"rate limit exceeded"}`)
+			return
+		case id == "missing":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = fmt.Fprint(w, `{"message":"dataset not found"}`)
+			return
+		}
+		_, _ = fmt.Fprint(w, `{
+			"ingested": 1,
+			"failed": 0,
+			"failures": [],
+			"processedBytes": 100,
+			"blocksCreated": 0,
+			"walLength": 1
+		}`)
+	}
+
+	client := setup(t, "/v1/datasets/", hf)
+
+	events := []Event{{"foo": "bar"}}
+	res, err := client.Datasets.IngestMulti(context.Background(), map[string][]Event{
+		"test":    events,
+		"limited": events,
+		"missing": events,
+	})
+	require.Error(t, err)
+	assert.True(t, IsNotFound(err))
+	assert.Contains(t, err.Error(), `dataset "missing"`)
+
+	require.Len(t, res, 3)
+	if assert.NoError(t, res["test"].Err) {
+		assert.EqualValues(t, 1, res["test"].Status.Ingested)
+	}
+	if assert.NoError(t, res["limited"].Err) {
+		assert.EqualValues(t, 1, res["limited"].Status.Ingested)
+	}
+	assert.True(t, IsNotFound(res["missing"].Err))
+	assert.Nil(t, res["missing"].Status)
+
+	assert.Equal(t, map[string]int{"test": 1, "limited": 2, "missing": 1}, calls)
+}
+
+func TestDatasetsService_QueryBatch(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		var req aplQueryRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+
+		if req.APL == "['broken']" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		w.Header().Set("X-Axiom-Trace-Id", "abc")
+		_, err = fmt.Fprint(w, actQueryResp)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/datasets/_apl", hf)
+
+	res, err := client.Datasets.QueryBatch(context.Background(), []query.Request{
+		{APL: "['test'] | where response == 304"},
+		{APL: "['broken']"},
+		{APL: "['test']", Options: []query.Option{query.Last(time.Hour)}},
+	})
+	require.Error(t, err)
+	require.Len(t, res, 3)
+
+	var batchErr QueryBatchError
+	if assert.ErrorAs(t, err, &batchErr) {
+		assert.Equal(t, 1, batchErr.Index)
+		var httpErr HTTPError
+		if assert.ErrorAs(t, batchErr.Err, &httpErr) {
+			assert.Equal(t, http.StatusBadRequest, httpErr.Status)
+		}
+	}
+
+	assert.Equal(t, *expQueryRes, res[0])
+	assert.Empty(t, res[1])
+	assert.Equal(t, time.Hour, res[2].EndTime.Sub(res[2].StartTime))
+	res[2].StartTime, res[2].EndTime = time.Time{}, time.Time{}
+	assert.Equal(t, *expQueryRes, res[2])
+}
+
+func TestDatasetsService_Query_SetRange(t *testing.T) {
+	var (
+		start = time.Date(2023, 3, 21, 13, 0, 0, 0, time.UTC)
+		end   = start.Add(time.Hour)
+	)
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		var req aplQueryRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		if assert.NoError(t, err) {
+			assert.Equal(t, start, req.StartTime)
+			assert.Equal(t, end, req.EndTime)
+		}
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err = fmt.Fprint(w, actQueryResp)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/datasets/_apl", hf)
+
+	_, err := client.Datasets.Query(context.Background(), "['test']", query.SetRange(start, end))
+	require.NoError(t, err)
+
+	_, err = client.Datasets.Query(context.Background(), "['test']", query.SetRange(end, start))
+	assert.ErrorIs(t, err, query.ErrInvalidRange)
+
+	_, err = client.Datasets.Query(context.Background(), "['test']", query.SetRange(start, start))
+	assert.ErrorIs(t, err, query.ErrInvalidRange)
+}
+
+func TestDatasetsService_Query_SetNoCache(t *testing.T) {
+	var noCache string
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		noCache = r.URL.Query().Get("nocache")
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, actQueryResp)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/datasets/_apl", hf)
+
+	_, err := client.Datasets.Query(context.Background(), "['test']", query.SetNoCache(true))
+	require.NoError(t, err)
+	assert.Equal(t, "true", noCache)
+
+	_, err = client.Datasets.Query(context.Background(), "['test']")
+	require.NoError(t, err)
+	assert.Empty(t, noCache)
+}
+
+func TestDatasetsService_Query_Cursor(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		var req aplQueryRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+
+		// The server reports all rows of the query as matched on every page
+		// and the "take" operator doesn't limit them, either.
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		switch req.Cursor {
+		case "":
+			_, _ = fmt.Fprint(w, `{
+				"status": { "rowsMatched": 5, "minCursor": "c5", "maxCursor": "c1" },
+				"matches": [
+					{ "_rowId": "c1", "data": {} },
+					{ "_rowId": "c2", "data": {} }
+				]
+			}`)
+		case "c2":
+			assert.False(t, req.IncludeCursor)
+			_, _ = fmt.Fprint(w, `{
+				"status": { "rowsMatched": 5, "minCursor": "c5", "maxCursor": "c3" },
+				"matches": [
+					{ "_rowId": "c3", "data": {} },
+					{ "_rowId": "c4", "data": {} }
+				]
+			}`)
+		case "c4":
+			assert.False(t, req.IncludeCursor)
+			_, _ = fmt.Fprint(w, `{
+				"status": { "rowsMatched": 5, "minCursor": "c5", "maxCursor": "c5" },
+				"matches": [
+					{ "_rowId": "c5", "data": {} }
+				]
+			}`)
+		default:
+			t.Errorf("unexpected cursor %q", req.Cursor)
+		}
+	}
+
+	client := setup(t, "/v1/datasets/_apl", hf)
+
+	res, err := client.Datasets.Query(context.Background(), "['test'] | take 2")
+	require.NoError(t, err)
+	assert.Equal(t, "c2", res.Cursor)
+	assert.True(t, res.More)
+
+	res, err = client.Datasets.Query(context.Background(), "['test'] | take 2",
+		query.SetCursor(res.Cursor, false))
+	require.NoError(t, err)
+	assert.Equal(t, "c4", res.Cursor)
+	assert.True(t, res.More)
+
+	res, err = client.Datasets.Query(context.Background(), "['test'] | take 2",
+		query.SetCursor(res.Cursor, false))
+	require.NoError(t, err)
+	assert.Equal(t, "c5", res.Cursor)
+	assert.False(t, res.More)
+}
+
+func TestDatasetsService_Query_Fields(t *testing.T) {
+	var res aplQueryResponse
+	err := json.Unmarshal([]byte(`{
+		"matches": [
+			{ "_rowId": "c0", "data": { "status": 200, "message": "a", "nested": { "z": 1, "a": 2 } } },
+			{ "_rowId": "c1", "data": { "message": "b", "duration": 1.5, "status": 500 } },
+			{ "_rowId": "c2", "data": null }
+		]
+	}`), &res)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"status", "message", "nested", "duration"}, res.Fields)
+}
+
+func TestDatasetsService_Query_More(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		exp    bool
+	}{
+		{
+			name:   "no cursors",
+			status: `{ "rowsMatched": 10 }`,
+			exp:    false,
+		},
+		{
+			name:   "last match at the end",
+			status: `{ "rowsMatched": 10, "minCursor": "c1", "maxCursor": "c0" }`,
+			exp:    false,
+		},
+		{
+			name:   "last match before the end",
+			status: `{ "rowsMatched": 1, "minCursor": "c2", "maxCursor": "c0" }`,
+			exp:    true,
+		},
+		{
+			name:   "partial",
+			status: `{ "isPartial": true }`,
+			exp:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var res aplQueryResponse
+			err := json.Unmarshal([]byte(`{
+				"status": `+tt.status+`,
+				"matches": [
+					{ "_rowId": "c0", "data": {} },
+					{ "_rowId": "c1", "data": {} }
+				]
+			}`), &res)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.exp, res.More)
+		})
+	}
+}
+
+func TestDatasetsService_Query_Next(t *testing.T) {
+	var reqs []aplQueryRequest
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		var req aplQueryRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+		reqs = append(reqs, req)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, _ = fmt.Fprint(w, `{
+			"status": { "rowsMatched": 3 },
+			"matches": [
+				{ "_rowId": "c1", "data": {} },
+				{ "_rowId": "c2", "data": {} }
+			]
+		}`)
+	}
+
+	client := setup(t, "/v1/datasets/_apl", hf)
+
+	last := query.Last(time.Hour)
+
+	res, err := client.Datasets.Query(context.Background(), "['test']", last)
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, res.EndTime.Sub(res.StartTime))
+
+	// Reusing the option computes a new interval...
+	time.Sleep(time.Millisecond)
+	_, err = client.Datasets.Query(context.Background(), "['test']", last)
+	require.NoError(t, err)
+
+	// ...while continuing the query keeps the interval of the initial query.
+	_, err = client.Datasets.Query(context.Background(), "['test']", last, res.Next(false))
+	require.NoError(t, err)
+
+	require.Len(t, reqs, 3)
+	assert.True(t, reqs[1].EndTime.After(reqs[0].EndTime))
+	assert.True(t, reqs[2].StartTime.Equal(reqs[0].StartTime))
+	assert.True(t, reqs[2].EndTime.Equal(reqs[0].EndTime))
+	assert.Equal(t, "c2", reqs[2].Cursor)
+	assert.Empty(t, reqs[0].Cursor)
+}
+
+func TestDatasetsService_Query_SetNumberMode(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, _ = fmt.Fprint(w, `{
+			"matches": [
+				{
+					"_time": "2020-11-19T11:06:31.569475746Z",
+					"_sysTime": "2020-11-19T11:06:31.581384524Z",
+					"_rowId": "c776x1uafkpu-4918f6cb9000095-0",
+					"data": {
+						"id": 9007199254740993,
+						"ratio": 0.5,
+						"huge": 18446744073709551615,
+						"nested": {
+							"ids": [9007199254740993]
+						}
+					}
+				}
+			],
+			"buckets": {
+				"series": [],
+				"totals": [
+					{
+						"id": 1,
+						"group": {
+							"id": 9007199254740993
+						},
+						"aggregations": [
+							{
+								"op": "count",
+								"value": 9007199254740993
+							}
+						]
+					}
+				]
+			}
+		}`)
+	}
+
+	client := setup(t, "/v1/datasets/_apl", hf)
+
+	res, err := client.Datasets.Query(context.Background(), "['test']")
+	require.NoError(t, err)
+	assert.Equal(t, float64(9007199254740993), res.Matches[0].Data["id"])
+	assert.Equal(t, 0.5, res.Matches[0].Data["ratio"])
+
+	res, err = client.Datasets.Query(context.Background(), "['test']",
+		query.SetNumberMode(query.NumberModeJSON))
+	require.NoError(t, err)
+	assert.Equal(t, json.Number("9007199254740993"), res.Matches[0].Data["id"])
+	assert.Equal(t, json.Number("0.5"), res.Matches[0].Data["ratio"])
+	assert.Equal(t, json.Number("9007199254740993"), res.Buckets.Totals[0].Group["id"])
+	assert.Equal(t, json.Number("9007199254740993"), res.Buckets.Totals[0].Aggregations[0].Value)
+
+	res, err = client.Datasets.Query(context.Background(), "['test']",
+		query.SetNumberMode(query.NumberModeInt64))
+	require.NoError(t, err)
+	assert.Equal(t, int64(9007199254740993), res.Matches[0].Data["id"])
+	assert.Equal(t, 0.5, res.Matches[0].Data["ratio"])
+	assert.Equal(t, json.Number("18446744073709551615"), res.Matches[0].Data["huge"])
+	assert.Equal(t, map[string]any{
+		"ids": []any{int64(9007199254740993)},
+	}, res.Matches[0].Data["nested"])
+	assert.Equal(t, int64(9007199254740993), res.Buckets.Totals[0].Group["id"])
+	assert.Equal(t, int64(9007199254740993), res.Buckets.Totals[0].Aggregations[0].Value)
 }
 
 func TestDatasetsService_Query_WithGroupBy(t *testing.T) {
@@ -0,0 +1,72 @@
+package axiom
+
+import (
+	"net/http"
+	"regexp"
+	"time"
+)
+
+// tokenRe matches Axiom API and personal tokens.
+var tokenRe = regexp.MustCompile(`(xa[ap]t-)[a-zA-Z0-9]{8}-[a-zA-Z0-9]{4}-[a-zA-Z0-9]{4}-[a-zA-Z0-9]{4}-[a-zA-Z0-9]{12}`)
+
+// DebugEntry describes a single attempt of a request made by the [Client]. It
+// is passed to the debug logger configured using [SetDebugLogger].
+type DebugEntry struct {
+	// Method is the HTTP method of the request.
+	Method string
+	// URL is the URL of the request.
+	URL string
+	// Header holds the request headers. Tokens are redacted.
+	Header http.Header
+	// Status is the status code of the response. It is zero, if no response
+	// was received.
+	Status int
+	// Duration is the time the attempt took until the response headers were
+	// received.
+	Duration time.Duration
+	// Attempt is the number of the attempt, starting at 1.
+	Attempt int
+	// RetryReason is the reason the previous attempt failed. It is empty for
+	// the first attempt.
+	RetryReason string
+	// Err is the error returned by the underlying [http.Client], if any.
+	Err error
+	// Deprecation is the warning for a deprecated endpoint, if the server sent
+	// one with the response. See [SetDeprecationHandler].
+	Deprecation string
+}
+
+// logAttempt passes a [DebugEntry] for an attempt of the given request to the
+// configured debug logger, if any.
+func (c *Client) logAttempt(req *http.Request, resp *http.Response, err error, start time.Time, attempt int, retryReason string) {
+	if c.debugLogger == nil {
+		return
+	}
+
+	entry := DebugEntry{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		Header:      redactHeader(req.Header),
+		Duration:    time.Since(start),
+		Attempt:     attempt,
+		RetryReason: retryReason,
+		Err:         err,
+	}
+	if resp != nil {
+		entry.Status = resp.StatusCode
+		entry.Deprecation = deprecationWarning(req, resp.Header)
+	}
+
+	c.debugLogger(req.Context(), entry)
+}
+
+// redactHeader returns a copy of the given header with all tokens in the
+// "Authorization" header redacted.
+func redactHeader(header http.Header) http.Header {
+	header = header.Clone()
+	values := header[http.CanonicalHeaderKey(headerAuthorization)]
+	for i, v := range values {
+		values[i] = tokenRe.ReplaceAllString(v, "${1}REDACTED")
+	}
+	return header
+}
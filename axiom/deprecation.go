@@ -0,0 +1,31 @@
+package axiom
+
+import (
+	"net/http"
+	"strings"
+)
+
+const (
+	headerDeprecation = "Deprecation"
+	headerSunset      = "Sunset"
+)
+
+// deprecationWarning returns a warning for the given request, if the
+// "Deprecation" or "Sunset" header of the given response header indicates that
+// the requested endpoint is deprecated. Otherwise, an empty string is returned.
+func deprecationWarning(req *http.Request, header http.Header) string {
+	deprecation, sunset := header.Get(headerDeprecation), header.Get(headerSunset)
+	if (deprecation == "" || deprecation == "false") && sunset == "" {
+		return ""
+	}
+
+	var sb strings.Builder
+	sb.WriteString(req.Method + " " + req.URL.Path + " is deprecated")
+	if deprecation != "" && deprecation != "true" && deprecation != "false" {
+		sb.WriteString(" since " + deprecation)
+	}
+	if sunset != "" {
+		sb.WriteString(" and will be removed after " + sunset)
+	}
+	return sb.String()
+}
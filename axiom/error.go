@@ -15,6 +15,14 @@ var ErrUnauthorized = newHTTPError(http.StatusForbidden)
 // valid.
 var ErrUnauthenticated = newHTTPError(http.StatusUnauthorized)
 
+// ErrTokenExpired is raised when the authentication token has expired. Errors
+// matching it also match [ErrUnauthenticated].
+var ErrTokenExpired = errors.New("token expired")
+
+// ErrUnreachable is returned by [Client.Ping] when the server can't be
+// reached, e.g. because of a network error. It wraps the underlying error.
+var ErrUnreachable = errors.New("server unreachable")
+
 // ErrNotFound is returned when the requested resource is not found.
 var ErrNotFound = newHTTPError(http.StatusNotFound)
 
@@ -22,16 +30,28 @@ var ErrNotFound = newHTTPError(http.StatusNotFound)
 // exists.
 var ErrExists = newHTTPError(http.StatusConflict)
 
+// ErrPreconditionFailed is returned when a resource was modified concurrently
+// and thus couldn't be updated.
+var ErrPreconditionFailed = newHTTPError(http.StatusPreconditionFailed)
+
 // ErrUnprivilegedToken is raised when a [Client] tries to call an ingest or
 // query endpoint with an API token configured.
 var ErrUnprivilegedToken = errors.New("using API token for non-ingest or non-query operation")
 
+// maxRawErrorBytes is the maximum amount of bytes of a response body captured
+// by [HTTPError.Raw].
+const maxRawErrorBytes = 4 << 10
+
 // HTTPError is the generic error response returned on non 2xx HTTP status
 // codes.
 type HTTPError struct {
 	Status  int    `json:"-"`
 	Message string `json:"message"`
 	TraceID string `json:"-"`
+	// Raw holds the start of the response body, capped at 4KB, if the response
+	// is not JSON formatted. This helps to debug error pages returned by a
+	// proxy in front of Axiom. It is a string, so HTTPError stays comparable.
+	Raw string `json:"-"`
 }
 
 func newHTTPError(code int) HTTPError {
@@ -41,8 +61,12 @@ func newHTTPError(code int) HTTPError {
 	}
 }
 
-// Error implements error.
+// Error implements error. The trace id is included if present, so it can be
+// referenced when reaching out to support.
 func (e HTTPError) Error() string {
+	if e.TraceID != "" {
+		return fmt.Sprintf("API error %d: %s (trace id: %s)", e.Status, e.Message, e.TraceID)
+	}
 	return fmt.Sprintf("API error %d: %s", e.Status, e.Message)
 }
 
@@ -67,8 +91,12 @@ type LimitError struct {
 //
 // It implements error.
 func (e LimitError) Error() string {
-	return fmt.Sprintf("%s limit exceeded: try again in %s",
-		e.Limit.limitType, time.Until(e.Limit.Reset).Truncate(time.Second))
+	msg := fmt.Sprintf("%s limit exceeded: try again in %s",
+		e.Limit.limitType, e.Limit.until(time.Now()).Truncate(time.Second))
+	if e.TraceID != "" {
+		msg += fmt.Sprintf(" (trace id: %s)", e.TraceID)
+	}
+	return msg
 }
 
 // Is returns whether the provided error equals this error.
@@ -79,3 +107,53 @@ func (e LimitError) Is(target error) bool {
 	}
 	return e.Limit == v.Limit && e.HTTPError.Is(v.HTTPError)
 }
+
+// IsNotFound reports whether the error is caused by a 404 (Not Found)
+// response.
+func IsNotFound(err error) bool {
+	status, ok := errorStatus(err)
+	return ok && status == http.StatusNotFound
+}
+
+// IsRateLimited reports whether the error is caused by an exceeded rate,
+// ingest or query limit.
+func IsRateLimited(err error) bool {
+	status, ok := errorStatus(err)
+	return ok && (status == http.StatusTooManyRequests || status == httpStatusLimitExceeded)
+}
+
+// IsUnauthenticated reports whether the error is caused by a 401
+// (Unauthorized) response, which means the authentication is not valid, e.g.
+// because the token is unknown or expired. It matches [ErrUnauthenticated].
+func IsUnauthenticated(err error) bool {
+	status, ok := errorStatus(err)
+	return ok && status == http.StatusUnauthorized
+}
+
+// IsUnauthorized reports whether the error is caused by a 403 (Forbidden)
+// response, which means the authentication is valid but lacks the permissions
+// for the operation. It matches [ErrUnauthorized].
+func IsUnauthorized(err error) bool {
+	status, ok := errorStatus(err)
+	return ok && status == http.StatusForbidden
+}
+
+// IsServerError reports whether the error is caused by a 5xx response.
+func IsServerError(err error) bool {
+	status, ok := errorStatus(err)
+	return ok && status >= 500 && status <= 599
+}
+
+// errorStatus returns the HTTP status code of the [HTTPError] or [LimitError]
+// in the errors chain.
+func errorStatus(err error) (int, bool) {
+	var limitErr LimitError
+	if errors.As(err, &limitErr) {
+		return limitErr.Status, true
+	}
+	var httpErr HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Status, true
+	}
+	return 0, false
+}
@@ -1,6 +1,13 @@
 package axiom_test
 
 import (
+	"errors"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
 	"github.com/axiomhq/axiom-go/axiom"
 )
 
@@ -13,3 +20,99 @@ var (
 	_ is = (*axiom.HTTPError)(nil)
 	_ is = (*axiom.LimitError)(nil)
 )
+
+func TestErrorHelpers(t *testing.T) {
+	limitErr := axiom.LimitError{
+		HTTPError: axiom.HTTPError{Status: http.StatusTooManyRequests},
+	}
+
+	tests := []struct {
+		name            string
+		err             error
+		notFound        bool
+		rateLimited     bool
+		unauthenticated bool
+		unauthorized    bool
+		serverError     bool
+	}{
+		{
+			name: "nil",
+		},
+		{
+			name: "unrelated",
+			err:  errors.New("unrelated"),
+		},
+		{
+			name:     "not found",
+			err:      axiom.ErrNotFound,
+			notFound: true,
+		},
+		{
+			name:     "wrapped not found",
+			err:      fmt.Errorf("get dataset: %w", axiom.ErrNotFound),
+			notFound: true,
+		},
+		{
+			name:        "rate limited",
+			err:         limitErr,
+			rateLimited: true,
+		},
+		{
+			name:        "wrapped rate limited",
+			err:         fmt.Errorf("ingest: %w", limitErr),
+			rateLimited: true,
+		},
+		{
+			name:        "limit exceeded",
+			err:         axiom.HTTPError{Status: 430},
+			rateLimited: true,
+		},
+		{
+			name:            "unauthenticated",
+			err:             axiom.ErrUnauthenticated,
+			unauthenticated: true,
+		},
+		{
+			name:         "unauthorized",
+			err:          axiom.ErrUnauthorized,
+			unauthorized: true,
+		},
+		{
+			name:            "token expired",
+			err:             fmt.Errorf("%w: %w", axiom.ErrTokenExpired, axiom.ErrUnauthenticated),
+			unauthenticated: true,
+		},
+		{
+			name:        "server error",
+			err:         axiom.HTTPError{Status: http.StatusBadGateway},
+			serverError: true,
+		},
+		{
+			name: "conflict",
+			err:  axiom.ErrExists,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.notFound, axiom.IsNotFound(tt.err))
+			assert.Equal(t, tt.rateLimited, axiom.IsRateLimited(tt.err))
+			assert.Equal(t, tt.unauthenticated, axiom.IsUnauthenticated(tt.err))
+			assert.Equal(t, tt.unauthorized, axiom.IsUnauthorized(tt.err))
+			assert.Equal(t, tt.serverError, axiom.IsServerError(tt.err))
+		})
+	}
+}
+
+func TestHTTPError_Comparable(t *testing.T) {
+	var err error = axiom.ErrNotFound
+
+	assert.NotPanics(t, func() {
+		assert.True(t, err == axiom.ErrNotFound)
+		assert.False(t, err == axiom.ErrExists)
+	})
+
+	err = axiom.HTTPError{Status: http.StatusBadGateway, Raw: "<html>"}
+	assert.NotPanics(t, func() {
+		assert.False(t, err == axiom.ErrUnauthorized)
+	})
+}
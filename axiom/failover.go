@@ -0,0 +1,55 @@
+package axiom
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// tokenFailover switches the [Client] from a primary to a secondary token, once
+// the server rejects the primary one. See [SetAccessTokens].
+type tokenFailover struct {
+	primary, secondary string
+
+	switched atomic.Bool
+}
+
+// token returns the token the [Client] authenticates with. It is the secondary
+// token, if the client switched to it and the configured token is still the
+// primary one.
+func (c *Client) token() string {
+	token := c.config.Token()
+	if f := c.failover; f != nil && f.switched.Load() && token == f.primary {
+		return f.secondary
+	}
+	return token
+}
+
+// failoverRequest switches to the secondary token, if the given response
+// rejected the primary token the given request was sent with. It returns a copy
+// of the request that uses the secondary token, if the request can be sent
+// again. Otherwise, nil is returned.
+func (c *Client) failoverRequest(req *http.Request, resp *Response) *http.Request {
+	f := c.failover
+	if f == nil || resp.StatusCode != http.StatusUnauthorized ||
+		c.config.Token() != f.primary || req.Header.Get(headerAuthorization) != "Bearer "+f.primary {
+		return nil
+	}
+
+	if f.switched.CompareAndSwap(false, true) && c.failoverHandler != nil {
+		c.failoverHandler()
+	}
+
+	retryReq := req.Clone(req.Context())
+	if req.GetBody != nil {
+		body, err := req.GetBody()
+		if err != nil {
+			return nil
+		}
+		retryReq.Body = body
+	} else if req.Body != nil && req.Body != http.NoBody {
+		return nil
+	}
+	retryReq.Header.Set(headerAuthorization, "Bearer "+f.secondary)
+
+	return retryReq
+}
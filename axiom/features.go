@@ -0,0 +1,90 @@
+package axiom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+//go:generate go run golang.org/x/tools/cmd/stringer -type=Feature -linecomment -output=features_string.go
+
+// ErrServerVersionUnknown is returned by [Client.Supports] and
+// [Client.RefreshServerInfo] if the version of the server can't be determined.
+var ErrServerVersionUnknown = errors.New("server version unknown")
+
+// ErrMinimumVersionUnknown is returned by [Client.Supports] for features whose
+// minimum server version is not known, yet.
+var ErrMinimumVersionUnknown = errors.New("unknown minimum version")
+
+// Feature is a server feature whose availability depends on the version of the
+// server.
+type Feature uint8
+
+// All available [Feature]s.
+const (
+	FeatureZstdIngest           Feature = iota + 1 // zstd ingest
+	FeatureParameterizedQueries                    // parameterized queries
+	FeatureStreaming                               // streaming
+)
+
+// featureVersions maps features to the minimum server version supporting them.
+// Only versions documented in the release notes of the server belong here. An
+// empty version means the feature is supported by all servers this package
+// works with. Features without an entry have no known minimum version.
+var featureVersions = map[Feature]string{
+	// Ingested events are always zstd compressed by this package.
+	FeatureZstdIngest: "",
+}
+
+// Supports returns true if the server supports the given feature. The version
+// of the server is fetched once and cached for the lifetime of the client. Use
+// [Client.RefreshServerInfo] to update it, e.g. after a server upgrade. If the
+// version of the server can't be determined, an [ErrServerVersionUnknown] is
+// returned. If the minimum server version of the feature is not known, an
+// [ErrMinimumVersionUnknown] is returned.
+func (c *Client) Supports(ctx context.Context, feature Feature) (bool, error) {
+	if feature < FeatureZstdIngest || feature > FeatureStreaming {
+		return false, fmt.Errorf("unknown feature %s", feature)
+	}
+
+	minimum, ok := featureVersions[feature]
+	if !ok {
+		return false, fmt.Errorf("%w: %s", ErrMinimumVersionUnknown, feature)
+	} else if minimum == "" {
+		return true, nil
+	}
+
+	c.serverInfoMtx.Lock()
+	version := c.serverInfo
+	c.serverInfoMtx.Unlock()
+
+	if version == nil {
+		if err := c.RefreshServerInfo(ctx); err != nil {
+			return false, err
+		}
+		c.serverInfoMtx.Lock()
+		version = c.serverInfo
+		c.serverInfoMtx.Unlock()
+	}
+
+	supported, err := version.AtLeast(minimum)
+	if err != nil {
+		return false, fmt.Errorf("%w: %w", ErrServerVersionUnknown, err)
+	}
+	return supported, nil
+}
+
+// RefreshServerInfo fetches the version of the server and caches it for use by
+// [Client.Supports].
+func (c *Client) RefreshServerInfo(ctx context.Context) error {
+	version, err := c.Version.Get(ctx)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrServerVersionUnknown, err)
+	}
+
+	c.serverInfoMtx.Lock()
+	c.serverInfo = version
+	c.serverInfoMtx.Unlock()
+
+	return nil
+}
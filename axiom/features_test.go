@@ -0,0 +1,93 @@
+package axiom
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClient_Supports(t *testing.T) {
+	defer func(versions map[Feature]string) { featureVersions = versions }(featureVersions)
+	featureVersions = map[Feature]string{
+		FeatureParameterizedQueries: "1.5.0",
+		FeatureStreaming:            "1.6.0",
+	}
+
+	var (
+		calls   int
+		version = "1.5.0"
+	)
+	hf := func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprintf(w, `{"version":%q}`, version)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/version", hf)
+
+	ok, err := client.Supports(context.Background(), FeatureParameterizedQueries)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = client.Supports(context.Background(), FeatureStreaming)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	// The version is cached.
+	assert.Equal(t, 1, calls)
+
+	// Until it is refreshed.
+	version = "1.6.0"
+	err = client.RefreshServerInfo(context.Background())
+	require.NoError(t, err)
+
+	ok, err = client.Supports(context.Background(), FeatureStreaming)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, 2, calls)
+
+	_, err = client.Supports(context.Background(), Feature(0))
+	assert.EqualError(t, err, "unknown feature Feature(0)")
+}
+
+func TestClient_Supports_Features(t *testing.T) {
+	hf := func(http.ResponseWriter, *http.Request) {
+		t.Error("server version must not be fetched")
+	}
+
+	client := setup(t, "/v1/version", hf)
+
+	// Ingest is always zstd compressed, so it is always supported.
+	ok, err := client.Supports(context.Background(), FeatureZstdIngest)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	_, err = client.Supports(context.Background(), FeatureParameterizedQueries)
+	assert.ErrorIs(t, err, ErrMinimumVersionUnknown)
+	assert.EqualError(t, err, "unknown minimum version: parameterized queries")
+
+	_, err = client.Supports(context.Background(), FeatureStreaming)
+	assert.ErrorIs(t, err, ErrMinimumVersionUnknown)
+}
+
+func TestClient_Supports_UnknownVersion(t *testing.T) {
+	defer func(versions map[Feature]string) { featureVersions = versions }(featureVersions)
+	featureVersions = map[Feature]string{
+		FeatureStreaming: "1.6.0",
+	}
+
+	hf := func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}
+
+	client := setup(t, "/v1/version", hf)
+
+	_, err := client.Supports(context.Background(), FeatureStreaming)
+	assert.ErrorIs(t, err, ErrServerVersionUnknown)
+	assert.True(t, IsNotFound(err))
+}
@@ -0,0 +1,188 @@
+package axiom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// FieldType represents the type of a dataset field. A field that carries values
+// of different types across events has multiple types set, e.g.
+// FieldTypeInteger|FieldTypeFloat. Types returned by the server that are not
+// known to this package are represented by [FieldTypeUnknown].
+type FieldType uint8
+
+// All available [FieldType]s.
+const (
+	FieldTypeString FieldType = 1 << iota
+	FieldTypeInteger
+	FieldTypeFloat
+	FieldTypeBoolean
+	FieldTypeArray
+	FieldTypeMap
+	FieldTypeDatetime
+	FieldTypeUnknown
+)
+
+var fieldTypeNames = []struct {
+	typ  FieldType
+	name string
+}{
+	{FieldTypeString, "string"},
+	{FieldTypeInteger, "integer"},
+	{FieldTypeFloat, "float"},
+	{FieldTypeBoolean, "boolean"},
+	{FieldTypeArray, "array"},
+	{FieldTypeMap, "map"},
+	{FieldTypeDatetime, "datetime"},
+	{FieldTypeUnknown, "unknown"},
+}
+
+// fieldTypeFromString parses the given string representation of a field type.
+// Types not known to this package are mapped to [FieldTypeUnknown], so new
+// types introduced by the server don't break decoding.
+func fieldTypeFromString(s string) (ft FieldType) {
+	for _, part := range strings.Split(s, "|") {
+		typ := FieldTypeUnknown
+		for _, n := range fieldTypeNames {
+			if part == n.name {
+				typ = n.typ
+				break
+			}
+		}
+		ft |= typ
+	}
+	return ft
+}
+
+// Has reports whether the given type is set on the field type.
+func (ft FieldType) Has(typ FieldType) bool {
+	return typ != 0 && ft&typ == typ
+}
+
+// String returns the string representation of the field type. Multiple types
+// are separated by "|", e.g. "integer|float".
+//
+// It implements [fmt.Stringer].
+func (ft FieldType) String() string {
+	var names []string
+	for _, n := range fieldTypeNames {
+		if ft.Has(n.typ) {
+			names = append(names, n.name)
+		}
+	}
+	if len(names) == 0 {
+		return fmt.Sprintf("FieldType(%d)", ft)
+	}
+	return strings.Join(names, "|")
+}
+
+// MarshalJSON implements [json.Marshaler]. It is in place to marshal the field
+// type to its string representation because that's what the server expects.
+func (ft FieldType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(ft.String())
+}
+
+// UnmarshalJSON implements [json.Unmarshaler]. It is in place to unmarshal the
+// field type from the string representation the server returns.
+func (ft *FieldType) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	*ft = fieldTypeFromString(s)
+
+	return nil
+}
+
+type datasetField struct {
+	Name string    `json:"name"`
+	Type FieldType `json:"type"`
+}
+
+// fieldsKey identifies the field types of a dataset in the cache of the client.
+// Datasets of different organizations can share the same id.
+type fieldsKey struct {
+	orgID     string
+	datasetID string
+}
+
+// MapFields returns the types of the fields of the dataset identified by its
+// id, as known by the server, keyed by field name. The result is cached per
+// organization and dataset, so subsequent calls don't send a request. Use
+// [DatasetsService.InvalidateFields] to drop the cached result, e.g. after new
+// fields have been ingested.
+func (s *DatasetsService) MapFields(ctx context.Context, id string) (map[string]FieldType, error) {
+	ctx, span := s.client.trace(ctx, "Datasets.MapFields", trace.WithAttributes(
+		attribute.String("axiom.dataset_id", id),
+	))
+	defer span.End()
+
+	key := fieldsKey{orgID: s.client.config.OrganizationID(), datasetID: id}
+	if orgID, ok := orgIDFromContext(ctx); ok {
+		key.orgID = orgID
+	}
+
+	s.client.fieldsMtx.Lock()
+	fields, ok := s.client.fields[key]
+	s.client.fieldsMtx.Unlock()
+
+	if !ok {
+		path, err := url.JoinPath(s.basePath, id, "fields")
+		if err != nil {
+			return nil, spanError(span, err)
+		}
+
+		var res []datasetField
+		if err = s.client.Call(ctx, http.MethodGet, path, nil, &res); err != nil {
+			return nil, spanError(span, err)
+		}
+
+		fields = make(map[string]FieldType, len(res))
+		for _, field := range res {
+			fields[field.Name] = field.Type
+		}
+
+		s.client.fieldsMtx.Lock()
+		if s.client.fields == nil {
+			s.client.fields = make(map[fieldsKey]map[string]FieldType)
+		}
+		s.client.fields[key] = fields
+		s.client.fieldsMtx.Unlock()
+	}
+
+	// Return a copy, so the cached result can't be modified by the caller.
+	res := make(map[string]FieldType, len(fields))
+	for k, v := range fields {
+		res[k] = v
+	}
+
+	return res, nil
+}
+
+// InvalidateFields drops the field types cached by [DatasetsService.MapFields]
+// for the datasets identified by the given ids, across all organizations. If no
+// id is given, the field types of all datasets are dropped.
+func (s *DatasetsService) InvalidateFields(ids ...string) {
+	s.client.fieldsMtx.Lock()
+	defer s.client.fieldsMtx.Unlock()
+
+	if len(ids) == 0 {
+		s.client.fields = nil
+		return
+	}
+	for _, id := range ids {
+		for key := range s.client.fields {
+			if key.datasetID == id {
+				delete(s.client.fields, key)
+			}
+		}
+	}
+}
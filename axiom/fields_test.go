@@ -0,0 +1,117 @@
+package axiom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatasetsService_MapFields(t *testing.T) {
+	var calls int
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		calls++
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `[
+			{"name": "_time", "type": "string"},
+			{"name": "bytes", "type": "integer"},
+			{"name": "duration", "type": "integer|float"},
+			{"name": "tags", "type": "array"},
+			{"name": "ts", "type": "datetime"},
+			{"name": "geo", "type": "geopoint|string"}
+		]`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/datasets/test/fields", hf)
+
+	exp := map[string]FieldType{
+		"_time":    FieldTypeString,
+		"bytes":    FieldTypeInteger,
+		"duration": FieldTypeInteger | FieldTypeFloat,
+		"tags":     FieldTypeArray,
+		"ts":       FieldTypeDatetime,
+		"geo":      FieldTypeUnknown | FieldTypeString,
+	}
+
+	res, err := client.Datasets.MapFields(context.Background(), "test")
+	require.NoError(t, err)
+	assert.Equal(t, exp, res)
+
+	// Modifying the result doesn't affect the cache.
+	delete(res, "bytes")
+
+	res, err = client.Datasets.MapFields(context.Background(), "test")
+	require.NoError(t, err)
+	assert.Equal(t, exp, res)
+	assert.Equal(t, 1, calls)
+
+	// The cache is kept per organization.
+	other := map[string]FieldType{"bytes": FieldTypeFloat}
+	client.fields[fieldsKey{orgID: "other", datasetID: "test"}] = other
+
+	res, err = client.Datasets.MapFields(ContextWithOrgID(context.Background(), "other"), "test")
+	require.NoError(t, err)
+	assert.Equal(t, other, res)
+
+	res, err = client.Datasets.MapFields(context.Background(), "test")
+	require.NoError(t, err)
+	assert.Equal(t, exp, res)
+	assert.Equal(t, 1, calls)
+
+	client.Datasets.InvalidateFields("test")
+	assert.Empty(t, client.fields)
+
+	_, err = client.Datasets.MapFields(context.Background(), "test")
+	require.NoError(t, err)
+	assert.Equal(t, 2, calls)
+
+	client.Datasets.InvalidateFields()
+
+	_, err = client.Datasets.MapFields(context.Background(), "test")
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestFieldType_Has(t *testing.T) {
+	ft := FieldTypeInteger | FieldTypeFloat
+
+	assert.True(t, ft.Has(FieldTypeInteger))
+	assert.True(t, ft.Has(FieldTypeFloat))
+	assert.True(t, ft.Has(FieldTypeInteger|FieldTypeFloat))
+	assert.False(t, ft.Has(FieldTypeString))
+	assert.False(t, ft.Has(0))
+}
+
+func TestFieldType_String(t *testing.T) {
+	assert.Equal(t, "string", FieldTypeString.String())
+	assert.Equal(t, "integer|float", (FieldTypeFloat | FieldTypeInteger).String())
+	assert.Equal(t, "FieldType(0)", FieldType(0).String())
+	assert.Equal(t, "datetime|unknown", (FieldTypeDatetime | FieldTypeUnknown).String())
+}
+
+func TestFieldType_Marshal(t *testing.T) {
+	b, err := json.Marshal(FieldTypeInteger | FieldTypeFloat)
+	require.NoError(t, err)
+
+	assert.Equal(t, `"integer|float"`, string(b))
+}
+
+func TestFieldType_Unmarshal(t *testing.T) {
+	var ft FieldType
+	err := json.Unmarshal([]byte(`"boolean|map"`), &ft)
+	require.NoError(t, err)
+
+	assert.Equal(t, FieldTypeBoolean|FieldTypeMap, ft)
+
+	err = json.Unmarshal([]byte(`"integer|decimal"`), &ft)
+	require.NoError(t, err)
+
+	assert.Equal(t, FieldTypeInteger|FieldTypeUnknown, ft)
+}
@@ -0,0 +1,93 @@
+package ingest
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Flatten returns a copy of the given event with all nested maps flattened
+// into the top level. The keys of nested fields are joined by the given
+// separator, e.g. "http.request.method" for a separator of ".". If indexArrays
+// is true, arrays are flattened as well, using the index of each element as
+// key. Otherwise, they are left intact. Empty maps and arrays are kept as they
+// are.
+//
+// Fields are flattened in lexical order of their keys. If flattening produces
+// the same key more than once, the value flattened last is kept.
+func Flatten(event map[string]any, separator string, indexArrays bool) map[string]any {
+	flat := make(map[string]any, len(event))
+	flatten(flat, "", event, separator, indexArrays)
+	return flat
+}
+
+func flatten(dst map[string]any, prefix string, v map[string]any, separator string, indexArrays bool) {
+	keys := make([]string, 0, len(v))
+	for k := range v {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		key := k
+		if prefix != "" {
+			key = prefix + separator + k
+		}
+		flattenValue(dst, key, v[k], separator, indexArrays)
+	}
+}
+
+func flattenValue(dst map[string]any, key string, v any, separator string, indexArrays bool) {
+	switch v := v.(type) {
+	case map[string]any:
+		if len(v) > 0 {
+			flatten(dst, key, v, separator, indexArrays)
+			return
+		}
+	case []any:
+		if indexArrays && len(v) > 0 {
+			for i, elem := range v {
+				flattenValue(dst, key+separator+strconv.Itoa(i), elem, separator, indexArrays)
+			}
+			return
+		}
+	}
+	dst[key] = v
+}
+
+// Unflatten returns a copy of the given event with all keys containing the
+// given separator expanded into nested maps. It is the inverse of [Flatten] for
+// events that don't contain the separator in their original keys. Indexed
+// arrays are not restored and become maps keyed by the element index instead.
+//
+// Fields are expanded in lexical order of their keys. If a key is both a value
+// and the prefix of other keys, e.g. "a" and "a.b", the nested map replaces the
+// value.
+func Unflatten(event map[string]any, separator string) map[string]any {
+	keys := make([]string, 0, len(event))
+	for k := range event {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	nested := make(map[string]any, len(event))
+	for _, k := range keys {
+		var (
+			parts = []string{k}
+			m     = nested
+		)
+		if separator != "" {
+			parts = strings.Split(k, separator)
+		}
+		for _, part := range parts[:len(parts)-1] {
+			child, ok := m[part].(map[string]any)
+			if !ok {
+				child = make(map[string]any)
+				m[part] = child
+			}
+			m = child
+		}
+		m[parts[len(parts)-1]] = event[k]
+	}
+	return nested
+}
@@ -1,10 +1,78 @@
 package ingest
 
+import (
+	"io"
+	"time"
+)
+
 // TimestampField is the default field the server will look for a timestamp to
 // use as the ingestion time. If not present, the server will set the ingestion
 // time to the current server time.
 const TimestampField = "_time"
 
+// TimestampSource selects which timestamp of an event is authoritative when an
+// event carries more than one. Use [PreferTime], [PreferField] or [Now] and
+// pass it to [SetTimestampSource].
+type TimestampSource struct {
+	kind  timestampSourceKind
+	field string
+}
+
+type timestampSourceKind uint8
+
+const (
+	timestampSourceUnset timestampSourceKind = iota
+	timestampSourceTime
+	timestampSourceField
+	timestampSourceNow
+)
+
+var (
+	// PreferTime selects the [TimestampField] of an event. If it is not
+	// present, the custom field set by [SetTimestampField] is used instead.
+	PreferTime = TimestampSource{kind: timestampSourceTime}
+	// Now discards all timestamps present on an event and uses the time of
+	// ingestion instead.
+	Now = TimestampSource{kind: timestampSourceNow}
+)
+
+// PreferField selects the given field of an event. If it is not present, the
+// [TimestampField] is used instead.
+func PreferField(name string) TimestampSource {
+	return TimestampSource{kind: timestampSourceField, field: name}
+}
+
+// IsSet reports whether the timestamp source has been set to any of the
+// available sources.
+func (ts TimestampSource) IsSet() bool {
+	return ts.kind != timestampSourceUnset
+}
+
+// Timestamp returns the authoritative timestamp of the given event. The field
+// is the custom timestamp field considered by [PreferTime] and now is the time
+// used by [Now]. If the event carries none of the selected timestamps, false is
+// returned.
+func (ts TimestampSource) Timestamp(event map[string]any, field string, now time.Time) (any, bool) {
+	switch ts.kind {
+	case timestampSourceTime:
+		if v, ok := event[TimestampField]; ok {
+			return v, true
+		} else if field != "" {
+			v, ok = event[field]
+			return v, ok
+		}
+	case timestampSourceField:
+		if v, ok := event[ts.field]; ok {
+			return v, true
+		}
+		v, ok := event[TimestampField]
+		return v, ok
+	case timestampSourceNow:
+		return now, true
+	}
+	return nil, false
+}
+
 // Options specifies the optional parameters for ingestion.
 type Options struct {
 	// TimestampField defines a custom field to extract the ingestion timestamp
@@ -22,6 +90,44 @@ type Options struct {
 	// event data. This is especially useful when ingesting events from a
 	// third-party source that you do not have control over.
 	EventLabels map[string]any `url:"-"`
+	// TimestampSource selects the authoritative timestamp of an event. It is
+	// resolved on the client and the result is always sent as
+	// [TimestampField]. Only valid for [axiom.DatasetsService.IngestEvents] and
+	// [axiom.DatasetsService.IngestChannel].
+	TimestampSource TimestampSource `url:"-"`
+	// FlattenSeparator is the separator used to join the keys of nested
+	// fields when flattening events. Flattening is disabled if it is empty.
+	// Only valid for [axiom.DatasetsService.IngestEvents] and
+	// [axiom.DatasetsService.IngestChannel].
+	FlattenSeparator string `url:"-"`
+	// FlattenArrays enables flattening arrays, using the index of each element
+	// as key. Only has an effect if [Options.FlattenSeparator] is set.
+	FlattenArrays bool `url:"-"`
+	// FieldRedactor is invoked for every field of an event before it is sent.
+	// It returns the value to send instead or false to drop the field. Only
+	// valid for [axiom.DatasetsService.IngestEvents] and
+	// [axiom.DatasetsService.IngestChannel].
+	FieldRedactor func(key string, val any) (any, bool) `url:"-"`
+	// ReceiptWriter receives a [Receipt] for every batch of events ingested.
+	// Only valid for [axiom.DatasetsService.IngestEvents] and
+	// [axiom.DatasetsService.IngestChannel].
+	ReceiptWriter io.Writer `url:"-"`
+	// FlushInterval is the interval at which
+	// [axiom.DatasetsService.IngestChannel] ingests the events collected so
+	// far, if the batch isn't full before. Defaults to one second.
+	FlushInterval time.Duration `url:"-"`
+	// ShutdownSignal is a channel that, once it receives a value or is closed,
+	// instructs [axiom.DatasetsService.IngestChannel] to flush all pending
+	// events and return. It is ignored by all other ingest methods.
+	ShutdownSignal <-chan struct{} `url:"-"`
+	// ShutdownGracePeriod is the maximum duration the final flush triggered by
+	// the [Options.ShutdownSignal] is allowed to take. A zero value means no
+	// additional limit is imposed.
+	ShutdownGracePeriod time.Duration `url:"-"`
+	// IdempotencyKey is sent as "Idempotency-Key" header, allowing the server
+	// to discard duplicate requests caused by retries. If empty, a random key
+	// is generated for every request that can be retried.
+	IdempotencyKey string `url:"-"`
 }
 
 // An Option applies optional parameters to an ingest operation.
@@ -33,6 +139,53 @@ func SetTimestampField(field string) Option {
 	return func(o *Options) { o.TimestampField = field }
 }
 
+// SetTimestampSource specifies which timestamp of an event is authoritative
+// when it carries both the [TimestampField] and a custom timestamp field. The
+// selected timestamp is resolved on the client and sent as [TimestampField],
+// the custom field set by [SetTimestampField] is then only used as fallback by
+// [PreferTime]. Only valid for [axiom.DatasetsService.IngestEvents] and
+// [axiom.DatasetsService.IngestChannel].
+func SetTimestampSource(source TimestampSource) Option {
+	return func(o *Options) { o.TimestampSource = source }
+}
+
+// SetFlatten enables flattening nested maps of events before they are sent. The
+// keys of nested fields are joined by the given separator, e.g.
+// "http.request.method" for a separator of ".". Arrays are left intact, unless
+// [SetFlattenArrays] is used as well. Flattening happens before the timestamp
+// of an event is resolved, so the flattened key of a nested timestamp field
+// can be passed to [SetTimestampField]. See [Flatten] for details. Only valid
+// for [axiom.DatasetsService.IngestEvents] and
+// [axiom.DatasetsService.IngestChannel].
+func SetFlatten(separator string) Option {
+	return func(o *Options) { o.FlattenSeparator = separator }
+}
+
+// SetFlattenArrays specifies if arrays are flattened as well when flattening
+// events using [SetFlatten]. The index of each element is used as key, e.g.
+// "tags.0". Defaults to false which leaves arrays intact.
+func SetFlattenArrays(indexArrays bool) Option {
+	return func(o *Options) { o.FlattenArrays = indexArrays }
+}
+
+// SetFieldRedactor specifies a function that is invoked for every field of an
+// event before it is sent, which allows for dropping or redacting sensitive
+// fields on the client, so they never leave the process. If it returns false,
+// the field is dropped, otherwise the returned value replaces the original
+// one. The events passed by the caller are not modified.
+//
+// The redactor is invoked for the top-level fields of an event. If events are
+// flattened using [SetFlatten], it is applied after flattening and thus
+// invoked with the flattened keys of nested fields. It is also applied after
+// the timestamp of an event has been resolved by [SetTimestampSource]. Be aware
+// that every event is copied and every field passed to the redactor, which adds
+// overhead to each event ingested. Only valid for
+// [axiom.DatasetsService.IngestEvents] and
+// [axiom.DatasetsService.IngestChannel].
+func SetFieldRedactor(redactor func(key string, val any) (any, bool)) Option {
+	return func(o *Options) { o.FieldRedactor = redactor }
+}
+
 // SetTimestampFormat specifies the format of the timestamp field. The reference
 // time is "Mon Jan 2 15:04:05 -0700 MST 2006", as specified in
 // https://pkg.go.dev/time/?tab=doc#Parse.
@@ -63,3 +216,49 @@ func SetEventLabel(key string, value any) Option {
 func SetEventLabels(labels map[string]any) Option {
 	return func(o *Options) { o.EventLabels = labels }
 }
+
+// SetReceiptWriter specifies a writer that receives a [Receipt] as a line of
+// JSON for every batch of events ingested. This allows for keeping an audit
+// trail of the data sent to Axiom. Only valid for
+// [axiom.DatasetsService.IngestEvents] and [axiom.DatasetsService.IngestChannel]
+// which ingests every batch using the former.
+func SetReceiptWriter(w io.Writer) Option {
+	return func(o *Options) { o.ReceiptWriter = w }
+}
+
+// SetFlushInterval specifies the interval at which
+// [axiom.DatasetsService.IngestChannel] ingests the events collected so far, if
+// the batch isn't full before. Defaults to one second. It is ignored by all
+// other ingest methods.
+func SetFlushInterval(interval time.Duration) Option {
+	return func(o *Options) { o.FlushInterval = interval }
+}
+
+// SetShutdownSignal specifies a channel that, once it receives a value or is
+// closed, makes [axiom.DatasetsService.IngestChannel] flush all pending events
+// (including those buffered in the events channel at that time) and return.
+// The final flush is bounded by the given grace period, if it is greater than
+// zero.
+//
+// To flush on an OS signal, pass the done channel of a context created by
+// [os/signal.NotifyContext]. Make sure not to pass that context to
+// [axiom.DatasetsService.IngestChannel] as well, as the pending events are
+// discarded once that context is marked as done.
+func SetShutdownSignal(signal <-chan struct{}, gracePeriod time.Duration) Option {
+	return func(o *Options) {
+		o.ShutdownSignal = signal
+		o.ShutdownGracePeriod = gracePeriod
+	}
+}
+
+// SetIdempotencyKey specifies the key sent as "Idempotency-Key" header which
+// allows the server to discard duplicate requests, e.g. when a request is
+// retried after a network error. The same key is used for all retries of a
+// request. The key must be unique per request, so it is ignored by
+// [axiom.DatasetsService.IngestChannel] which generates one for every batch.
+//
+// If not set, a random key is generated for every request that is retried by
+// the client. Discarding duplicate requests depends on server support.
+func SetIdempotencyKey(key string) Option {
+	return func(o *Options) { o.IdempotencyKey = key }
+}
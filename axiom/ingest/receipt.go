@@ -0,0 +1,29 @@
+package ingest
+
+import "time"
+
+// Receipt is a record of a batch of events sent to Axiom. It is written to the
+// writer set by [SetReceiptWriter] as a single line of JSON for every batch
+// that was ingested.
+//
+// A receipt is created by the client from the request it sent and the response
+// it received. It is not signed and doesn't carry an ID assigned to the batch
+// by the server, so it documents what was sent rather than proving what the
+// server stored.
+type Receipt struct {
+	// Hash is the hex encoded SHA-256 hash of the newline delimited JSON
+	// representation of the batch, as sent to the server before compression.
+	// It can be used to later verify the contents of the batch.
+	Hash string `json:"hash"`
+	// Events is the amount of events in the batch.
+	Events int `json:"events"`
+	// Ingested is the amount of events the server reported as ingested.
+	Ingested uint64 `json:"ingested"`
+	// Failed is the amount of events the server reported as failed.
+	Failed uint64 `json:"failed"`
+	// Time is the time the batch was ingested at.
+	Time time.Time `json:"time"`
+	// TraceID is the ID of the trace that was generated by the server for the
+	// ingest request of the batch.
+	TraceID string `json:"traceId,omitempty"`
+}
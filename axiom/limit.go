@@ -2,8 +2,11 @@ package axiom
 
 import (
 	"fmt"
+	"math"
 	"net/http"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
@@ -23,9 +26,20 @@ const (
 	headerRateRemaining = "X-RateLimit-Remaining"
 	headerRateReset     = "X-RateLimit-Reset"
 
+	headerDate = "Date"
+
+	// minClockSkew is the minimum difference between the server and the local
+	// clock that is corrected for. Smaller differences are within the
+	// resolution of the "Date" header.
+	minClockSkew = time.Second
+
 	// httpStatusLimitExceeded is a non-standard http status code returned by
 	// Axiom to indicate that the query and/or ingest limit has been reached.
 	httpStatusLimitExceeded = 430
+
+	// httpStatusTextLimitExceeded is the status text of
+	// [httpStatusLimitExceeded], which is unknown to [http.StatusText].
+	httpStatusTextLimitExceeded = "Limit Exceeded"
 )
 
 type limitType uint8
@@ -74,10 +88,21 @@ type Limit struct {
 	Limit uint64
 	// The remaining count towards the maximum limit.
 	Remaining uint64
-	// The time at which the current limit time window will reset.
+	// The time at which the current limit time window will reset, as reported
+	// by the server clock.
 	Reset time.Time
 
 	limitType limitType
+	// clockSkew is the difference between the server and the local clock at
+	// the time the limit was reported.
+	clockSkew time.Duration
+}
+
+// until returns the duration until the limit resets, as seen from the given
+// local time. It corrects for the difference between the server and the local
+// clock.
+func (l Limit) until(now time.Time) time.Duration {
+	return l.Reset.Sub(now.Add(l.clockSkew))
 }
 
 // String returns a string representation of the limit.
@@ -87,6 +112,131 @@ func (l Limit) String() string {
 	return fmt.Sprintf("%d/%d %s limit remaining until %s", l.Remaining, l.Limit, l.limitType, l.Reset)
 }
 
+// limitKey identifies a limit. Limits of different organizations and types are
+// tracked independently, so exhausting one doesn't affect requests subject to
+// another.
+type limitKey struct {
+	orgID     string
+	scope     LimitScope
+	limitType limitType
+}
+
+// limitCache keeps track of the limits reported by the server. It is safe for
+// concurrent use.
+type limitCache struct {
+	mtx    sync.RWMutex
+	last   Limit
+	limits map[limitKey]Limit
+}
+
+// record stores the given limit reported for the given organization, if it
+// carries any information.
+func (lc *limitCache) record(orgID string, limit Limit) {
+	if limit.limitType == 0 {
+		return
+	}
+
+	lc.mtx.Lock()
+	defer lc.mtx.Unlock()
+
+	if lc.limits == nil {
+		lc.limits = make(map[limitKey]Limit)
+	}
+	lc.last = limit
+	lc.limits[limitKey{orgID, limit.Scope, limit.limitType}] = limit
+}
+
+// lastLimit returns the most recently recorded limit.
+func (lc *limitCache) lastLimit() Limit {
+	lc.mtx.RLock()
+	defer lc.mtx.RUnlock()
+
+	return lc.last
+}
+
+// get returns the limit with the lowest remaining count recorded for the given
+// scope.
+func (lc *limitCache) get(scope LimitScope) (Limit, bool) {
+	lc.mtx.RLock()
+	defer lc.mtx.RUnlock()
+
+	var (
+		res   Limit
+		found bool
+	)
+	for key, limit := range lc.limits {
+		if key.scope == scope && (!found || limit.Remaining < res.Remaining) {
+			res, found = limit, true
+		}
+	}
+	return res, found
+}
+
+// exhausted returns an exhausted limit a request for the given organization
+// and subject to the given limit type would run into. Rate limits apply to all
+// requests, ingest and query limits only to requests of their type.
+func (lc *limitCache) exhausted(orgID string, typ limitType, now time.Time) (Limit, bool) {
+	lc.mtx.RLock()
+	defer lc.mtx.RUnlock()
+
+	for key, limit := range lc.limits {
+		if key.orgID != orgID || (key.limitType != limitRate && key.limitType != typ) {
+			continue
+		}
+		if limit.Remaining == 0 && limit.until(now) > 0 {
+			return limit, true
+		}
+	}
+	return Limit{}, false
+}
+
+// requestLimitType returns the type of limit the given request is subject to,
+// besides the rate limit.
+func requestLimitType(req *http.Request) limitType {
+	switch path := req.URL.Path; {
+	case strings.HasSuffix(path, "/ingest"):
+		return limitIngest
+	case strings.HasSuffix(path, "/_apl"), strings.HasSuffix(path, "/query"):
+		return limitQuery
+	}
+	return limitRate
+}
+
+// LastLimit returns the limit reported by the server with the most recent
+// response that carried limit information. The zero value is returned if no
+// such response has been received, yet. It never sends a request.
+func (c *Client) LastLimit() Limit {
+	return c.limits.lastLimit()
+}
+
+// LimitRemaining returns the remaining count towards the limit of the given
+// scope and the time at which it resets, as reported by the server with the
+// most recent response for that scope. If multiple limits are known for the
+// scope, the one with the lowest remaining count is returned. Ingest and query
+// limits are reported with [LimitScopeUnknown]. If no limit is known for the
+// scope, -1 and the zero time are returned. It never sends a request.
+func (c *Client) LimitRemaining(scope LimitScope) (int, time.Time) {
+	limit, ok := c.limits.get(scope)
+	if !ok {
+		return -1, time.Time{}
+	}
+
+	remaining := math.MaxInt
+	if limit.Remaining < math.MaxInt {
+		remaining = int(limit.Remaining)
+	}
+	return remaining, limit.Reset
+}
+
+// limitStatus returns the http status code and text the server responds with
+// when the given limit is exceeded.
+func limitStatus(limit Limit) (int, string) {
+	if limit.limitType == limitRate {
+		return http.StatusTooManyRequests, http.StatusText(http.StatusTooManyRequests)
+	}
+	return httpStatusLimitExceeded, httpStatusTextLimitExceeded
+}
+
 // parseLimit parses the limit related headers from a http response.
 func parseLimit(r *http.Response) Limit {
 	var limit Limit
@@ -100,9 +250,27 @@ func parseLimit(r *http.Response) Limit {
 		limit = parseLimitFromHeaders(r, headerRateScope, headerRateLimit, headerRateRemaining, headerRateReset)
 		limit.limitType = limitRate
 	}
+	if limit.limitType != 0 {
+		limit.clockSkew = parseClockSkew(r, time.Now())
+	}
 	return limit
 }
 
+// parseClockSkew returns the difference between the server clock, as reported
+// by the "Date" header of the given response, and the given local time. Zero is
+// returned if the header is missing or the difference is below the resolution
+// of the header.
+func parseClockSkew(r *http.Response, now time.Time) time.Duration {
+	date, err := http.ParseTime(r.Header.Get(headerDate))
+	if err != nil {
+		return 0
+	}
+	if skew := date.Sub(now); skew > minClockSkew || skew < -minClockSkew {
+		return skew
+	}
+	return 0
+}
+
 // parseLimitFromHeaders parses the named headers from a  http response.
 func parseLimitFromHeaders(r *http.Response, headerScope, headerLimit, headerRemaining, headerReset string) Limit {
 	var limit Limit
@@ -2,6 +2,7 @@ package axiom
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 )
@@ -25,3 +26,31 @@ func TestLimitScopeFromString(t *testing.T) {
 		assert.Equal(t, l, parsed)
 	}
 }
+
+func TestLimitCache_exhausted(t *testing.T) {
+	now := time.Now()
+
+	var lc limitCache
+	lc.record("org-a", Limit{
+		Limit:     1000,
+		Remaining: 0,
+		Reset:     now.Add(time.Hour),
+		limitType: limitQuery,
+	})
+
+	// Exhausted for queries of the organization the limit was reported for...
+	limit, ok := lc.exhausted("org-a", limitQuery, now)
+	if assert.True(t, ok) {
+		assert.Equal(t, limitQuery, limit.limitType)
+	}
+
+	// ...but not for other request types or organizations.
+	_, ok = lc.exhausted("org-a", limitIngest, now)
+	assert.False(t, ok)
+	_, ok = lc.exhausted("org-b", limitQuery, now)
+	assert.False(t, ok)
+
+	// Not exhausted once the limit has reset.
+	_, ok = lc.exhausted("org-a", limitQuery, now.Add(2*time.Hour))
+	assert.False(t, ok)
+}
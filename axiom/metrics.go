@@ -0,0 +1,41 @@
+package axiom
+
+import (
+	"net/http"
+	"time"
+)
+
+// MetricsObserver observes the requests made by a [Client]. It can be used to
+// record request counts and latencies without wrapping the underlying
+// [http.RoundTripper]. Implementations must be safe for concurrent use.
+//
+// The [github.com/axiomhq/axiom-go/axiom/prometheus] package provides an
+// implementation backed by Prometheus metrics.
+type MetricsObserver interface {
+	// ObserveRequest is called after every attempt of a request. The status is
+	// zero, if no response was received.
+	ObserveRequest(method, path string, status int, dur time.Duration)
+	// ObserveRetry is called before a failed request is retried.
+	ObserveRetry(path string)
+}
+
+// observeRequest reports an attempt of the given request to the configured
+// metrics observer, if any.
+func (c *Client) observeRequest(req *http.Request, resp *http.Response, start time.Time) {
+	if c.metrics == nil {
+		return
+	}
+	var status int
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	c.metrics.ObserveRequest(req.Method, req.URL.Path, status, time.Since(start))
+}
+
+// observeRetry reports a retry of the given request to the configured metrics
+// observer, if any.
+func (c *Client) observeRetry(req *http.Request) {
+	if c.metrics != nil {
+		c.metrics.ObserveRetry(req.URL.Path)
+	}
+}
@@ -0,0 +1,736 @@
+package axiom
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/axiomhq/axiom-go/axiom/query"
+)
+
+//go:generate go run golang.org/x/tools/cmd/stringer -type=MonitorType,MonitorOperator -linecomment -output=monitors_string.go
+
+// ErrInvalidMonitor is raised when a [Monitor] is not valid and thus not sent
+// to the server.
+var ErrInvalidMonitor = errors.New("invalid monitor")
+
+// notifierUpdateMaxAttempts is the maximum amount of times the notifiers of a
+// monitor are read and written when the monitor is modified concurrently.
+const notifierUpdateMaxAttempts = 3
+
+// MonitorType represents the type of a [Monitor].
+type MonitorType uint8
+
+// All available [Monitor] types.
+const (
+	emptyMonitorType MonitorType = iota //
+
+	MonitorTypeThreshold        // Threshold
+	MonitorTypeAnomalyDetection // AnomalyDetection
+)
+
+func monitorTypeFromString(s string) (mt MonitorType, err error) {
+	switch s {
+	case emptyMonitorType.String():
+		mt = emptyMonitorType
+	case MonitorTypeThreshold.String():
+		mt = MonitorTypeThreshold
+	case MonitorTypeAnomalyDetection.String():
+		mt = MonitorTypeAnomalyDetection
+	default:
+		err = fmt.Errorf("unknown monitor type %q", s)
+	}
+
+	return mt, err
+}
+
+// MarshalJSON implements [json.Marshaler]. It is in place to marshal the
+// monitor type to its string representation because that's what the server
+// expects.
+func (mt MonitorType) MarshalJSON() ([]byte, error) {
+	return json.Marshal(mt.String())
+}
+
+// UnmarshalJSON implements [json.Unmarshaler]. It is in place to unmarshal the
+// monitor type from the string representation the server returns.
+func (mt *MonitorType) UnmarshalJSON(b []byte) (err error) {
+	var s string
+	if err = json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	*mt, err = monitorTypeFromString(s)
+
+	return err
+}
+
+// MonitorOperator represents the comparison operator of a [Monitor] used to
+// compare the query result against the threshold.
+type MonitorOperator uint8
+
+// All available [Monitor] operators.
+const (
+	emptyMonitorOperator MonitorOperator = iota //
+
+	MonitorOperatorBelow        // Below
+	MonitorOperatorBelowOrEqual // BelowOrEqual
+	MonitorOperatorAbove        // Above
+	MonitorOperatorAboveOrEqual // AboveOrEqual
+	MonitorOperatorEqual        // Equal
+)
+
+func monitorOperatorFromString(s string) (op MonitorOperator, err error) {
+	switch s {
+	case emptyMonitorOperator.String():
+		op = emptyMonitorOperator
+	case MonitorOperatorBelow.String():
+		op = MonitorOperatorBelow
+	case MonitorOperatorBelowOrEqual.String():
+		op = MonitorOperatorBelowOrEqual
+	case MonitorOperatorAbove.String():
+		op = MonitorOperatorAbove
+	case MonitorOperatorAboveOrEqual.String():
+		op = MonitorOperatorAboveOrEqual
+	case MonitorOperatorEqual.String():
+		op = MonitorOperatorEqual
+	default:
+		err = fmt.Errorf("unknown operator %q", s)
+	}
+
+	return op, err
+}
+
+// MarshalJSON implements [json.Marshaler]. It is in place to marshal the
+// operator to its string representation because that's what the server
+// expects.
+func (op MonitorOperator) MarshalJSON() ([]byte, error) {
+	return json.Marshal(op.String())
+}
+
+// UnmarshalJSON implements [json.Unmarshaler]. It is in place to unmarshal the
+// operator from the string representation the server returns.
+func (op *MonitorOperator) UnmarshalJSON(b []byte) (err error) {
+	var s string
+	if err = json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	*op, err = monitorOperatorFromString(s)
+
+	return err
+}
+
+// compare reports whether the given value crosses the threshold according to
+// the operator.
+func (op MonitorOperator) compare(value, threshold float64) bool {
+	switch op {
+	case MonitorOperatorBelow:
+		return value < threshold
+	case MonitorOperatorBelowOrEqual:
+		return value <= threshold
+	case MonitorOperatorAbove:
+		return value > threshold
+	case MonitorOperatorAboveOrEqual:
+		return value >= threshold
+	case MonitorOperatorEqual:
+		return value == threshold
+	}
+	return false
+}
+
+// Monitor represents a monitor which periodically runs an APL query and
+// alerts the configured notifiers when the result crosses the threshold.
+type Monitor struct {
+	// ID is the unique ID of the monitor.
+	ID string `json:"id,omitempty"`
+	// Type of the monitor. Defaults to [MonitorTypeThreshold], if not set.
+	Type MonitorType `json:"type,omitempty"`
+	// Name of the monitor.
+	Name string `json:"name"`
+	// Description of the monitor.
+	Description string `json:"description,omitempty"`
+	// AlertOnNoData specifies if the monitor alerts when the query returns no
+	// data.
+	AlertOnNoData bool `json:"alertOnNoData"`
+	// APLQuery is the APL query the monitor runs.
+	APLQuery string `json:"aplQuery"`
+	// Operator used to compare the query result against the threshold.
+	Operator MonitorOperator `json:"operator"`
+	// Threshold the query result is compared against. Only valid for
+	// [MonitorTypeThreshold] monitors.
+	Threshold float64 `json:"threshold"`
+	// Tolerance is the amount of standard deviations the query result is
+	// allowed to deviate from the expected value. Only valid for
+	// [MonitorTypeAnomalyDetection] monitors.
+	Tolerance float64 `json:"tolerance"`
+	// CompareDays is the amount of days the query result is compared against
+	// to determine the expected value. Only valid for
+	// [MonitorTypeAnomalyDetection] monitors.
+	CompareDays int `json:"compareDays"`
+	// Frequency is the interval the monitor runs at. It is sent to and
+	// returned by the server in minutes, so it must be a whole number of
+	// minutes and at least one minute.
+	Frequency time.Duration `json:"intervalMinutes"`
+	// Range is the time range the query of the monitor covers. It is sent to
+	// and returned by the server in minutes, so it must be a whole number of
+	// minutes.
+	Range time.Duration `json:"rangeMinutes"`
+	// NotifierIDs are the IDs of the notifiers alerted by the monitor.
+	NotifierIDs []string `json:"notifierIds"`
+	// DisabledUntil is the time until which the monitor is snoozed and doesn't
+	// alert its notifiers. The monitor isn't snoozed, if it is zero. See
+	// [MonitorsService.Snooze].
+	DisabledUntil time.Time `json:"disabledUntil"`
+}
+
+// MarshalJSON implements [json.Marshaler]. It is in place to marshal the
+// Frequency and Range to minutes because that's what the server expects. Partial
+// minutes are rounded up, so a positive duration is never sent as zero. Fields
+// that are not valid for the type of the monitor and a zero DisabledUntil are
+// omitted.
+func (m Monitor) MarshalJSON() ([]byte, error) {
+	type localMonitor Monitor
+
+	// Set to the value in minutes.
+	m.Frequency = toMinutes(m.Frequency)
+	m.Range = toMinutes(m.Range)
+
+	var disabledUntil *time.Time
+	if !m.DisabledUntil.IsZero() {
+		disabledUntil = &m.DisabledUntil
+	}
+
+	// Shadow the fields that don't apply to the type of the monitor with
+	// fields that are always omitted.
+	if m.Type == MonitorTypeAnomalyDetection {
+		return json.Marshal(struct {
+			localMonitor
+			Threshold     *float64   `json:"threshold,omitempty"`
+			DisabledUntil *time.Time `json:"disabledUntil,omitempty"`
+		}{
+			localMonitor:  localMonitor(m),
+			DisabledUntil: disabledUntil,
+		})
+	}
+	return json.Marshal(struct {
+		localMonitor
+		Tolerance     *float64   `json:"tolerance,omitempty"`
+		CompareDays   *int       `json:"compareDays,omitempty"`
+		DisabledUntil *time.Time `json:"disabledUntil,omitempty"`
+	}{
+		localMonitor:  localMonitor(m),
+		DisabledUntil: disabledUntil,
+	})
+}
+
+// UnmarshalJSON implements [json.Unmarshaler]. It is in place to unmarshal the
+// Frequency and Range into a proper [time.Duration] value because the server
+// returns them in minutes.
+func (m *Monitor) UnmarshalJSON(b []byte) error {
+	type localMonitor *Monitor
+
+	if err := json.Unmarshal(b, localMonitor(m)); err != nil {
+		return err
+	}
+
+	// Set to a proper [time.Duration] value by interpreting the server response
+	// value in minutes.
+	m.Frequency *= time.Minute
+	m.Range *= time.Minute
+
+	return nil
+}
+
+// toMinutes returns the given duration as a number of minutes, rounded up.
+func toMinutes(d time.Duration) time.Duration {
+	minutes := d / time.Minute
+	if d%time.Minute > 0 {
+		minutes++
+	}
+	return minutes
+}
+
+func (m Monitor) validate() error {
+	if m.APLQuery == "" {
+		return fmt.Errorf("%w: apl query is required", ErrInvalidMonitor)
+	} else if m.Frequency < time.Minute {
+		return fmt.Errorf("%w: frequency must be at least one minute", ErrInvalidMonitor)
+	} else if m.Frequency%time.Minute != 0 {
+		return fmt.Errorf("%w: frequency must be a whole number of minutes", ErrInvalidMonitor)
+	} else if m.Range < 0 {
+		return fmt.Errorf("%w: range must not be negative", ErrInvalidMonitor)
+	} else if m.Range%time.Minute != 0 {
+		return fmt.Errorf("%w: range must be a whole number of minutes", ErrInvalidMonitor)
+	}
+
+	switch m.Type {
+	case emptyMonitorType, MonitorTypeThreshold:
+		if m.Tolerance != 0 || m.CompareDays != 0 {
+			return fmt.Errorf("%w: tolerance and compare days must not be set for threshold monitors", ErrInvalidMonitor)
+		}
+	case MonitorTypeAnomalyDetection:
+		if m.Threshold != 0 {
+			return fmt.Errorf("%w: threshold must not be set for anomaly detection monitors", ErrInvalidMonitor)
+		}
+	default:
+		return fmt.Errorf("%w: unknown type %s", ErrInvalidMonitor, m.Type)
+	}
+
+	return nil
+}
+
+// MonitorTestResult is the result of a monitor evaluated by
+// [MonitorsService.Test].
+type MonitorTestResult struct {
+	// Fired reports whether the monitor would currently alert.
+	Fired bool
+	// Value is the observed value that was compared against the threshold. If
+	// the query returned multiple groups, it is the value of the first group
+	// that fired or, if none fired, the value of the first group. For queries
+	// without aggregations, it is the amount of matched events.
+	Value float64
+	// NoData reports whether the query returned no data. In that case, the
+	// monitor fired if it is configured to alert on no data.
+	NoData bool
+}
+
+// MonitorHistoryOptions specifies the optional parameters to
+// [MonitorsService.History].
+type MonitorHistoryOptions struct {
+	// StartTime restricts the evaluations to the ones at or after the given
+	// time.
+	StartTime time.Time `url:"startTime,omitempty"`
+	// EndTime restricts the evaluations to the ones before the given time.
+	EndTime time.Time `url:"endTime,omitempty"`
+	// Limit the amount of evaluations returned.
+	Limit uint `url:"limit,omitempty"`
+}
+
+// MonitorEvaluation is a past evaluation of a monitor, as returned by
+// [MonitorsService.History].
+type MonitorEvaluation struct {
+	// Time the monitor was evaluated at.
+	Time time.Time `json:"timestamp"`
+	// Value is the observed value that was compared against the threshold.
+	Value float64 `json:"value"`
+	// Triggered reports whether the evaluation alerted the notifiers of the
+	// monitor.
+	Triggered bool `json:"triggered"`
+	// Error describes why the evaluation failed, if it did.
+	Error string `json:"error,omitempty"`
+}
+
+// MonitorsService handles communication with the monitor related operations of
+// the Axiom API.
+//
+// Axiom API Reference: /v2/monitors
+type MonitorsService service
+
+// List all available monitors.
+func (s *MonitorsService) List(ctx context.Context) ([]*Monitor, error) {
+	ctx, span := s.client.trace(ctx, "Monitors.List")
+	defer span.End()
+
+	var res []*Monitor
+	if err := s.client.Call(ctx, http.MethodGet, s.basePath, nil, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return res, nil
+}
+
+// Get a monitor by id.
+func (s *MonitorsService) Get(ctx context.Context, id string) (*Monitor, error) {
+	ctx, span := s.client.trace(ctx, "Monitors.Get", trace.WithAttributes(
+		attribute.String("axiom.monitor_id", id),
+	))
+	defer span.End()
+
+	path, err := url.JoinPath(s.basePath, id)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	var res Monitor
+	if err := s.client.Call(ctx, http.MethodGet, path, nil, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return &res, nil
+}
+
+// Create a monitor with the given properties. The monitor must have an APL
+// query and a positive frequency, otherwise [ErrInvalidMonitor] is returned.
+func (s *MonitorsService) Create(ctx context.Context, req Monitor) (*Monitor, error) {
+	ctx, span := s.client.trace(ctx, "Monitors.Create", trace.WithAttributes(
+		attribute.String("axiom.param.name", req.Name),
+	))
+	defer span.End()
+
+	if err := req.validate(); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	var res Monitor
+	if err := s.client.Call(ctx, http.MethodPost, s.basePath, req, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return &res, nil
+}
+
+// Update the monitor identified by the given id with the given properties. The
+// same validation as for [MonitorsService.Create] applies.
+func (s *MonitorsService) Update(ctx context.Context, id string, req Monitor) (*Monitor, error) {
+	ctx, span := s.client.trace(ctx, "Monitors.Update", trace.WithAttributes(
+		attribute.String("axiom.monitor_id", id),
+		attribute.String("axiom.param.name", req.Name),
+	))
+	defer span.End()
+
+	if err := req.validate(); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	path, err := url.JoinPath(s.basePath, id)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	var res Monitor
+	if err := s.client.Call(ctx, http.MethodPut, path, req, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return &res, nil
+}
+
+// Snooze the monitor identified by the given id until the given time, e.g. for
+// the duration of a planned maintenance. A snoozed monitor keeps being
+// evaluated but doesn't alert its notifiers. The time must be in the future.
+func (s *MonitorsService) Snooze(ctx context.Context, id string, until time.Time) (*Monitor, error) {
+	ctx, span := s.client.trace(ctx, "Monitors.Snooze", trace.WithAttributes(
+		attribute.String("axiom.monitor_id", id),
+		attribute.String("axiom.param.until", until.String()),
+	))
+	defer span.End()
+
+	if !until.After(time.Now()) {
+		return nil, spanError(span, fmt.Errorf("%w: snooze time must be in the future", ErrInvalidMonitor))
+	}
+
+	res, err := s.setDisabledUntil(ctx, id, until)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return res, nil
+}
+
+// Unsnooze the monitor identified by the given id, so it alerts its notifiers
+// again.
+func (s *MonitorsService) Unsnooze(ctx context.Context, id string) (*Monitor, error) {
+	ctx, span := s.client.trace(ctx, "Monitors.Unsnooze", trace.WithAttributes(
+		attribute.String("axiom.monitor_id", id),
+	))
+	defer span.End()
+
+	res, err := s.setDisabledUntil(ctx, id, time.Time{})
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return res, nil
+}
+
+// setDisabledUntil updates the DisabledUntil field of the monitor identified by
+// the given id, leaving all its other properties untouched.
+func (s *MonitorsService) setDisabledUntil(ctx context.Context, id string, until time.Time) (*Monitor, error) {
+	monitor, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	monitor.DisabledUntil = until
+
+	return s.Update(ctx, id, *monitor)
+}
+
+// AttachNotifier adds the notifier identified by the given id to the notifiers
+// alerted by the monitor identified by the given id. Concurrent modifications of
+// the monitor are detected, if the server supports it, and the update is
+// retried, so they are not overwritten. If the update still fails because of a
+// concurrent modification, [ErrPreconditionFailed] is returned.
+func (s *MonitorsService) AttachNotifier(ctx context.Context, monitorID, notifierID string) (*Monitor, error) {
+	ctx, span := s.client.trace(ctx, "Monitors.AttachNotifier", trace.WithAttributes(
+		attribute.String("axiom.monitor_id", monitorID),
+		attribute.String("axiom.notifier_id", notifierID),
+	))
+	defer span.End()
+
+	res, err := s.updateNotifiers(ctx, monitorID, func(ids []string) ([]string, bool) {
+		for _, id := range ids {
+			if id == notifierID {
+				return ids, false
+			}
+		}
+		return append(ids, notifierID), true
+	})
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return res, nil
+}
+
+// DetachNotifier removes the notifier identified by the given id from the
+// notifiers alerted by the monitor identified by the given id. The same
+// concurrency guarantees as for [MonitorsService.AttachNotifier] apply.
+func (s *MonitorsService) DetachNotifier(ctx context.Context, monitorID, notifierID string) (*Monitor, error) {
+	ctx, span := s.client.trace(ctx, "Monitors.DetachNotifier", trace.WithAttributes(
+		attribute.String("axiom.monitor_id", monitorID),
+		attribute.String("axiom.notifier_id", notifierID),
+	))
+	defer span.End()
+
+	res, err := s.updateNotifiers(ctx, monitorID, func(ids []string) ([]string, bool) {
+		res := make([]string, 0, len(ids))
+		for _, id := range ids {
+			if id != notifierID {
+				res = append(res, id)
+			}
+		}
+		return res, len(res) != len(ids)
+	})
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return res, nil
+}
+
+// updateNotifiers reads the monitor identified by the given id, applies the
+// given function to its notifier IDs and writes the monitor back, if the
+// function reports a change. The entity tag of the monitor, if returned by the
+// server, is sent along with the update so the server rejects it, if the
+// monitor was modified in the meantime. In that case, the update is retried.
+func (s *MonitorsService) updateNotifiers(ctx context.Context, id string, f func(ids []string) ([]string, bool)) (*Monitor, error) {
+	path, err := url.JoinPath(s.basePath, id)
+	if err != nil {
+		return nil, err
+	}
+
+	for attempt := 1; ; attempt++ {
+		req, err := s.client.NewRequest(ctx, http.MethodGet, path, nil)
+		if err != nil {
+			return nil, err
+		}
+
+		var monitor Monitor
+		resp, err := s.client.Do(req, &monitor)
+		if err != nil {
+			return nil, err
+		}
+
+		var changed bool
+		if monitor.NotifierIDs, changed = f(monitor.NotifierIDs); !changed {
+			return &monitor, nil
+		}
+
+		if req, err = s.client.NewRequest(ctx, http.MethodPut, path, monitor); err != nil {
+			return nil, err
+		}
+		if etag := resp.Header.Get("ETag"); etag != "" {
+			req.Header.Set("If-Match", etag)
+		}
+
+		var res Monitor
+		if _, err = s.client.Do(req, &res); errors.Is(err, ErrPreconditionFailed) && attempt < notifierUpdateMaxAttempts {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+
+		return &res, nil
+	}
+}
+
+// Test evaluates the given monitor against recent data without saving it. The
+// APL query of the monitor is run over its range (or its frequency, if no range
+// is set) up until now and the result is compared against the threshold on the
+// client. Only threshold monitors can be tested.
+func (s *MonitorsService) Test(ctx context.Context, monitor Monitor) (*MonitorTestResult, error) {
+	ctx, span := s.client.trace(ctx, "Monitors.Test", trace.WithAttributes(
+		attribute.String("axiom.param.name", monitor.Name),
+	))
+	defer span.End()
+
+	if err := monitor.validate(); err != nil {
+		return nil, spanError(span, err)
+	} else if monitor.Type == MonitorTypeAnomalyDetection {
+		return nil, spanError(span, fmt.Errorf("%w: anomaly detection monitors can't be tested", ErrInvalidMonitor))
+	}
+
+	queryRange := monitor.Range
+	if queryRange <= 0 {
+		queryRange = monitor.Frequency
+	}
+
+	now := time.Now()
+	res, err := s.client.Datasets.Query(ctx, monitor.APLQuery,
+		query.SetStartTime(now.Add(-queryRange)),
+		query.SetEndTime(now),
+	)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return evaluateMonitor(monitor, res), nil
+}
+
+func evaluateMonitor(monitor Monitor, res *query.Result) *MonitorTestResult {
+	var values []float64
+	for _, total := range res.Buckets.Totals {
+		if len(total.Aggregations) == 0 {
+			continue
+		}
+		if v, ok := total.Aggregations[0].Value.(float64); ok {
+			values = append(values, v)
+		}
+	}
+
+	// Queries without aggregations are evaluated by the amount of matches.
+	if len(values) == 0 && len(res.Matches) > 0 {
+		values = append(values, float64(len(res.Matches)))
+	}
+
+	if len(values) == 0 {
+		return &MonitorTestResult{
+			Fired:  monitor.AlertOnNoData,
+			NoData: true,
+		}
+	}
+
+	for _, v := range values {
+		if monitor.Operator.compare(v, monitor.Threshold) {
+			return &MonitorTestResult{
+				Fired: true,
+				Value: v,
+			}
+		}
+	}
+
+	return &MonitorTestResult{
+		Value: values[0],
+	}
+}
+
+// History returns the recent evaluations of the monitor identified by the given
+// id, sorted by time with the oldest evaluation first. Unlike
+// [MonitorsService.Test], it reports the evaluations that actually happened on
+// the server, which helps with debugging flapping alerts.
+func (s *MonitorsService) History(ctx context.Context, id string, opts MonitorHistoryOptions) ([]*MonitorEvaluation, error) {
+	ctx, span := s.client.trace(ctx, "Monitors.History", trace.WithAttributes(
+		attribute.String("axiom.monitor_id", id),
+		attribute.String("axiom.param.start_time", opts.StartTime.String()),
+		attribute.String("axiom.param.end_time", opts.EndTime.String()),
+		attribute.Int("axiom.param.limit", int(opts.Limit)),
+	))
+	defer span.End()
+
+	path, err := url.JoinPath(s.basePath, id, "history")
+	if err != nil {
+		return nil, spanError(span, err)
+	} else if path, err = AddURLOptions(path, opts); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	var res []*MonitorEvaluation
+	if err := s.client.Call(ctx, http.MethodGet, path, nil, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Time.Before(res[j].Time)
+	})
+
+	return res, nil
+}
+
+// ExportAll exports the definitions of all available monitors as JSON. Server
+// assigned IDs are stripped and the monitors are sorted by name, so the export
+// is suitable for keeping in version control. Use [MonitorsService.ImportAll]
+// to restore the monitors.
+func (s *MonitorsService) ExportAll(ctx context.Context) ([]byte, error) {
+	ctx, span := s.client.trace(ctx, "Monitors.ExportAll")
+	defer span.End()
+
+	monitors, err := s.List(ctx)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	res := make([]Monitor, len(monitors))
+	for i, monitor := range monitors {
+		res[i] = *monitor
+		res[i].ID = ""
+	}
+	sort.SliceStable(res, func(i, j int) bool {
+		return res[i].Name < res[j].Name
+	})
+
+	b, err := json.MarshalIndent(res, "", "  ")
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return b, nil
+}
+
+// ImportAll creates the monitors exported by [MonitorsService.ExportAll]. As
+// notifiers usually have different IDs after being restored, the optional
+// notifierIDMap maps the notifier IDs found in the export to the ones to use
+// for the created monitors. Notifier IDs not present in the map are kept as
+// is.
+//
+// Import stops at the first monitor that can't be created. The monitors
+// created up to that point are returned alongside the error.
+func (s *MonitorsService) ImportAll(ctx context.Context, data []byte, notifierIDMap map[string]string) ([]*Monitor, error) {
+	ctx, span := s.client.trace(ctx, "Monitors.ImportAll")
+	defer span.End()
+
+	var monitors []Monitor
+	if err := json.Unmarshal(data, &monitors); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	res := make([]*Monitor, 0, len(monitors))
+	for _, monitor := range monitors {
+		monitor.ID = ""
+		if len(monitor.NotifierIDs) > 0 {
+			notifierIDs := make([]string, len(monitor.NotifierIDs))
+			for i, id := range monitor.NotifierIDs {
+				if mappedID, ok := notifierIDMap[id]; ok {
+					id = mappedID
+				}
+				notifierIDs[i] = id
+			}
+			monitor.NotifierIDs = notifierIDs
+		}
+
+		created, err := s.Create(ctx, monitor)
+		if err != nil {
+			return res, spanError(span, fmt.Errorf("import monitor %q: %w", monitor.Name, err))
+		}
+		res = append(res, created)
+	}
+
+	return res, nil
+}
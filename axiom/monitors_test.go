@@ -0,0 +1,783 @@
+package axiom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMonitorsService_List(t *testing.T) {
+	exp := []*Monitor{
+		{
+			ID:            "test",
+			Name:          "Test",
+			Description:   "A test monitor",
+			AlertOnNoData: true,
+			APLQuery:      "['test'] | summarize count()",
+			Operator:      MonitorOperatorAbove,
+			Threshold:     100,
+			Frequency:     time.Minute * 5,
+			Range:         time.Minute * 10,
+			NotifierIDs:   []string{"slack"},
+		},
+	}
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `[
+			{
+				"id": "test",
+				"name": "Test",
+				"description": "A test monitor",
+				"alertOnNoData": true,
+				"aplQuery": "['test'] | summarize count()",
+				"operator": "Above",
+				"threshold": 100,
+				"intervalMinutes": 5,
+				"rangeMinutes": 10,
+				"notifierIds": [
+					"slack"
+				]
+			}
+		]`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v2/monitors", hf)
+
+	res, err := client.Monitors.List(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, exp, res)
+}
+
+func TestMonitorsService_Create(t *testing.T) {
+	exp := &Monitor{
+		ID:          "test",
+		Name:        "Test",
+		APLQuery:    "['test'] | summarize count()",
+		Operator:    MonitorOperatorBelow,
+		Threshold:   1,
+		Frequency:   time.Minute,
+		Range:       time.Minute * 5,
+		NotifierIDs: []string{"slack"},
+	}
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, mediaTypeJSON, r.Header.Get("Content-Type"))
+
+		var req map[string]any
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+
+		assert.NotContains(t, req, "id")
+		assert.EqualValues(t, 1, req["intervalMinutes"])
+		assert.EqualValues(t, 5, req["rangeMinutes"])
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err = fmt.Fprint(w, `{
+			"id": "test",
+			"name": "Test",
+			"aplQuery": "['test'] | summarize count()",
+			"operator": "Below",
+			"threshold": 1,
+			"alertOnNoData": false,
+			"intervalMinutes": 1,
+			"rangeMinutes": 5,
+			"notifierIds": [
+				"slack"
+			]
+		}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v2/monitors", hf)
+
+	res, err := client.Monitors.Create(context.Background(), Monitor{
+		Name:        "Test",
+		APLQuery:    "['test'] | summarize count()",
+		Operator:    MonitorOperatorBelow,
+		Threshold:   1,
+		Frequency:   time.Minute,
+		Range:       time.Minute * 5,
+		NotifierIDs: []string{"slack"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, exp, res)
+}
+
+func TestMonitorsService_Get(t *testing.T) {
+	exp := &Monitor{
+		ID:          "test",
+		Name:        "Test",
+		APLQuery:    "['test'] | summarize count()",
+		Operator:    MonitorOperatorEqual,
+		Threshold:   0,
+		Frequency:   time.Minute,
+		Range:       time.Minute,
+		NotifierIDs: []string{},
+	}
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `{
+			"id": "test",
+			"name": "Test",
+			"aplQuery": "['test'] | summarize count()",
+			"operator": "Equal",
+			"threshold": 0,
+			"alertOnNoData": false,
+			"intervalMinutes": 1,
+			"rangeMinutes": 1,
+			"notifierIds": []
+		}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v2/monitors/test", hf)
+
+	res, err := client.Monitors.Get(context.Background(), "test")
+	require.NoError(t, err)
+
+	assert.Equal(t, exp, res)
+}
+
+func TestMonitorsService_Update(t *testing.T) {
+	exp := &Monitor{
+		ID:          "test",
+		Name:        "Test",
+		Description: "This is the new description",
+		APLQuery:    "['test'] | summarize count()",
+		Operator:    MonitorOperatorBelowOrEqual,
+		Threshold:   10,
+		Frequency:   time.Minute * 2,
+		Range:       time.Minute * 2,
+		NotifierIDs: []string{"slack"},
+	}
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, mediaTypeJSON, r.Header.Get("Content-Type"))
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `{
+			"id": "test",
+			"name": "Test",
+			"description": "This is the new description",
+			"aplQuery": "['test'] | summarize count()",
+			"operator": "BelowOrEqual",
+			"threshold": 10,
+			"alertOnNoData": false,
+			"intervalMinutes": 2,
+			"rangeMinutes": 2,
+			"notifierIds": [
+				"slack"
+			]
+		}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v2/monitors/test", hf)
+
+	res, err := client.Monitors.Update(context.Background(), "test", Monitor{
+		Name:        "Test",
+		Description: "This is the new description",
+		APLQuery:    "['test'] | summarize count()",
+		Operator:    MonitorOperatorBelowOrEqual,
+		Threshold:   10,
+		Frequency:   time.Minute * 2,
+		Range:       time.Minute * 2,
+		NotifierIDs: []string{"slack"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, exp, res)
+}
+
+func TestMonitorsService_Snooze(t *testing.T) {
+	until := time.Now().Add(time.Hour).UTC().Truncate(time.Second)
+
+	stored := Monitor{
+		ID:          "test",
+		Name:        "Test",
+		APLQuery:    "['test'] | summarize count()",
+		Operator:    MonitorOperatorAbove,
+		Threshold:   10,
+		Frequency:   time.Minute,
+		Range:       time.Minute,
+		NotifierIDs: []string{"slack"},
+	}
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			var req Monitor
+			err := json.NewDecoder(r.Body).Decode(&req)
+			require.NoError(t, err)
+			stored = req
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		err := json.NewEncoder(w).Encode(stored)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v2/monitors/test", hf)
+
+	res, err := client.Monitors.Snooze(context.Background(), "test", until)
+	require.NoError(t, err)
+	assert.True(t, until.Equal(res.DisabledUntil))
+	assert.Equal(t, "Test", res.Name)
+	assert.Equal(t, []string{"slack"}, res.NotifierIDs)
+
+	res, err = client.Monitors.Unsnooze(context.Background(), "test")
+	require.NoError(t, err)
+	assert.True(t, res.DisabledUntil.IsZero())
+	assert.Equal(t, float64(10), res.Threshold)
+
+	_, err = client.Monitors.Snooze(context.Background(), "test", time.Now().Add(-time.Minute))
+	assert.ErrorIs(t, err, ErrInvalidMonitor)
+}
+
+func TestMonitorsService_AttachDetachNotifier(t *testing.T) {
+	var (
+		version = 1
+		stored  = Monitor{
+			ID:          "test",
+			Name:        "Test",
+			APLQuery:    "['test'] | summarize count()",
+			Operator:    MonitorOperatorAbove,
+			Threshold:   10,
+			Frequency:   time.Minute,
+			Range:       time.Minute,
+			NotifierIDs: []string{"slack"},
+		}
+		conflicts = 1
+		puts      int
+	)
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut:
+			puts++
+			assert.Equal(t, fmt.Sprintf(`"%d"`, version), r.Header.Get("If-Match"))
+
+			// Simulate a concurrent modification of the monitor.
+			if conflicts > 0 {
+				conflicts--
+				version++
+				w.WriteHeader(http.StatusPreconditionFailed)
+				return
+			}
+
+			var req Monitor
+			err := json.NewDecoder(r.Body).Decode(&req)
+			require.NoError(t, err)
+			stored = req
+			version++
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		w.Header().Set("ETag", fmt.Sprintf(`"%d"`, version))
+		err := json.NewEncoder(w).Encode(stored)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v2/monitors/test", hf)
+
+	res, err := client.Monitors.AttachNotifier(context.Background(), "test", "email")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"slack", "email"}, res.NotifierIDs)
+	assert.Equal(t, 2, puts)
+
+	// Attaching a notifier that is already attached doesn't update the
+	// monitor.
+	res, err = client.Monitors.AttachNotifier(context.Background(), "test", "email")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"slack", "email"}, res.NotifierIDs)
+	assert.Equal(t, 2, puts)
+
+	res, err = client.Monitors.DetachNotifier(context.Background(), "test", "slack")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"email"}, res.NotifierIDs)
+	assert.Equal(t, 3, puts)
+
+	// Give up after too many concurrent modifications.
+	conflicts = notifierUpdateMaxAttempts
+	_, err = client.Monitors.DetachNotifier(context.Background(), "test", "email")
+	require.ErrorIs(t, err, ErrPreconditionFailed)
+	assert.Equal(t, 3+notifierUpdateMaxAttempts, puts)
+}
+
+func TestMonitorsService_Validation(t *testing.T) {
+	tests := []struct {
+		name    string
+		monitor Monitor
+		err     string
+	}{
+		{
+			name:    "missing apl query",
+			monitor: Monitor{Name: "Test", Frequency: time.Minute},
+			err:     "apl query is required",
+		},
+		{
+			name:    "zero frequency",
+			monitor: Monitor{Name: "Test", APLQuery: "['test']"},
+			err:     "frequency must be at least one minute",
+		},
+		{
+			name:    "negative frequency",
+			monitor: Monitor{Name: "Test", APLQuery: "['test']", Frequency: -time.Minute},
+			err:     "frequency must be at least one minute",
+		},
+		{
+			name:    "sub-minute frequency",
+			monitor: Monitor{Name: "Test", APLQuery: "['test']", Frequency: 30 * time.Second},
+			err:     "frequency must be at least one minute",
+		},
+		{
+			name:    "fractional frequency",
+			monitor: Monitor{Name: "Test", APLQuery: "['test']", Frequency: 90 * time.Second},
+			err:     "frequency must be a whole number of minutes",
+		},
+		{
+			name:    "negative range",
+			monitor: Monitor{Name: "Test", APLQuery: "['test']", Frequency: time.Minute, Range: -time.Minute},
+			err:     "range must not be negative",
+		},
+		{
+			name:    "fractional range",
+			monitor: Monitor{Name: "Test", APLQuery: "['test']", Frequency: time.Minute, Range: 150 * time.Second},
+			err:     "range must be a whole number of minutes",
+		},
+		{
+			name:    "threshold monitor with tolerance",
+			monitor: Monitor{Type: MonitorTypeThreshold, APLQuery: "['test']", Frequency: time.Minute, Tolerance: 2},
+			err:     "tolerance and compare days must not be set for threshold monitors",
+		},
+		{
+			name:    "untyped monitor with compare days",
+			monitor: Monitor{APLQuery: "['test']", Frequency: time.Minute, CompareDays: 7},
+			err:     "tolerance and compare days must not be set for threshold monitors",
+		},
+		{
+			name:    "anomaly detection monitor with threshold",
+			monitor: Monitor{Type: MonitorTypeAnomalyDetection, APLQuery: "['test']", Frequency: time.Minute, Threshold: 10},
+			err:     "threshold must not be set for anomaly detection monitors",
+		},
+		{
+			name:    "unknown type",
+			monitor: Monitor{Type: MonitorTypeAnomalyDetection + 1, APLQuery: "['test']", Frequency: time.Minute},
+			err:     "unknown type",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hf := func(w http.ResponseWriter, _ *http.Request) {
+				t.Error("invalid monitor should not be sent to the server")
+				w.WriteHeader(http.StatusBadRequest)
+			}
+
+			client := setup(t, "/v2/monitors/", hf)
+
+			_, err := client.Monitors.Create(context.Background(), tt.monitor)
+			require.ErrorIs(t, err, ErrInvalidMonitor)
+			assert.ErrorContains(t, err, tt.err)
+
+			_, err = client.Monitors.Update(context.Background(), "test", tt.monitor)
+			require.ErrorIs(t, err, ErrInvalidMonitor)
+			assert.ErrorContains(t, err, tt.err)
+		})
+	}
+}
+
+func TestMonitorsService_Test(t *testing.T) {
+	tests := []struct {
+		name     string
+		operator MonitorOperator
+		resp     string
+		exp      *MonitorTestResult
+	}{
+		{
+			name:     "fires",
+			operator: MonitorOperatorAbove,
+			resp:     `[{"id":1,"group":{"host":"a"},"aggregations":[{"op":"count_","value":5}]},{"id":2,"group":{"host":"b"},"aggregations":[{"op":"count_","value":15}]}]`,
+			exp:      &MonitorTestResult{Fired: true, Value: 15},
+		},
+		{
+			name:     "does not fire",
+			operator: MonitorOperatorBelow,
+			resp:     `[{"id":1,"group":{},"aggregations":[{"op":"count_","value":15}]}]`,
+			exp:      &MonitorTestResult{Fired: false, Value: 15},
+		},
+		{
+			name:     "no data",
+			operator: MonitorOperatorEqual,
+			resp:     `[]`,
+			exp:      &MonitorTestResult{Fired: true, NoData: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hf := func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+
+				var req aplQueryRequest
+				err := json.NewDecoder(r.Body).Decode(&req)
+				if assert.NoError(t, err) {
+					assert.Equal(t, "['test'] | summarize count() by host", req.APL)
+					assert.Equal(t, time.Minute*10, req.EndTime.Sub(req.StartTime))
+				}
+
+				w.Header().Set("Content-Type", mediaTypeJSON)
+				_, err = fmt.Fprintf(w, `{
+					"status": {},
+					"matches": [],
+					"buckets": {
+						"series": [],
+						"totals": %s
+					},
+					"datasetNames": [
+						"test"
+					]
+				}`, tt.resp)
+				assert.NoError(t, err)
+			}
+
+			client := setup(t, "/v1/datasets/_apl", hf)
+
+			res, err := client.Monitors.Test(context.Background(), Monitor{
+				Name:          "Test",
+				AlertOnNoData: true,
+				APLQuery:      "['test'] | summarize count() by host",
+				Operator:      tt.operator,
+				Threshold:     10,
+				Frequency:     time.Minute,
+				Range:         time.Minute * 10,
+			})
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.exp, res)
+		})
+	}
+}
+
+func TestMonitorsService_History(t *testing.T) {
+	exp := []*MonitorEvaluation{
+		{
+			Time:  parseTimeOrPanic("2023-03-21T13:38:51Z"),
+			Value: 3,
+		},
+		{
+			Time:      parseTimeOrPanic("2023-03-21T13:39:51Z"),
+			Value:     12,
+			Triggered: true,
+		},
+	}
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "2023-03-21T13:00:00Z", r.URL.Query().Get("startTime"))
+		assert.False(t, r.URL.Query().Has("endTime"))
+		assert.Equal(t, "10", r.URL.Query().Get("limit"))
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `[
+			{
+				"timestamp": "2023-03-21T13:39:51Z",
+				"value": 12,
+				"triggered": true
+			},
+			{
+				"timestamp": "2023-03-21T13:38:51Z",
+				"value": 3,
+				"triggered": false
+			}
+		]`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v2/monitors/test/history", hf)
+
+	res, err := client.Monitors.History(context.Background(), "test", MonitorHistoryOptions{
+		StartTime: parseTimeOrPanic("2023-03-21T13:00:00Z"),
+		Limit:     10,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, exp, res)
+}
+
+func TestMonitorsService_ExportImportAll(t *testing.T) {
+	monitor := Monitor{
+		ID:            "server-assigned",
+		Name:          "Test",
+		Description:   "A test monitor",
+		AlertOnNoData: true,
+		APLQuery:      "['test'] | summarize count()",
+		Operator:      MonitorOperatorAboveOrEqual,
+		Threshold:     42.5,
+		Frequency:     time.Minute * 5,
+		Range:         time.Minute * 10,
+		NotifierIDs:   []string{"old-notifier", "unmapped-notifier"},
+	}
+
+	var created []Monitor
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", mediaTypeJSON)
+
+		switch r.Method {
+		case http.MethodGet:
+			err := json.NewEncoder(w).Encode([]Monitor{monitor})
+			assert.NoError(t, err)
+		case http.MethodPost:
+			var req Monitor
+			err := json.NewDecoder(r.Body).Decode(&req)
+			require.NoError(t, err)
+
+			assert.Empty(t, req.ID)
+			created = append(created, req)
+
+			req.ID = "new-id"
+			err = json.NewEncoder(w).Encode(req)
+			assert.NoError(t, err)
+		default:
+			t.Errorf("unexpected method %s", r.Method)
+		}
+	}
+
+	client := setup(t, "/v2/monitors", hf)
+
+	data, err := client.Monitors.ExportAll(context.Background())
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "server-assigned")
+
+	res, err := client.Monitors.ImportAll(context.Background(), data, map[string]string{
+		"old-notifier": "new-notifier",
+	})
+	require.NoError(t, err)
+	require.Len(t, res, 1)
+	require.Len(t, created, 1)
+
+	exp := monitor
+	exp.ID = ""
+	exp.NotifierIDs = []string{"new-notifier", "unmapped-notifier"}
+	assert.Equal(t, exp, created[0])
+
+	exp.ID = "new-id"
+	assert.Equal(t, &exp, res[0])
+}
+
+func TestMonitorsService_ImportAll_Error(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}
+
+	client := setup(t, "/v2/monitors", hf)
+
+	res, err := client.Monitors.ImportAll(context.Background(), []byte(`[{"name":"Test","aplQuery":"['test']","intervalMinutes":1}]`), nil)
+	require.ErrorIs(t, err, ErrUnauthorized)
+	assert.ErrorContains(t, err, `import monitor "Test"`)
+	assert.Empty(t, res)
+}
+
+func TestMonitor_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		monitor Monitor
+		exp     string
+	}{
+		{
+			name: "threshold",
+			monitor: Monitor{
+				Type:      MonitorTypeThreshold,
+				Name:      "Test",
+				APLQuery:  "['test'] | summarize count()",
+				Operator:  MonitorOperatorAbove,
+				Threshold: 10,
+				Frequency: time.Minute,
+				Range:     time.Minute * 5,
+			},
+			exp: `{
+				"type": "Threshold",
+				"name": "Test",
+				"alertOnNoData": false,
+				"aplQuery": "['test'] | summarize count()",
+				"operator": "Above",
+				"threshold": 10,
+				"intervalMinutes": 1,
+				"rangeMinutes": 5,
+				"notifierIds": null
+			}`,
+		},
+		{
+			name: "anomaly detection",
+			monitor: Monitor{
+				Type:        MonitorTypeAnomalyDetection,
+				Name:        "Test",
+				APLQuery:    "['test'] | summarize count()",
+				Operator:    MonitorOperatorAbove,
+				Tolerance:   2.5,
+				CompareDays: 7,
+				Frequency:   time.Minute,
+				Range:       time.Minute * 5,
+			},
+			exp: `{
+				"type": "AnomalyDetection",
+				"name": "Test",
+				"alertOnNoData": false,
+				"aplQuery": "['test'] | summarize count()",
+				"operator": "Above",
+				"tolerance": 2.5,
+				"compareDays": 7,
+				"intervalMinutes": 1,
+				"rangeMinutes": 5,
+				"notifierIds": null
+			}`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := json.Marshal(tt.monitor)
+			require.NoError(t, err)
+			require.NotEmpty(t, b)
+
+			assert.JSONEq(t, tt.exp, string(b))
+
+			var act Monitor
+			require.NoError(t, json.Unmarshal(b, &act))
+			assert.Equal(t, tt.monitor, act)
+		})
+	}
+}
+
+func TestMonitor_MarshalJSON_PartialMinutes(t *testing.T) {
+	b, err := json.Marshal(Monitor{
+		Frequency: 30 * time.Second,
+		Range:     90 * time.Second,
+	})
+	require.NoError(t, err)
+
+	var act struct {
+		Frequency int `json:"intervalMinutes"`
+		Range     int `json:"rangeMinutes"`
+	}
+	require.NoError(t, json.Unmarshal(b, &act))
+
+	assert.Equal(t, 1, act.Frequency)
+	assert.Equal(t, 2, act.Range)
+}
+
+func TestMonitorType_Marshal(t *testing.T) {
+	exp := `{
+		"type": "AnomalyDetection"
+	}`
+
+	b, err := json.Marshal(struct {
+		Type MonitorType `json:"type"`
+	}{
+		Type: MonitorTypeAnomalyDetection,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, b)
+
+	assert.JSONEq(t, exp, string(b))
+}
+
+func TestMonitorType_Unmarshal(t *testing.T) {
+	var act struct {
+		Type MonitorType `json:"type"`
+	}
+	err := json.Unmarshal([]byte(`{ "type": "AnomalyDetection" }`), &act)
+	require.NoError(t, err)
+
+	assert.Equal(t, MonitorTypeAnomalyDetection, act.Type)
+}
+
+func TestMonitorType_String(t *testing.T) {
+	// Check outer bounds.
+	assert.Empty(t, MonitorType(0).String())
+	assert.Empty(t, emptyMonitorType.String())
+	assert.Equal(t, emptyMonitorType, MonitorType(0))
+	assert.Contains(t, (MonitorTypeAnomalyDetection + 1).String(), "MonitorType(")
+
+	for mt := MonitorTypeThreshold; mt <= MonitorTypeAnomalyDetection; mt++ {
+		s := mt.String()
+		assert.NotEmpty(t, s)
+		assert.NotContains(t, s, "MonitorType(")
+	}
+}
+
+func TestMonitorTypeFromString(t *testing.T) {
+	for mt := MonitorTypeThreshold; mt <= MonitorTypeAnomalyDetection; mt++ {
+		parsed, err := monitorTypeFromString(mt.String())
+		assert.NoError(t, err)
+		assert.Equal(t, mt, parsed)
+	}
+}
+
+func TestMonitorOperator_Marshal(t *testing.T) {
+	exp := `{
+		"operator": "Above"
+	}`
+
+	b, err := json.Marshal(struct {
+		Operator MonitorOperator `json:"operator"`
+	}{
+		Operator: MonitorOperatorAbove,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, b)
+
+	assert.JSONEq(t, exp, string(b))
+}
+
+func TestMonitorOperator_Unmarshal(t *testing.T) {
+	var act struct {
+		Operator MonitorOperator `json:"operator"`
+	}
+	err := json.Unmarshal([]byte(`{ "operator": "Above" }`), &act)
+	require.NoError(t, err)
+
+	assert.Equal(t, MonitorOperatorAbove, act.Operator)
+}
+
+func TestMonitorOperator_String(t *testing.T) {
+	// Check outer bounds.
+	assert.Empty(t, MonitorOperator(0).String())
+	assert.Empty(t, emptyMonitorOperator.String())
+	assert.Equal(t, emptyMonitorOperator, MonitorOperator(0))
+	assert.Contains(t, (MonitorOperatorEqual + 1).String(), "MonitorOperator(")
+
+	for op := MonitorOperatorBelow; op <= MonitorOperatorEqual; op++ {
+		s := op.String()
+		assert.NotEmpty(t, s)
+		assert.NotContains(t, s, "MonitorOperator(")
+	}
+}
+
+func TestMonitorOperatorFromString(t *testing.T) {
+	for op := MonitorOperatorBelow; op <= MonitorOperatorEqual; op++ {
+		parsed, err := monitorOperatorFromString(op.String())
+		assert.NoError(t, err)
+		assert.Equal(t, op, parsed)
+	}
+}
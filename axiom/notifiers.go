@@ -0,0 +1,386 @@
+package axiom
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// ErrInvalidNotifier is raised when a [Notifier] is not valid and thus not
+// sent to the server.
+var ErrInvalidNotifier = errors.New("invalid notifier")
+
+// NotifierConfig is the type specific configuration of a [Notifier]. It is
+// implemented by [NotifierSlack], [NotifierPagerDuty], [NotifierWebhook],
+// [NotifierMSTeams] and [NotifierDiscord]. Notifiers of types unknown to this
+// package are represented by [NotifierRaw].
+type NotifierConfig interface {
+	notifierType() string
+	validate() error
+}
+
+// NotifierSlack is the configuration of a [Notifier] that sends notifications
+// to a Slack channel using an incoming webhook.
+type NotifierSlack struct {
+	// ChannelURL is the URL of the incoming webhook of the Slack channel.
+	ChannelURL string `json:"slackUrl"`
+}
+
+func (NotifierSlack) notifierType() string { return "slack" }
+
+func (n NotifierSlack) validate() error {
+	return validateNotifierURL("slack channel url", n.ChannelURL, "https")
+}
+
+// NotifierPagerDuty is the configuration of a [Notifier] that sends
+// notifications to PagerDuty. Its String and GoString methods redact the
+// routing key and token so they don't end up in logs.
+type NotifierPagerDuty struct {
+	// RoutingKey is the integration key of the PagerDuty service.
+	RoutingKey string `json:"routingKey"`
+	// Token is the PagerDuty API token.
+	Token string `json:"token"`
+}
+
+func (NotifierPagerDuty) notifierType() string { return "pagerduty" }
+
+func (n NotifierPagerDuty) validate() error {
+	if n.RoutingKey == "" {
+		return fmt.Errorf("%w: pagerduty routing key is required", ErrInvalidNotifier)
+	}
+	return nil
+}
+
+// String implements [fmt.Stringer]. It redacts the routing key and token.
+func (n NotifierPagerDuty) String() string {
+	return fmt.Sprintf("{RoutingKey:%s Token:%s}", redact(n.RoutingKey), redact(n.Token))
+}
+
+// GoString implements [fmt.GoStringer]. It redacts the routing key and token.
+func (n NotifierPagerDuty) GoString() string {
+	return fmt.Sprintf("axiom.NotifierPagerDuty{RoutingKey:%q, Token:%q}", redact(n.RoutingKey), redact(n.Token))
+}
+
+// redact replaces a non-empty secret with a placeholder.
+func redact(secret string) string {
+	if secret == "" {
+		return ""
+	}
+	return "REDACTED"
+}
+
+// NotifierWebhook is the configuration of a [Notifier] that sends
+// notifications to a custom HTTP endpoint.
+type NotifierWebhook struct {
+	// URL of the endpoint to send the notifications to. Must be an absolute
+	// http or https URL.
+	URL string `json:"url"`
+	// Headers are additional headers sent with every notification.
+	Headers map[string]string `json:"headers,omitempty"`
+	// Body is the template of the notification body. It can reference the
+	// template variables provided by the server and is passed through as is.
+	Body string `json:"body,omitempty"`
+}
+
+func (NotifierWebhook) notifierType() string { return "webhook" }
+
+func (n NotifierWebhook) validate() error {
+	return validateNotifierURL("webhook url", n.URL, "http", "https")
+}
+
+// NotifierMSTeams is the configuration of a [Notifier] that sends
+// notifications to a Microsoft Teams channel using an incoming webhook.
+type NotifierMSTeams struct {
+	// WebhookURL is the URL of the incoming webhook of the Teams channel.
+	WebhookURL string `json:"microsoftTeamsUrl"`
+}
+
+func (NotifierMSTeams) notifierType() string { return "msteams" }
+
+func (n NotifierMSTeams) validate() error {
+	return validateNotifierURL("microsoft teams webhook url", n.WebhookURL, "https")
+}
+
+// NotifierDiscord is the configuration of a [Notifier] that sends
+// notifications to a Discord channel using a webhook.
+type NotifierDiscord struct {
+	// WebhookURL is the URL of the webhook of the Discord channel.
+	WebhookURL string `json:"discordWebhookUrl"`
+}
+
+func (NotifierDiscord) notifierType() string { return "discord" }
+
+func (n NotifierDiscord) validate() error {
+	return validateNotifierURL("discord webhook url", n.WebhookURL, "https")
+}
+
+// NotifierRaw is the configuration of a [Notifier] of a type that is not
+// known to this package. It preserves the properties returned by the server,
+// so it can be sent back as is.
+type NotifierRaw struct {
+	// Type of the notifier.
+	Type string
+	// Properties are the raw JSON properties of the notifier.
+	Properties json.RawMessage
+}
+
+func (n NotifierRaw) notifierType() string { return n.Type }
+
+func (n NotifierRaw) validate() error {
+	if n.Type == "" {
+		return fmt.Errorf("%w: raw notifier type is required", ErrInvalidNotifier)
+	}
+	return nil
+}
+
+// MarshalJSON implements [json.Marshaler]. It is in place to marshal the raw
+// properties of the notifier as is.
+func (n NotifierRaw) MarshalJSON() ([]byte, error) {
+	if len(n.Properties) == 0 {
+		return []byte("{}"), nil
+	}
+	return n.Properties, nil
+}
+
+// validateNotifierURL makes sure the given url is an absolute URL using one of
+// the given schemes.
+func validateNotifierURL(name, s string, schemes ...string) error {
+	u, err := url.Parse(s)
+	if err != nil {
+		return fmt.Errorf("%w: %s: %w", ErrInvalidNotifier, name, err)
+	} else if u.Host == "" {
+		return fmt.Errorf("%w: %s must be an absolute url", ErrInvalidNotifier, name)
+	}
+	for _, scheme := range schemes {
+		if u.Scheme == scheme {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s must use one of the schemes %s, got %q", ErrInvalidNotifier, name, strings.Join(schemes, ", "), u.Scheme)
+}
+
+// Notifier represents a notifier which is alerted by a [Monitor].
+type Notifier struct {
+	// ID is the unique ID of the notifier.
+	ID string
+	// Name of the notifier.
+	Name string
+	// Config is the type specific configuration of the notifier, e.g.
+	// [NotifierSlack].
+	Config NotifierConfig
+}
+
+type wireNotifier struct {
+	ID         string          `json:"id,omitempty"`
+	Name       string          `json:"name"`
+	Type       string          `json:"type"`
+	Properties json.RawMessage `json:"properties"`
+}
+
+// MarshalJSON implements [json.Marshaler]. It is in place to marshal the
+// notifier config alongside its type because that's what the server expects.
+func (n Notifier) MarshalJSON() ([]byte, error) {
+	if n.Config == nil {
+		return nil, fmt.Errorf("%w: missing config", ErrInvalidNotifier)
+	}
+
+	properties, err := json.Marshal(n.Config)
+	if err != nil {
+		return nil, err
+	}
+
+	return json.Marshal(wireNotifier{
+		ID:         n.ID,
+		Name:       n.Name,
+		Type:       n.Config.notifierType(),
+		Properties: properties,
+	})
+}
+
+// UnmarshalJSON implements [json.Unmarshaler]. It is in place to unmarshal the
+// notifier config into the concrete type indicated by the type the server
+// returns. Unknown types are unmarshalled into a [NotifierRaw].
+func (n *Notifier) UnmarshalJSON(b []byte) error {
+	var wn wireNotifier
+	if err := json.Unmarshal(b, &wn); err != nil {
+		return err
+	}
+
+	var err error
+	switch wn.Type {
+	case NotifierSlack{}.notifierType():
+		var config NotifierSlack
+		err = json.Unmarshal(wn.Properties, &config)
+		n.Config = config
+	case NotifierPagerDuty{}.notifierType():
+		var config NotifierPagerDuty
+		err = json.Unmarshal(wn.Properties, &config)
+		n.Config = config
+	case NotifierWebhook{}.notifierType():
+		var config NotifierWebhook
+		err = json.Unmarshal(wn.Properties, &config)
+		n.Config = config
+	case NotifierMSTeams{}.notifierType():
+		var config NotifierMSTeams
+		err = json.Unmarshal(wn.Properties, &config)
+		n.Config = config
+	case NotifierDiscord{}.notifierType():
+		var config NotifierDiscord
+		err = json.Unmarshal(wn.Properties, &config)
+		n.Config = config
+	default:
+		// Don't fail on notifier types introduced by the server after this
+		// package has been released.
+		n.Config = NotifierRaw{
+			Type:       wn.Type,
+			Properties: wn.Properties,
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	n.ID = wn.ID
+	n.Name = wn.Name
+
+	return nil
+}
+
+func (n Notifier) validate() error {
+	if n.Config == nil {
+		return fmt.Errorf("%w: missing config", ErrInvalidNotifier)
+	}
+	return n.Config.validate()
+}
+
+// NotifiersService handles communication with the notifier related operations
+// of the Axiom API.
+//
+// Axiom API Reference: /v2/notifiers
+type NotifiersService service
+
+// List all available notifiers.
+func (s *NotifiersService) List(ctx context.Context) ([]*Notifier, error) {
+	ctx, span := s.client.trace(ctx, "Notifiers.List")
+	defer span.End()
+
+	var res []*Notifier
+	if err := s.client.Call(ctx, http.MethodGet, s.basePath, nil, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return res, nil
+}
+
+// Get a notifier by id.
+func (s *NotifiersService) Get(ctx context.Context, id string) (*Notifier, error) {
+	ctx, span := s.client.trace(ctx, "Notifiers.Get", trace.WithAttributes(
+		attribute.String("axiom.notifier_id", id),
+	))
+	defer span.End()
+
+	path, err := url.JoinPath(s.basePath, id)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	var res Notifier
+	if err := s.client.Call(ctx, http.MethodGet, path, nil, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return &res, nil
+}
+
+// Create a notifier with the given properties. The config of the notifier is
+// validated before sending it to the server and [ErrInvalidNotifier] is
+// returned if it is not valid.
+func (s *NotifiersService) Create(ctx context.Context, req Notifier) (*Notifier, error) {
+	ctx, span := s.client.trace(ctx, "Notifiers.Create", trace.WithAttributes(
+		attribute.String("axiom.param.name", req.Name),
+	))
+	defer span.End()
+
+	if err := req.validate(); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	var res Notifier
+	if err := s.client.Call(ctx, http.MethodPost, s.basePath, req, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return &res, nil
+}
+
+// Update the notifier identified by the given id with the given properties.
+// The same validation as for [NotifiersService.Create] applies.
+func (s *NotifiersService) Update(ctx context.Context, id string, req Notifier) (*Notifier, error) {
+	ctx, span := s.client.trace(ctx, "Notifiers.Update", trace.WithAttributes(
+		attribute.String("axiom.notifier_id", id),
+		attribute.String("axiom.param.name", req.Name),
+	))
+	defer span.End()
+
+	if err := req.validate(); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	path, err := url.JoinPath(s.basePath, id)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	var res Notifier
+	if err := s.client.Call(ctx, http.MethodPut, path, req, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return &res, nil
+}
+
+// Test triggers a test notification for the notifier identified by the given
+// id.
+func (s *NotifiersService) Test(ctx context.Context, id string) error {
+	ctx, span := s.client.trace(ctx, "Notifiers.Test", trace.WithAttributes(
+		attribute.String("axiom.notifier_id", id),
+	))
+	defer span.End()
+
+	path, err := url.JoinPath(s.basePath, id, "test")
+	if err != nil {
+		return spanError(span, err)
+	}
+
+	if err := s.client.Call(ctx, http.MethodPost, path, nil, nil); err != nil {
+		return spanError(span, err)
+	}
+
+	return nil
+}
+
+// Delete the notifier identified by the given id.
+func (s *NotifiersService) Delete(ctx context.Context, id string) error {
+	ctx, span := s.client.trace(ctx, "Notifiers.Delete", trace.WithAttributes(
+		attribute.String("axiom.notifier_id", id),
+	))
+	defer span.End()
+
+	path, err := url.JoinPath(s.basePath, id)
+	if err != nil {
+		return spanError(span, err)
+	}
+
+	if err := s.client.Call(ctx, http.MethodDelete, path, nil, nil); err != nil {
+		return spanError(span, err)
+	}
+
+	return nil
+}
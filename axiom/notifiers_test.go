@@ -0,0 +1,458 @@
+package axiom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNotifiersService_List(t *testing.T) {
+	exp := []*Notifier{
+		{
+			ID:   "test",
+			Name: "Test",
+			Config: NotifierSlack{
+				ChannelURL: "https://hooks.slack.com/services/123",
+			},
+		},
+	}
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `[
+			{
+				"id": "test",
+				"name": "Test",
+				"type": "slack",
+				"properties": {
+					"slackUrl": "https://hooks.slack.com/services/123"
+				}
+			}
+		]`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v2/notifiers", hf)
+
+	res, err := client.Notifiers.List(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, exp, res)
+}
+
+func TestNotifiersService_Get(t *testing.T) {
+	exp := &Notifier{
+		ID:   "test",
+		Name: "Test",
+		Config: NotifierSlack{
+			ChannelURL: "https://hooks.slack.com/services/123",
+		},
+	}
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `{
+			"id": "test",
+			"name": "Test",
+			"type": "slack",
+			"properties": {
+				"slackUrl": "https://hooks.slack.com/services/123"
+			}
+		}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v2/notifiers/test", hf)
+
+	res, err := client.Notifiers.Get(context.Background(), "test")
+	require.NoError(t, err)
+
+	assert.Equal(t, exp, res)
+}
+
+func TestNotifiersService_Create(t *testing.T) {
+	exp := &Notifier{
+		ID:   "test",
+		Name: "Test",
+		Config: NotifierSlack{
+			ChannelURL: "https://hooks.slack.com/services/123",
+		},
+	}
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, mediaTypeJSON, r.Header.Get("Content-Type"))
+
+		var req map[string]any
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]any{
+			"name": "Test",
+			"type": "slack",
+			"properties": map[string]any{
+				"slackUrl": "https://hooks.slack.com/services/123",
+			},
+		}, req)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err = fmt.Fprint(w, `{
+			"id": "test",
+			"name": "Test",
+			"type": "slack",
+			"properties": {
+				"slackUrl": "https://hooks.slack.com/services/123"
+			}
+		}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v2/notifiers", hf)
+
+	res, err := client.Notifiers.Create(context.Background(), Notifier{
+		Name: "Test",
+		Config: NotifierSlack{
+			ChannelURL: "https://hooks.slack.com/services/123",
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, exp, res)
+}
+
+func TestNotifiersService_Create_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		notifier Notifier
+	}{
+		{
+			name:     "missing config",
+			notifier: Notifier{Name: "Test"},
+		},
+		{
+			name:     "empty url",
+			notifier: Notifier{Name: "Test", Config: NotifierSlack{}},
+		},
+		{
+			name:     "relative url",
+			notifier: Notifier{Name: "Test", Config: NotifierSlack{ChannelURL: "/services/123"}},
+		},
+		{
+			name:     "http url",
+			notifier: Notifier{Name: "Test", Config: NotifierSlack{ChannelURL: "http://hooks.slack.com/services/123"}},
+		},
+		{
+			name:     "malformed url",
+			notifier: Notifier{Name: "Test", Config: NotifierSlack{ChannelURL: "https://hooks slack com/%zz"}},
+		},
+		{
+			name:     "webhook with ftp url",
+			notifier: Notifier{Name: "Test", Config: NotifierWebhook{URL: "ftp://example.com/hook"}},
+		},
+		{
+			name:     "webhook with relative url",
+			notifier: Notifier{Name: "Test", Config: NotifierWebhook{URL: "example.com/hook"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hf := func(w http.ResponseWriter, _ *http.Request) {
+				t.Error("invalid notifier should not be sent to the server")
+				w.WriteHeader(http.StatusBadRequest)
+			}
+
+			client := setup(t, "/v2/notifiers", hf)
+
+			_, err := client.Notifiers.Create(context.Background(), tt.notifier)
+			assert.ErrorIs(t, err, ErrInvalidNotifier)
+		})
+	}
+}
+
+func TestNotifiersService_Create_PagerDuty(t *testing.T) {
+	exp := &Notifier{
+		ID:   "test",
+		Name: "On-Call",
+		Config: NotifierPagerDuty{
+			RoutingKey: "routing-key",
+			Token:      "token",
+		},
+	}
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+
+		var req map[string]any
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]any{
+			"name": "On-Call",
+			"type": "pagerduty",
+			"properties": map[string]any{
+				"routingKey": "routing-key",
+				"token":      "token",
+			},
+		}, req)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err = fmt.Fprint(w, `{
+			"id": "test",
+			"name": "On-Call",
+			"type": "pagerduty",
+			"properties": {
+				"routingKey": "routing-key",
+				"token": "token"
+			}
+		}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v2/notifiers", hf)
+
+	res, err := client.Notifiers.Create(context.Background(), Notifier{
+		Name: "On-Call",
+		Config: NotifierPagerDuty{
+			RoutingKey: "routing-key",
+			Token:      "token",
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, exp, res)
+
+	_, err = client.Notifiers.Create(context.Background(), Notifier{
+		Name:   "On-Call",
+		Config: NotifierPagerDuty{},
+	})
+	assert.ErrorIs(t, err, ErrInvalidNotifier)
+}
+
+func TestNotifiersService_Webhook(t *testing.T) {
+	config := NotifierWebhook{
+		URL: "http://alerts.internal/hook",
+		Headers: map[string]string{
+			"Authorization": "Bearer 123",
+		},
+		Body: `{"title":"{{.Title}}","body":"{{.Body}}"}`,
+	}
+
+	var stored []byte
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodPost && r.URL.Path == "/v2/notifiers",
+			r.Method == http.MethodPut && r.URL.Path == "/v2/notifiers/test":
+			var req map[string]any
+			err := json.NewDecoder(r.Body).Decode(&req)
+			require.NoError(t, err)
+
+			assert.Equal(t, "webhook", req["type"])
+			assert.Equal(t, map[string]any{
+				"url": "http://alerts.internal/hook",
+				"headers": map[string]any{
+					"Authorization": "Bearer 123",
+				},
+				"body": `{"title":"{{.Title}}","body":"{{.Body}}"}`,
+			}, req["properties"])
+
+			req["id"] = "test"
+			stored, err = json.Marshal(req)
+			require.NoError(t, err)
+		case r.Method == http.MethodGet && r.URL.Path == "/v2/notifiers/test":
+		default:
+			t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := w.Write(stored)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/", hf)
+
+	exp := &Notifier{
+		ID:     "test",
+		Name:   "Internal",
+		Config: config,
+	}
+
+	res, err := client.Notifiers.Create(context.Background(), Notifier{
+		Name:   "Internal",
+		Config: config,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, exp, res)
+
+	res, err = client.Notifiers.Get(context.Background(), "test")
+	require.NoError(t, err)
+	assert.Equal(t, exp, res)
+
+	res, err = client.Notifiers.Update(context.Background(), "test", *res)
+	require.NoError(t, err)
+	assert.Equal(t, exp, res)
+}
+
+func TestNotifierPagerDuty_Redacted(t *testing.T) {
+	config := NotifierPagerDuty{
+		RoutingKey: "routing-key",
+		Token:      "token",
+	}
+
+	for _, format := range []string{"%v", "%+v", "%#v", "%s"} {
+		s := fmt.Sprintf(format, config)
+		assert.NotContains(t, s, "routing-key", format)
+		assert.NotContains(t, s, "token\"", format)
+		assert.Contains(t, s, "REDACTED", format)
+	}
+
+	s := fmt.Sprintf("%+v", Notifier{Name: "On-Call", Config: config})
+	assert.NotContains(t, s, "routing-key")
+}
+
+func TestNotifiersService_Test(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	client := setup(t, "/v2/notifiers/test/test", hf)
+
+	err := client.Notifiers.Test(context.Background(), "test")
+	require.NoError(t, err)
+}
+
+func TestNotifiersService_Update(t *testing.T) {
+	exp := &Notifier{
+		ID:   "test",
+		Name: "New Name",
+		Config: NotifierSlack{
+			ChannelURL: "https://hooks.slack.com/services/456",
+		},
+	}
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, mediaTypeJSON, r.Header.Get("Content-Type"))
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `{
+			"id": "test",
+			"name": "New Name",
+			"type": "slack",
+			"properties": {
+				"slackUrl": "https://hooks.slack.com/services/456"
+			}
+		}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v2/notifiers/test", hf)
+
+	res, err := client.Notifiers.Update(context.Background(), "test", Notifier{
+		Name: "New Name",
+		Config: NotifierSlack{
+			ChannelURL: "https://hooks.slack.com/services/456",
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, exp, res)
+}
+
+func TestNotifiersService_Delete(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	client := setup(t, "/v2/notifiers/test", hf)
+
+	err := client.Notifiers.Delete(context.Background(), "test")
+	require.NoError(t, err)
+}
+
+func TestNotifier_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		exp  NotifierConfig
+	}{
+		{
+			name: "msteams",
+			in:   `{"id":"test","name":"Test","type":"msteams","properties":{"microsoftTeamsUrl":"https://example.webhook.office.com/123"}}`,
+			exp:  NotifierMSTeams{WebhookURL: "https://example.webhook.office.com/123"},
+		},
+		{
+			name: "discord",
+			in:   `{"id":"test","name":"Test","type":"discord","properties":{"discordWebhookUrl":"https://discord.com/api/webhooks/123"}}`,
+			exp:  NotifierDiscord{WebhookURL: "https://discord.com/api/webhooks/123"},
+		},
+		{
+			name: "unknown",
+			in:   `{"id":"test","name":"Test","type":"carrier-pigeon","properties":{"loft":"rooftop"}}`,
+			exp:  NotifierRaw{Type: "carrier-pigeon", Properties: json.RawMessage(`{"loft":"rooftop"}`)},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var act Notifier
+			err := json.Unmarshal([]byte(tt.in), &act)
+			require.NoError(t, err)
+
+			assert.Equal(t, "test", act.ID)
+			assert.Equal(t, "Test", act.Name)
+			assert.Equal(t, tt.exp, act.Config)
+
+			// Marshalling must produce the original input.
+			b, err := json.Marshal(act)
+			require.NoError(t, err)
+
+			assert.JSONEq(t, tt.in, string(b))
+		})
+	}
+}
+
+func TestNotifiersService_List_UnknownType(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `[
+			{
+				"id": "slack",
+				"name": "Slack",
+				"type": "slack",
+				"properties": {
+					"slackUrl": "https://hooks.slack.com/services/123"
+				}
+			},
+			{
+				"id": "future",
+				"name": "Future",
+				"type": "future",
+				"properties": {}
+			}
+		]`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v2/notifiers", hf)
+
+	res, err := client.Notifiers.List(context.Background())
+	require.NoError(t, err)
+	require.Len(t, res, 2)
+
+	assert.IsType(t, NotifierSlack{}, res[0].Config)
+	assert.IsType(t, NotifierRaw{}, res[1].Config)
+}
@@ -219,6 +219,23 @@ type Organization struct {
 	ModifiedAt time.Time `json:"metaModified"`
 }
 
+// OrganizationStatus is the usage and plan status of an [Organization].
+type OrganizationStatus struct {
+	// Plan the organization is on.
+	Plan Plan `json:"plan"`
+	// IngestedBytes is the amount of data in bytes ingested during the current
+	// month.
+	IngestedBytes uint64 `json:"ingestedBytes"`
+	// MonthlyIngestLimitBytes is the amount of data in bytes that can be
+	// ingested per month. It is zero if unlimited.
+	MonthlyIngestLimitBytes uint64 `json:"monthlyIngestLimitBytes"`
+	// QueryCount is the amount of queries run during the current month.
+	QueryCount uint64 `json:"queryCount"`
+	// LicenseExpiresAt is the time the license expires. It is only set for
+	// self-hosted deployments.
+	LicenseExpiresAt *time.Time `json:"licenseExpiresAt,omitempty"`
+}
+
 type wrappedOrganization struct {
 	*Organization
 
@@ -272,3 +289,25 @@ func (s *OrganizationsService) Get(ctx context.Context, id string) (*Organizatio
 
 	return res.Organization, nil
 }
+
+// Status retrieves the usage and plan status of the organization identified
+// by the given id. It allows monitoring the remaining ingest quota of the
+// current month.
+func (s *OrganizationsService) Status(ctx context.Context, id string) (*OrganizationStatus, error) {
+	ctx, span := s.client.trace(ctx, "Organizations.Status", trace.WithAttributes(
+		attribute.String("axiom.organization_id", id),
+	))
+	defer span.End()
+
+	path, err := url.JoinPath(s.basePath, id, "status")
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	var res OrganizationStatus
+	if err := s.client.Call(ctx, http.MethodGet, path, nil, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return &res, nil
+}
@@ -31,4 +31,11 @@ func (s *OrganizationsTestSuite) Test() {
 	s.Require().NotNil(organization)
 
 	s.Contains(organizations, organization)
+
+	// Get the status of the organization.
+	status, err := s.client.Organizations.Status(s.ctx, organization.ID)
+	s.Require().NoError(err)
+	s.Require().NotNil(status)
+
+	s.Equal(organization.Plan, status.Plan)
 }
@@ -194,6 +194,38 @@ func TestOrganizationsService_Get(t *testing.T) {
 	assert.Equal(t, exp, res)
 }
 
+func TestOrganizationsService_Status(t *testing.T) {
+	licenseExpiresAt := parseTimeOrPanic("2024-01-01T00:00:00Z")
+	exp := &OrganizationStatus{
+		Plan:                    Enterprise,
+		IngestedBytes:           5368709120,
+		MonthlyIngestLimitBytes: 1099511627776,
+		QueryCount:              1337,
+		LicenseExpiresAt:        &licenseExpiresAt,
+	}
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `{
+			"plan": "enterprise",
+			"ingestedBytes": 5368709120,
+			"monthlyIngestLimitBytes": 1099511627776,
+			"queryCount": 1337,
+			"licenseExpiresAt": "2024-01-01T00:00:00Z"
+		}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/orgs/axiom/status", hf)
+
+	res, err := client.Organizations.Status(context.Background(), "axiom")
+	require.NoError(t, err)
+
+	assert.Equal(t, exp, res)
+}
+
 func TestPlan_Marshal(t *testing.T) {
 	exp := `{
 		"plan": "personal"
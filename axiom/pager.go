@@ -0,0 +1,117 @@
+package axiom
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const headerLink = "Link"
+
+// Pager iterates over the pages of a list endpoint. Further pages are
+// indicated by the server using a "Link" header with the relation type "next",
+// as specified in [RFC 8288]. Endpoints that don't paginate return all items
+// with the first page. A Pager is not safe for concurrent use.
+//
+// [RFC 8288]: https://www.rfc-editor.org/rfc/rfc8288
+type Pager[T any] struct {
+	fetch func(ctx context.Context, path string) ([]T, string, error)
+	next  string
+	done  bool
+}
+
+// newPager returns a new [Pager] that starts at the given path and fetches
+// pages using the given function, which returns the items of a page and the
+// path of the next one, if any.
+func newPager[T any](path string, fetch func(ctx context.Context, path string) ([]T, string, error)) *Pager[T] {
+	return &Pager[T]{
+		fetch: fetch,
+		next:  path,
+	}
+}
+
+// HasMore reports whether there are more pages to fetch using [Pager.Next].
+func (p *Pager[T]) HasMore() bool {
+	return !p.done
+}
+
+// Next fetches the next page and returns its items. If there are no more pages,
+// it returns no items and no error. If fetching a page fails, the error is
+// returned and the same page is fetched again on the next call.
+func (p *Pager[T]) Next(ctx context.Context) ([]T, error) {
+	if p.done {
+		return nil, nil
+	}
+
+	items, next, err := p.fetch(ctx, p.next)
+	if err != nil {
+		return nil, err
+	}
+	p.next, p.done = next, next == ""
+
+	return items, nil
+}
+
+// Collect fetches all remaining pages and returns their items.
+func (p *Pager[T]) Collect(ctx context.Context) ([]T, error) {
+	var res []T
+	for p.HasMore() {
+		items, err := p.Next(ctx)
+		if err != nil {
+			return nil, err
+		}
+		res = append(res, items...)
+	}
+	return res, nil
+}
+
+// getPage fetches the page at the given path and decodes it into v. It returns
+// the path of the next page, if any.
+func (c *Client) getPage(ctx context.Context, path string, v any) (string, error) {
+	req, err := c.NewRequest(ctx, http.MethodGet, path, nil)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := c.Do(req, v)
+	if err != nil {
+		return "", err
+	}
+
+	return nextPage(resp.Header), nil
+}
+
+// nextPage returns the path, including the query, of the next page referenced
+// by the "Link" header, if any.
+func nextPage(header http.Header) string {
+	for _, v := range header.Values(headerLink) {
+		for _, link := range strings.Split(v, ",") {
+			target, params, ok := strings.Cut(strings.TrimSpace(link), ";")
+			if !ok || !strings.HasPrefix(target, "<") || !strings.HasSuffix(target, ">") {
+				continue
+			}
+
+			var isNext bool
+			for _, param := range strings.Split(params, ";") {
+				key, value, _ := strings.Cut(strings.TrimSpace(param), "=")
+				if !strings.EqualFold(key, "rel") {
+					continue
+				}
+				for _, rel := range strings.Fields(strings.Trim(value, `"`)) {
+					isNext = isNext || strings.EqualFold(rel, "next")
+				}
+			}
+			if !isNext {
+				continue
+			}
+
+			u, err := url.Parse(strings.Trim(target, "<>"))
+			if err != nil || u.Path == "" {
+				continue
+			}
+			return u.RequestURI()
+		}
+	}
+	return ""
+}
@@ -0,0 +1,133 @@
+package axiom
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPager(t *testing.T) {
+	var calls int
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+
+		calls++
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		switch cursor := r.URL.Query().Get("cursor"); cursor {
+		case "":
+			w.Header().Set("Link", `<https://api.axiom.co/v1/datasets?cursor=2>; rel="next"`)
+			_, _ = fmt.Fprint(w, `[{"id":"test1","name":"test1"}]`)
+		case "2":
+			// The first request for the second page fails.
+			if calls == 2 {
+				w.WriteHeader(http.StatusForbidden)
+				_, _ = fmt.Fprint(w, `{"message":"forbidden"}`)
+				return
+			}
+			_, _ = fmt.Fprint(w, `[{"id":"test2","name":"test2"},{"id":"test3","name":"test3"}]`)
+		default:
+			t.Errorf("unexpected cursor %q", cursor)
+		}
+	}
+
+	client := setup(t, "/v1/datasets", hf)
+
+	pager := client.Datasets.ListPager()
+	require.True(t, pager.HasMore())
+
+	datasets, err := pager.Next(context.Background())
+	require.NoError(t, err)
+	if assert.Len(t, datasets, 1) {
+		assert.Equal(t, "test1", datasets[0].ID)
+	}
+	assert.True(t, pager.HasMore())
+
+	_, err = pager.Next(context.Background())
+	assert.True(t, IsUnauthorized(err))
+	assert.True(t, pager.HasMore())
+
+	datasets, err = pager.Collect(context.Background())
+	require.NoError(t, err)
+	if assert.Len(t, datasets, 2) {
+		assert.Equal(t, "test2", datasets[0].ID)
+		assert.Equal(t, "test3", datasets[1].ID)
+	}
+	assert.False(t, pager.HasMore())
+
+	datasets, err = pager.Next(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, datasets)
+	assert.Equal(t, 3, calls)
+}
+
+func TestPager_NoPagination(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "2", r.URL.Query().Get("limit"))
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, _ = fmt.Fprint(w, `[]`)
+	}
+
+	client := setup(t, "/v1/starred", hf)
+
+	pager, err := client.StarredQueries.ListPager(StarredQueryListOptions{Limit: 2})
+	require.NoError(t, err)
+
+	res, err := pager.Collect(context.Background())
+	require.NoError(t, err)
+	assert.Empty(t, res)
+	assert.False(t, pager.HasMore())
+}
+
+func TestNextPage(t *testing.T) {
+	tests := []struct {
+		name  string
+		links []string
+		want  string
+	}{
+		{
+			name: "no link",
+		},
+		{
+			name:  "absolute",
+			links: []string{`<https://api.axiom.co/v1/datasets?cursor=abc>; rel="next"`},
+			want:  "/v1/datasets?cursor=abc",
+		},
+		{
+			name:  "relative",
+			links: []string{`</v1/users?page=2>; rel=next`},
+			want:  "/v1/users?page=2",
+		},
+		{
+			name:  "multiple links",
+			links: []string{`</v1/users?page=1>; rel="prev", </v1/users?page=3>; title="next"; rel="last next"`},
+			want:  "/v1/users?page=3",
+		},
+		{
+			name:  "multiple headers",
+			links: []string{`</v1/users?page=1>; rel="prev"`, `</v1/users?page=3>; rel="next"`},
+			want:  "/v1/users?page=3",
+		},
+		{
+			name:  "no next",
+			links: []string{`</v1/users?page=1>; rel="prev"`},
+		},
+		{
+			name:  "malformed",
+			links: []string{`/v1/users?page=3; rel="next"`},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := make(http.Header)
+			for _, link := range tt.links {
+				header.Add("Link", link)
+			}
+			assert.Equal(t, tt.want, nextPage(header))
+		})
+	}
+}
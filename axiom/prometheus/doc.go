@@ -0,0 +1,30 @@
+// Package prometheus provides a [Prometheus] backed implementation of the
+// [axiom.MetricsObserver] interface.
+//
+// Usage:
+//
+//	import (
+//	    "github.com/axiomhq/axiom-go/axiom"
+//	    axiomprom "github.com/axiomhq/axiom-go/axiom/prometheus"
+//	    "github.com/prometheus/client_golang/prometheus"
+//	)
+//	// ...
+//	observer := axiomprom.NewObserver()
+//	prometheus.MustRegister(observer)
+//
+//	client, err := axiom.NewClient(axiom.SetMetrics(observer))
+//
+// The following metrics are exported:
+//
+//   - axiom_client_requests_total: Counter of request attempts, partitioned by
+//     method, path and status code.
+//   - axiom_client_request_duration_seconds: Histogram of request attempt
+//     latencies, partitioned by method and path.
+//   - axiom_client_retries_total: Counter of request retries, partitioned by
+//     path.
+//
+// The path is the route of the request, e.g. "/v1/datasets/{id}/ingest", not
+// the actual path, so the cardinality of the metrics is bounded.
+//
+// [Prometheus]: https://prometheus.io
+package prometheus
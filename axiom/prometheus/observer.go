@@ -0,0 +1,93 @@
+package prometheus
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/axiomhq/axiom-go/axiom"
+)
+
+const namespace = "axiom_client"
+
+var _ interface {
+	axiom.MetricsObserver
+	prometheus.Collector
+} = (*Observer)(nil)
+
+// Observer implements [axiom.MetricsObserver] by recording request counts and
+// latencies as Prometheus metrics. It is a [prometheus.Collector] and must be
+// registered with a [prometheus.Registerer] in order to be exported.
+//
+// Request paths are labelled by their route, with the IDs and names of
+// resources replaced by "{id}", e.g. "/v1/datasets/{id}/ingest", to keep the
+// cardinality of the metrics bounded.
+type Observer struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+	retries  *prometheus.CounterVec
+}
+
+// NewObserver returns a new [Observer].
+func NewObserver() *Observer {
+	return &Observer{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "requests_total",
+			Help:      "Total number of request attempts made to the Axiom API.",
+		}, []string{"method", "path", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Name:      "request_duration_seconds",
+			Help:      "Latency of request attempts made to the Axiom API.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "path"}),
+		retries: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Name:      "retries_total",
+			Help:      "Total number of retried requests made to the Axiom API.",
+		}, []string{"path"}),
+	}
+}
+
+// ObserveRequest implements [axiom.MetricsObserver].
+func (o *Observer) ObserveRequest(method, path string, status int, dur time.Duration) {
+	path = route(path)
+	o.requests.WithLabelValues(method, path, strconv.Itoa(status)).Inc()
+	o.duration.WithLabelValues(method, path).Observe(dur.Seconds())
+}
+
+// ObserveRetry implements [axiom.MetricsObserver].
+func (o *Observer) ObserveRetry(path string) {
+	o.retries.WithLabelValues(route(path)).Inc()
+}
+
+// Describe implements [prometheus.Collector].
+func (o *Observer) Describe(ch chan<- *prometheus.Desc) {
+	o.requests.Describe(ch)
+	o.duration.Describe(ch)
+	o.retries.Describe(ch)
+}
+
+// Collect implements [prometheus.Collector].
+func (o *Observer) Collect(ch chan<- prometheus.Metric) {
+	o.requests.Collect(ch)
+	o.duration.Collect(ch)
+	o.retries.Collect(ch)
+}
+
+// route returns the route of the given request path. Paths of the Axiom API
+// alternate between the names of collections and the IDs of resources in them
+// after the version, e.g. "/v1/teams/{id}/members/{id}". The IDs are replaced
+// by "{id}", except for reserved names like "_apl" and "self".
+func route(path string) string {
+	segments := strings.Split(strings.Trim(path, "/"), "/")
+	for i := 2; i < len(segments); i += 2 {
+		if s := segments[i]; s != "self" && !strings.HasPrefix(s, "_") {
+			segments[i] = "{id}"
+		}
+	}
+	return "/" + strings.Join(segments, "/")
+}
@@ -0,0 +1,48 @@
+package prometheus_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	axiomprom "github.com/axiomhq/axiom-go/axiom/prometheus"
+)
+
+func TestObserver(t *testing.T) {
+	observer := axiomprom.NewObserver()
+
+	reg := prometheus.NewPedanticRegistry()
+	require.NoError(t, reg.Register(observer))
+
+	observer.ObserveRequest("POST", "/v1/datasets/test/ingest", 500, time.Millisecond)
+	observer.ObserveRetry("/v1/datasets/test/ingest")
+	observer.ObserveRequest("POST", "/v1/datasets/test/ingest", 200, time.Millisecond)
+	observer.ObserveRequest("POST", "/v1/datasets/logs/ingest", 200, time.Millisecond)
+	observer.ObserveRequest("POST", "/v1/datasets/_apl", 200, time.Millisecond)
+	observer.ObserveRequest("GET", "/v2/monitors/abc", 200, time.Millisecond)
+	observer.ObserveRequest("GET", "/v2/monitors/def", 200, time.Millisecond)
+	observer.ObserveRequest("DELETE", "/v1/teams/test/members/e9cffaad", 204, time.Millisecond)
+	observer.ObserveRequest("GET", "/v2/tokens/self", 200, time.Millisecond)
+
+	err := testutil.GatherAndCompare(reg, strings.NewReader(`
+		# HELP axiom_client_requests_total Total number of request attempts made to the Axiom API.
+		# TYPE axiom_client_requests_total counter
+		axiom_client_requests_total{code="200",method="POST",path="/v1/datasets/{id}/ingest"} 2
+		axiom_client_requests_total{code="500",method="POST",path="/v1/datasets/{id}/ingest"} 1
+		axiom_client_requests_total{code="200",method="POST",path="/v1/datasets/_apl"} 1
+		axiom_client_requests_total{code="200",method="GET",path="/v2/monitors/{id}"} 2
+		axiom_client_requests_total{code="204",method="DELETE",path="/v1/teams/{id}/members/{id}"} 1
+		axiom_client_requests_total{code="200",method="GET",path="/v2/tokens/self"} 1
+		# HELP axiom_client_retries_total Total number of retried requests made to the Axiom API.
+		# TYPE axiom_client_retries_total counter
+		axiom_client_retries_total{path="/v1/datasets/{id}/ingest"} 1
+	`), "axiom_client_requests_total", "axiom_client_retries_total")
+	assert.NoError(t, err)
+
+	assert.Equal(t, 5, testutil.CollectAndCount(observer, "axiom_client_request_duration_seconds"))
+}
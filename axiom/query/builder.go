@@ -0,0 +1,86 @@
+package query
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrMissingDataset is raised when a [Builder] is used without a dataset.
+var ErrMissingDataset = errors.New("missing dataset")
+
+// ErrHavingWithoutSummarize is raised when [Builder.Having] is used without a
+// preceding [Builder.Summarize].
+var ErrHavingWithoutSummarize = errors.New("having used without summarize")
+
+// Builder builds an APL query from a sequence of tabular operators. The
+// operators are piped in the order they are added. Errors are deferred until
+// [Builder.Build] is called, so calls can be chained.
+type Builder struct {
+	dataset string
+	stages  []string
+
+	summarized bool
+	err        error
+}
+
+// NewBuilder returns a new [Builder] which queries the given dataset.
+func NewBuilder(dataset string) *Builder {
+	return &Builder{dataset: dataset}
+}
+
+// Where filters the events by the given predicate, e.g. `status >= 500`.
+func (b *Builder) Where(expr string) *Builder {
+	b.stages = append(b.stages, "where "+expr)
+	return b
+}
+
+// Summarize aggregates the events using the given aggregation, e.g.
+// `count()`, optionally grouped by the given expressions.
+func (b *Builder) Summarize(aggregation string, by ...string) *Builder {
+	stage := "summarize " + aggregation
+	if len(by) > 0 {
+		stage += " by " + strings.Join(by, ", ")
+	}
+	b.stages = append(b.stages, stage)
+	b.summarized = true
+	return b
+}
+
+// Having filters the aggregated results by the given predicate, e.g.
+// `count_ > 100`. It must follow a [Builder.Summarize], otherwise
+// [ErrHavingWithoutSummarize] is returned by [Builder.Build].
+func (b *Builder) Having(expr string) *Builder {
+	if !b.summarized && b.err == nil {
+		b.err = fmt.Errorf("%w: %q", ErrHavingWithoutSummarize, expr)
+	}
+	b.stages = append(b.stages, "where "+expr)
+	return b
+}
+
+// Limit limits the amount of results returned.
+func (b *Builder) Limit(n int) *Builder {
+	b.stages = append(b.stages, fmt.Sprintf("limit %d", n))
+	return b
+}
+
+// Build returns the APL query or the first error that occurred while building
+// it.
+func (b *Builder) Build() (string, error) {
+	if b.err != nil {
+		return "", b.err
+	} else if b.dataset == "" {
+		return "", ErrMissingDataset
+	}
+
+	var sb strings.Builder
+	sb.WriteString("['")
+	sb.WriteString(strings.ReplaceAll(b.dataset, "'", `\'`))
+	sb.WriteString("']")
+	for _, stage := range b.stages {
+		sb.WriteString(" | ")
+		sb.WriteString(stage)
+	}
+
+	return sb.String(), nil
+}
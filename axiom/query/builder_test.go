@@ -0,0 +1,42 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder(t *testing.T) {
+	apl, err := NewBuilder("test").
+		Where("level == 'error'").
+		Summarize("count()", "service", "host").
+		Having("count_ > 100").
+		Limit(10).
+		Build()
+	require.NoError(t, err)
+
+	assert.Equal(t, "['test'] | where level == 'error' | summarize count() by service, host | where count_ > 100 | limit 10", apl)
+}
+
+func TestBuilder_HavingWithoutSummarize(t *testing.T) {
+	apl, err := NewBuilder("test").
+		Having("count_ > 100").
+		Summarize("count()").
+		Build()
+	require.ErrorIs(t, err, ErrHavingWithoutSummarize)
+
+	assert.Empty(t, apl)
+}
+
+func TestBuilder_MissingDataset(t *testing.T) {
+	_, err := NewBuilder("").Where("true").Build()
+	assert.ErrorIs(t, err, ErrMissingDataset)
+}
+
+func TestBuilder_EscapeDataset(t *testing.T) {
+	apl, err := NewBuilder("it's").Build()
+	require.NoError(t, err)
+
+	assert.Equal(t, `['it\'s']`, apl)
+}
@@ -0,0 +1,274 @@
+package query
+
+import (
+	"bufio"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"sort"
+	"strconv"
+	"time"
+)
+
+// timeColumn is the name of the column holding the time of an [Entry].
+const timeColumn = "_time"
+
+// WriteCSV writes the rows of the result to w as CSV, preceded by a header. See
+// [NewCSVWriter] for the rows and columns written. To export the results of
+// multiple queries, e.g. the pages of a paginated query, use a [CSVWriter]
+// instead.
+func WriteCSV(w io.Writer, result *Result) error {
+	cw := NewCSVWriter(w)
+	if err := cw.Write(result); err != nil {
+		return err
+	}
+	return cw.Flush()
+}
+
+// WriteNDJSON writes the rows of the result to w as newline delimited JSON, one
+// object per row. The rows and the order of their fields are the same as the
+// ones written by [WriteCSV], but fields not present in a row are omitted.
+// Times are formatted as RFC 3339. As it keeps no state, it can be called for
+// every page of a paginated query to stream the full result.
+func WriteNDJSON(w io.Writer, result *Result) error {
+	columns, rows := table(result)
+
+	bw := bufio.NewWriter(w)
+	for _, row := range rows {
+		if err := bw.WriteByte('{'); err != nil {
+			return err
+		}
+
+		first := true
+		for _, column := range columns {
+			v, ok := row[column]
+			if !ok {
+				continue
+			} else if t, isTime := v.(time.Time); isTime {
+				v = formatTime(t)
+			}
+
+			key, err := json.Marshal(column)
+			if err != nil {
+				return err
+			}
+			value, err := json.Marshal(v)
+			if err != nil {
+				return err
+			}
+
+			if !first {
+				if err = bw.WriteByte(','); err != nil {
+					return err
+				}
+			}
+			first = false
+
+			if _, err = bw.Write(key); err != nil {
+				return err
+			} else if err = bw.WriteByte(':'); err != nil {
+				return err
+			} else if _, err = bw.Write(value); err != nil {
+				return err
+			}
+		}
+
+		if _, err := bw.WriteString("}\n"); err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// CSVWriter writes the rows of query results to an [io.Writer] as CSV. The
+// header is written once, before the first row. This makes it possible to
+// stream the results of multiple queries, e.g. the pages of a paginated query,
+// into a single CSV document.
+type CSVWriter struct {
+	w       *csv.Writer
+	columns []string
+
+	wroteHeader bool
+}
+
+// NewCSVWriter returns a new [CSVWriter] writing to w.
+//
+// The rows written are the matches of a result, if it has any. Otherwise, they
+// are the groups of its time series, one row per interval and group, or, if
+// there are none, the groups of its totals. This way, the results of
+// aggregating queries are exported as well.
+//
+// The columns to write can be specified explicitly. Otherwise, they are
+// determined by the first result written: "_time", if the rows have a time,
+// followed by the fields of the matches in the order returned by the server,
+// as reported by [Result.Fields], or the fields grouped by followed by the
+// aliases of the aggregations for groups. Fields not present in the columns
+// are omitted.
+func NewCSVWriter(w io.Writer, columns ...string) *CSVWriter {
+	return &CSVWriter{
+		w:       csv.NewWriter(w),
+		columns: columns,
+	}
+}
+
+// Write the rows of the result as CSV. The header is written on the first
+// call.
+func (cw *CSVWriter) Write(result *Result) error {
+	columns, rows := table(result)
+
+	if !cw.wroteHeader {
+		if cw.columns == nil {
+			cw.columns = columns
+		}
+		if err := cw.w.Write(cw.columns); err != nil {
+			return err
+		}
+		cw.wroteHeader = true
+	}
+
+	record := make([]string, len(cw.columns))
+	for _, row := range rows {
+		for i, column := range cw.columns {
+			var err error
+			if record[i], err = formatValue(row[column]); err != nil {
+				return err
+			}
+		}
+
+		if err := cw.w.Write(record); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Flush writes any buffered data to the underlying [io.Writer].
+func (cw *CSVWriter) Flush() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// table returns the columns and rows of the result, as described by
+// [NewCSVWriter]. The time of a row is stored as a [time.Time] in the "_time"
+// column.
+func table(result *Result) ([]string, []map[string]any) {
+	var c columnSet
+
+	if len(result.Matches) > 0 {
+		c.add(timeColumn)
+		c.add(result.Fields...)
+
+		rows := make([]map[string]any, len(result.Matches))
+		var extra map[string]any
+		for i, entry := range result.Matches {
+			row := make(map[string]any, len(entry.Data)+1)
+			for k, v := range entry.Data {
+				row[k] = v
+				if !c.has(k) {
+					if extra == nil {
+						extra = make(map[string]any)
+					}
+					extra[k] = nil
+				}
+			}
+			row[timeColumn] = entry.Time
+			rows[i] = row
+		}
+
+		// Fields not reported by the server, e.g. for a result that was not
+		// returned by a query, are appended in lexical order.
+		c.add(dataColumns(extra)...)
+
+		return c.columns, rows
+	}
+
+	var rows []map[string]any
+	if len(result.Buckets.Series) > 0 {
+		c.add(timeColumn)
+		c.add(result.GroupBy...)
+		for _, interval := range result.Buckets.Series {
+			for _, group := range interval.Groups {
+				row := groupRow(&c, group)
+				row[timeColumn] = interval.StartTime
+				rows = append(rows, row)
+			}
+		}
+	} else {
+		c.add(result.GroupBy...)
+		for _, group := range result.Buckets.Totals {
+			rows = append(rows, groupRow(&c, group))
+		}
+	}
+
+	return c.columns, rows
+}
+
+// groupRow returns the row of the given group and adds the aliases of its
+// aggregations to the columns.
+func groupRow(c *columnSet, group EntryGroup) map[string]any {
+	row := make(map[string]any, len(group.Group)+len(group.Aggregations)+1)
+	for k, v := range group.Group {
+		row[k] = v
+	}
+	for _, agg := range group.Aggregations {
+		c.add(agg.Alias)
+		row[agg.Alias] = agg.Value
+	}
+	return row
+}
+
+// columnSet is an ordered set of columns.
+type columnSet struct {
+	columns []string
+	seen    map[string]bool
+}
+
+func (c *columnSet) add(columns ...string) {
+	for _, column := range columns {
+		if c.has(column) {
+			continue
+		} else if c.seen == nil {
+			c.seen = make(map[string]bool)
+		}
+		c.seen[column] = true
+		c.columns = append(c.columns, column)
+	}
+}
+
+func (c *columnSet) has(column string) bool {
+	return c.seen[column]
+}
+
+// dataColumns returns the keys of the given data in lexical order.
+func dataColumns(data map[string]any) []string {
+	columns := make([]string, 0, len(data))
+	for k := range data {
+		columns = append(columns, k)
+	}
+	sort.Strings(columns)
+	return columns
+}
+
+func formatTime(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
+
+// formatValue formats a field value for a CSV record. Strings are written as
+// is, times formatted as RFC 3339, other values JSON encoded.
+func formatValue(v any) (string, error) {
+	switch v := v.(type) {
+	case nil:
+		return "", nil
+	case time.Time:
+		return formatTime(v), nil
+	case string:
+		return v, nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	}
+	b, err := json.Marshal(v)
+	return string(b), err
+}
@@ -0,0 +1,157 @@
+package query
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var exportResult = &Result{
+	Matches: []Entry{
+		{
+			Time: time.Date(2023, 3, 21, 13, 38, 0, 0, time.UTC),
+			Data: map[string]any{
+				"message": `GET "/api", done`,
+				"status":  float64(200),
+				"tags":    []any{"a", "b"},
+			},
+		},
+		{
+			Time: time.Date(2023, 3, 21, 13, 39, 0, 500, time.UTC),
+			Data: map[string]any{
+				"cached": true,
+				"status": float64(304),
+			},
+		},
+	},
+}
+
+func TestWriteCSV(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteCSV(&buf, exportResult)
+	require.NoError(t, err)
+
+	assert.Equal(t, `_time,cached,message,status,tags
+2023-03-21T13:38:00Z,,"GET ""/api"", done",200,"[""a"",""b""]"
+2023-03-21T13:39:00.0000005Z,true,,304,
+`, buf.String())
+}
+
+func TestCSVWriter(t *testing.T) {
+	var buf bytes.Buffer
+	cw := NewCSVWriter(&buf)
+
+	// Write two pages, the header must only be written once.
+	for _, entry := range exportResult.Matches {
+		err := cw.Write(&Result{Matches: []Entry{entry}})
+		require.NoError(t, err)
+	}
+	require.NoError(t, cw.Flush())
+
+	assert.Equal(t, `_time,message,status,tags
+2023-03-21T13:38:00Z,"GET ""/api"", done",200,"[""a"",""b""]"
+2023-03-21T13:39:00.0000005Z,,304,
+`, buf.String())
+}
+
+func TestCSVWriter_Columns(t *testing.T) {
+	var buf bytes.Buffer
+	cw := NewCSVWriter(&buf, "status", "_time")
+
+	err := cw.Write(exportResult)
+	require.NoError(t, err)
+	require.NoError(t, cw.Flush())
+
+	assert.Equal(t, `status,_time
+200,2023-03-21T13:38:00Z
+304,2023-03-21T13:39:00.0000005Z
+`, buf.String())
+}
+
+func TestWriteNDJSON(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteNDJSON(&buf, exportResult)
+	require.NoError(t, err)
+
+	assert.Equal(t, `{"_time":"2023-03-21T13:38:00Z","message":"GET \"/api\", done","status":200,"tags":["a","b"]}
+{"_time":"2023-03-21T13:39:00.0000005Z","cached":true,"status":304}
+`, buf.String())
+}
+
+func TestWriteCSV_Fields(t *testing.T) {
+	res := *exportResult
+	res.Fields = []string{"status", "message", "tags", "cached"}
+
+	var buf bytes.Buffer
+	err := WriteCSV(&buf, &res)
+	require.NoError(t, err)
+
+	assert.Equal(t, `_time,status,message,tags,cached
+2023-03-21T13:38:00Z,200,"GET ""/api"", done","[""a"",""b""]",
+2023-03-21T13:39:00.0000005Z,304,,,true
+`, buf.String())
+}
+
+func TestWriteCSV_Aggregation(t *testing.T) {
+	totals := []EntryGroup{
+		{
+			Group: map[string]any{"status": float64(200)},
+			Aggregations: []EntryGroupAgg{
+				{Alias: "total", Value: float64(10)},
+				{Alias: "avg_duration", Value: 1.5},
+			},
+		},
+		{
+			Group: map[string]any{"status": float64(500)},
+			Aggregations: []EntryGroupAgg{
+				{Alias: "total", Value: float64(2)},
+				{Alias: "avg_duration", Value: float64(3)},
+			},
+		},
+	}
+
+	res := &Result{
+		GroupBy: []string{"status"},
+		Buckets: Timeseries{Totals: totals},
+	}
+
+	var buf bytes.Buffer
+	err := WriteCSV(&buf, res)
+	require.NoError(t, err)
+
+	assert.Equal(t, `status,total,avg_duration
+200,10,1.5
+500,2,3
+`, buf.String())
+
+	res.Buckets.Series = []Interval{
+		{
+			StartTime: time.Date(2023, 3, 21, 13, 0, 0, 0, time.UTC),
+			Groups:    totals[:1],
+		},
+		{
+			StartTime: time.Date(2023, 3, 21, 13, 1, 0, 0, time.UTC),
+			Groups:    totals[1:],
+		},
+	}
+
+	buf.Reset()
+	err = WriteCSV(&buf, res)
+	require.NoError(t, err)
+
+	assert.Equal(t, `_time,status,total,avg_duration
+2023-03-21T13:00:00Z,200,10,1.5
+2023-03-21T13:01:00Z,500,2,3
+`, buf.String())
+
+	buf.Reset()
+	err = WriteNDJSON(&buf, res)
+	require.NoError(t, err)
+
+	assert.Equal(t, `{"_time":"2023-03-21T13:00:00Z","status":200,"total":10,"avg_duration":1.5}
+{"_time":"2023-03-21T13:01:00Z","status":500,"total":2,"avg_duration":3}
+`, buf.String())
+}
@@ -0,0 +1,175 @@
+package query
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// tabularOperators are the tabular operators of APL that can follow a pipe.
+var tabularOperators = map[string]bool{
+	"as":              true,
+	"count":           true,
+	"distinct":        true,
+	"extend":          true,
+	"extend-valid":    true,
+	"externaldata":    true,
+	"getschema":       true,
+	"join":            true,
+	"limit":           true,
+	"lookup":          true,
+	"make-series":     true,
+	"mv-expand":       true,
+	"order":           true,
+	"parse":           true,
+	"parse-kv":        true,
+	"parse-where":     true,
+	"project":         true,
+	"project-away":    true,
+	"project-keep":    true,
+	"project-rename":  true,
+	"project-reorder": true,
+	"redact":          true,
+	"sample":          true,
+	"scan":            true,
+	"search":          true,
+	"sort":            true,
+	"summarize":       true,
+	"take":            true,
+	"top":             true,
+	"union":           true,
+	"where":           true,
+}
+
+// closingBrackets maps the opening brackets to their closing counterparts.
+var closingBrackets = map[byte]byte{
+	'(': ')',
+	'[': ']',
+	'{': '}',
+}
+
+// LintIssue is a problem in an APL query found by [Lint].
+type LintIssue struct {
+	// Offset is the byte offset of the problem in the query.
+	Offset int
+	// Message describes the problem.
+	Message string
+}
+
+// String returns the issue prefixed with its offset.
+func (i LintIssue) String() string {
+	return fmt.Sprintf("%d: %s", i.Offset, i.Message)
+}
+
+// Lint checks the given APL query for common syntax errors without sending it
+// to the server, e.g. to give early feedback in a query editor. It is not a
+// full parser: a query without issues can still be rejected by the server.
+//
+// Lint reports unterminated string literals, unbalanced brackets, pipes that
+// are not preceded by an expression or not followed by a tabular operator and
+// tabular operators that are not known. The issues are sorted by their offset.
+func Lint(apl string) []LintIssue {
+	if strings.TrimSpace(apl) == "" {
+		return []LintIssue{{Offset: 0, Message: "empty query"}}
+	}
+
+	var (
+		issues []LintIssue
+		// brackets holds the offsets of the brackets that are not closed, yet.
+		brackets []int
+		// pipe is the offset of the pipe that awaits a tabular operator or -1.
+		pipe = -1
+		// stageStart is true as long as no expression was found in the
+		// current pipeline stage.
+		stageStart = true
+	)
+	report := func(offset int, format string, args ...any) {
+		issues = append(issues, LintIssue{Offset: offset, Message: fmt.Sprintf(format, args...)})
+	}
+
+	for i := 0; i < len(apl); i++ {
+		c := apl[i]
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' {
+			continue
+		} else if c == '/' && i+1 < len(apl) && apl[i+1] == '/' {
+			// Skip the comment until the end of the line.
+			for i < len(apl) && apl[i] != '\n' {
+				i++
+			}
+			continue
+		}
+
+		if pipe >= 0 {
+			j := i
+			for j < len(apl) && isOperatorByte(apl[j]) {
+				j++
+			}
+			pipe = -1
+			if j == i {
+				report(i, "missing tabular operator after pipe")
+			} else {
+				if op := apl[i:j]; !tabularOperators[op] {
+					report(i, "unknown tabular operator %q", op)
+				}
+				i = j - 1
+				continue
+			}
+		}
+
+		switch c {
+		case '|':
+			if stageStart {
+				report(i, "missing expression before pipe")
+			}
+			pipe = i
+			continue
+		case ';':
+			stageStart = true
+			continue
+		case '(', '[', '{':
+			brackets = append(brackets, i)
+			stageStart = c == '('
+			continue
+		case ')', ']', '}':
+			if n := len(brackets); n > 0 && closingBrackets[apl[brackets[n-1]]] == c {
+				brackets = brackets[:n-1]
+			} else {
+				report(i, "unexpected %q", c)
+			}
+		case '"', '\'':
+			// Verbatim string literals, prefixed by "@", don't support escape
+			// sequences.
+			verbatim := i > 0 && apl[i-1] == '@'
+			j := i + 1
+			for ; j < len(apl) && apl[j] != c; j++ {
+				if apl[j] == '\\' && !verbatim {
+					j++
+				}
+			}
+			if j >= len(apl) {
+				report(i, "unterminated string literal")
+			}
+			i = j
+		}
+		stageStart = false
+	}
+
+	if pipe >= 0 {
+		report(pipe, "missing tabular operator after pipe")
+	}
+	for _, offset := range brackets {
+		report(offset, "unclosed %q", apl[offset])
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		return issues[i].Offset < issues[j].Offset
+	})
+
+	return issues
+}
+
+// isOperatorByte reports whether the given byte can be part of the name of a
+// tabular operator.
+func isOperatorByte(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
@@ -0,0 +1,89 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLint(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  []LintIssue
+	}{
+		{
+			name:  "valid",
+			input: "['test'] | where status == 200 | summarize count() by bin_auto(_time)",
+		},
+		{
+			name:  "valid multiline with comments and let",
+			input: "let threshold = 200;\n['test'] // all\n| where status >= threshold\n| project-away body",
+		},
+		{
+			name:  "valid nested pipeline",
+			input: "['a'] | union (['b'] | where x == 1) | take 10",
+		},
+		{
+			name:  "pipes and brackets in string literals",
+			input: `['test'] | where msg == "a | (b" or path == @'C:\dir'`,
+		},
+		{
+			name:  "empty",
+			input: " \n",
+			want:  []LintIssue{{Offset: 0, Message: "empty query"}},
+		},
+		{
+			name:  "unknown operator",
+			input: "['test'] | wehre status == 200",
+			want:  []LintIssue{{Offset: 11, Message: `unknown tabular operator "wehre"`}},
+		},
+		{
+			name:  "double pipe",
+			input: "['test'] | | take 1",
+			want:  []LintIssue{{Offset: 11, Message: "missing tabular operator after pipe"}},
+		},
+		{
+			name:  "trailing pipe",
+			input: "['test'] | take 1 |",
+			want:  []LintIssue{{Offset: 18, Message: "missing tabular operator after pipe"}},
+		},
+		{
+			name:  "leading pipe",
+			input: "| take 1",
+			want:  []LintIssue{{Offset: 0, Message: "missing expression before pipe"}},
+		},
+		{
+			name:  "unclosed bracket",
+			input: "['test'] | summarize count() by bin(_time, 1m",
+			want:  []LintIssue{{Offset: 35, Message: `unclosed '('`}},
+		},
+		{
+			name:  "unexpected bracket",
+			input: "['test'] | take 1)",
+			want:  []LintIssue{{Offset: 17, Message: `unexpected ')'`}},
+		},
+		{
+			name:  "unterminated string",
+			input: `['test'] | where msg == "oops`,
+			want:  []LintIssue{{Offset: 24, Message: "unterminated string literal"}},
+		},
+		{
+			name:  "multiple issues",
+			input: "['test' | wehre x == 1",
+			want: []LintIssue{
+				{Offset: 0, Message: `unclosed '['`},
+				{Offset: 10, Message: `unknown tabular operator "wehre"`},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Lint(tt.input))
+		})
+	}
+}
+
+func TestLintIssue_String(t *testing.T) {
+	assert.Equal(t, "3: unexpected ')'", LintIssue{Offset: 3, Message: "unexpected ')'"}.String())
+}
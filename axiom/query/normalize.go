@@ -0,0 +1,67 @@
+package query
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Normalize returns the canonical form of the given APL query. Queries that
+// only differ in whitespace, line breaks or comments have the same canonical
+// form, which allows for detecting queries that are equivalent, e.g. to share
+// cached results.
+//
+// Runs of whitespace and comments are collapsed into a single space, pipes are
+// surrounded by a single space and leading and trailing whitespace is removed.
+// String literals are left intact. The casing is not changed, as APL
+// identifiers, operators and string comparisons are case-sensitive.
+func Normalize(apl string) string {
+	var (
+		sb      strings.Builder
+		rs      = []rune(apl)
+		pending bool
+	)
+	sb.Grow(len(apl))
+
+	write := func(r rune) {
+		if pending && sb.Len() > 0 {
+			sb.WriteByte(' ')
+		}
+		pending = false
+		sb.WriteRune(r)
+	}
+
+	for i := 0; i < len(rs); i++ {
+		switch r := rs[i]; {
+		case unicode.IsSpace(r):
+			pending = true
+		case r == '/' && i+1 < len(rs) && rs[i+1] == '/':
+			// Skip the comment until the end of the line.
+			for i < len(rs) && rs[i] != '\n' {
+				i++
+			}
+			pending = true
+		case r == '|':
+			pending = true
+			write(r)
+			pending = true
+		case r == '"' || r == '\'':
+			// Copy the string literal verbatim. Verbatim string literals,
+			// prefixed by "@", don't support escape sequences.
+			verbatim := i > 0 && rs[i-1] == '@'
+			write(r)
+			for i++; i < len(rs); i++ {
+				sb.WriteRune(rs[i])
+				if rs[i] == '\\' && !verbatim && i+1 < len(rs) {
+					i++
+					sb.WriteRune(rs[i])
+				} else if rs[i] == r {
+					break
+				}
+			}
+		default:
+			write(r)
+		}
+	}
+
+	return sb.String()
+}
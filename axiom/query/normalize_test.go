@@ -0,0 +1,61 @@
+package query
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalize(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "empty",
+			input: "",
+			want:  "",
+		},
+		{
+			name:  "already normalized",
+			input: "['test'] | where status == 200",
+			want:  "['test'] | where status == 200",
+		},
+		{
+			name:  "whitespace",
+			input: "\n\t['test']\n|   where status  ==\t200 \n",
+			want:  "['test'] | where status == 200",
+		},
+		{
+			name:  "pipes",
+			input: "['test']|where status == 200|count",
+			want:  "['test'] | where status == 200 | count",
+		},
+		{
+			name:  "comments",
+			input: "['test'] // all events\n| count // the number of events",
+			want:  "['test'] | count",
+		},
+		{
+			name:  "string literals",
+			input: `['test'] | where msg == "a  |  b // c" and path == 'x\'  y'`,
+			want:  `['test'] | where msg == "a  |  b // c" and path == 'x\'  y'`,
+		},
+		{
+			name:  "verbatim string literals",
+			input: `['test'] | where path == @"C:\  dir\"  |  count`,
+			want:  `['test'] | where path == @"C:\  dir\" | count`,
+		},
+		{
+			name:  "casing",
+			input: "['Test'] | where Status == 'OK'",
+			want:  "['Test'] | where Status == 'OK'",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Normalize(tt.input))
+		})
+	}
+}
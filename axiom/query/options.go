@@ -1,6 +1,16 @@
 package query
 
-import "time"
+import (
+	"errors"
+	"time"
+)
+
+// now returns the current time. It is replaced in tests.
+var now = time.Now
+
+// ErrInvalidRange is raised when the start time of the query interval is not
+// before its end time.
+var ErrInvalidRange = errors.New("start time must be before end time")
 
 // Options specifies the optional parameters for a query.
 type Options struct {
@@ -19,8 +29,34 @@ type Options struct {
 	// the APL query. Defining variables in APL using the "let" keyword takes
 	// precedence over variables provided via the query options.
 	Variables map[string]any `json:"variables,omitempty"`
+	// NoCache omits the query cache and forces a fresh execution of the query.
+	NoCache bool `json:"-"`
+	// NumberMode specifies how numbers in the result rows are decoded.
+	NumberMode NumberMode `json:"-"`
+
+	// pinned is set by [Result.Next] and keeps [Last] and [Since] from
+	// replacing the interval of the query that is continued.
+	pinned bool
 }
 
+// NumberMode specifies how numbers in the result rows of a query, that is the
+// event data, group values and aggregation values, are decoded.
+type NumberMode uint8
+
+// All available number modes.
+const (
+	// NumberModeFloat64 decodes numbers into float64 values. Integers beyond
+	// 2^53 lose precision. This is the default.
+	NumberModeFloat64 NumberMode = iota
+	// NumberModeJSON decodes numbers into [encoding/json.Number] values which
+	// preserve their exact textual representation.
+	NumberModeJSON
+	// NumberModeInt64 decodes integral numbers into int64 values and all
+	// others into float64 values. Integers that overflow an int64 are decoded
+	// into [encoding/json.Number] values.
+	NumberModeInt64
+)
+
 // An Option applies an optional parameter to a query.
 type Option func(*Options)
 
@@ -38,13 +74,67 @@ func SetEndTime(endTime time.Time) Option {
 	return func(o *Options) { o.EndTime = endTime }
 }
 
+// SetRange specifies the start and end time of the query interval. It is a
+// shorthand for [SetStartTime] and [SetEndTime] and saves filtering the time in
+// the APL query itself. The start time must be before the end time, otherwise
+// the query fails with an [ErrInvalidRange]. If no interval is specified, the
+// server's default interval is used.
+func SetRange(start, end time.Time) Option {
+	return func(o *Options) { o.StartTime = start; o.EndTime = end }
+}
+
+// Last specifies the query interval as the given duration up until the time the
+// query is sent, e.g. the last 15 minutes. The interval is computed every time
+// a query is sent with this option. To paginate a query, pass [Result.Next] of
+// the previous page, which continues the query with the interval it was sent
+// with.
+func Last(d time.Duration) Option {
+	return relativeRange(func(t time.Time) (time.Time, time.Time) { return t.Add(-d), t })
+}
+
+// Since specifies the query interval as the time between the given start time
+// and the time the query is sent. The interval is computed every time a query
+// is sent with this option. To paginate a query, pass [Result.Next] of the
+// previous page, which continues the query with the interval it was sent with.
+func Since(start time.Time) Option {
+	return relativeRange(func(t time.Time) (time.Time, time.Time) { return start, t })
+}
+
+// relativeRange returns an option that sets the interval returned by the given
+// function for the current time, unless the interval has been pinned by
+// [Result.Next].
+func relativeRange(f func(now time.Time) (start, end time.Time)) Option {
+	return func(o *Options) {
+		if !o.pinned {
+			o.StartTime, o.EndTime = f(now())
+		}
+	}
+}
+
 // SetCursor specifies the cursor of the query. If include is set to true the
 // event that matches the cursor will be included in the result. When using this
-// option, please make sure to use the initial query's start and end times.
+// option, please make sure to use the initial query's start and end times. The
+// cursor to continue a previous query with is available as [Result.Cursor].
+// [Result.Next] takes care of both.
 func SetCursor(cursor string, include bool) Option {
 	return func(o *Options) { o.Cursor = cursor; o.IncludeCursor = include }
 }
 
+// SetNoCache specifies whether the query cache is omitted, forcing a fresh
+// execution of the query. This is useful to query recently ingested data which
+// might not be reflected by a cached result, yet.
+func SetNoCache(noCache bool) Option {
+	return func(o *Options) { o.NoCache = noCache }
+}
+
+// SetNumberMode specifies how numbers in the result rows are decoded. By
+// default, they are decoded into float64 values which can't represent large
+// integers, like 64-bit IDs or counters, exactly. Use [NumberModeJSON] or
+// [NumberModeInt64] to preserve their precision.
+func SetNumberMode(mode NumberMode) Option {
+	return func(o *Options) { o.NumberMode = mode }
+}
+
 // SetVariable adds a variable that can be referenced by the APL query. This
 // option can be called multiple times to add multiple variables. If a variable
 // with the same name already exists, it will be overwritten. Defining variables
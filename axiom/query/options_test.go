@@ -0,0 +1,86 @@
+package query
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLast(t *testing.T) {
+	clock := time.Date(2023, 3, 21, 13, 0, 0, 0, time.UTC)
+	setNow(t, func() time.Time { return clock })
+
+	option := Last(time.Hour)
+
+	// The interval is not computed when the option is created.
+	clock = clock.Add(time.Minute)
+
+	var opts Options
+	option(&opts)
+
+	assert.Equal(t, clock.Add(-time.Hour), opts.StartTime)
+	assert.Equal(t, clock, opts.EndTime)
+
+	// And recomputed every time the option is applied.
+	clock = clock.Add(time.Minute)
+
+	opts = Options{}
+	option(&opts)
+
+	assert.Equal(t, clock.Add(-time.Hour), opts.StartTime)
+	assert.Equal(t, clock, opts.EndTime)
+
+	// Unless a query is continued from its result.
+	res := Result{
+		Cursor:    "c9b8r8i6y5s0",
+		StartTime: opts.StartTime,
+		EndTime:   opts.EndTime,
+	}
+	first := clock
+	clock = clock.Add(time.Minute)
+
+	for _, options := range [][]Option{
+		{option, res.Next(false)},
+		{res.Next(false), option},
+	} {
+		opts = Options{}
+		for _, option := range options {
+			option(&opts)
+		}
+
+		assert.Equal(t, first.Add(-time.Hour), opts.StartTime)
+		assert.Equal(t, first, opts.EndTime)
+		assert.Equal(t, "c9b8r8i6y5s0", opts.Cursor)
+		assert.False(t, opts.IncludeCursor)
+	}
+}
+
+func TestSince(t *testing.T) {
+	clock := time.Date(2023, 3, 21, 13, 0, 0, 0, time.UTC)
+	setNow(t, func() time.Time { return clock })
+
+	start := clock.Add(-24 * time.Hour)
+	option := Since(start)
+
+	var opts Options
+	option(&opts)
+
+	assert.Equal(t, start, opts.StartTime)
+	assert.Equal(t, clock, opts.EndTime)
+
+	clock = clock.Add(time.Minute)
+
+	opts = Options{}
+	option(&opts)
+
+	assert.Equal(t, start, opts.StartTime)
+	assert.Equal(t, clock, opts.EndTime)
+}
+
+func setNow(t *testing.T, f func() time.Time) {
+	t.Helper()
+	old := now
+	now = f
+	t.Cleanup(func() { now = old })
+}
@@ -0,0 +1,10 @@
+package query
+
+// Request is a query specified using the Axiom Processing Language (APL)
+// together with its options. It describes a single query of a batch of queries.
+type Request struct {
+	// APL is the query to run.
+	APL string
+	// Options configure the query.
+	Options []Option
+}
@@ -135,6 +135,9 @@ type Result struct {
 	Status Status `json:"status"`
 	// Matches are the events that matched the query.
 	Matches []Entry `json:"matches"`
+	// Fields are the names of the fields of the matches, in the order they
+	// are returned by the server.
+	Fields []string `json:"-"`
 	// Buckets are the time series buckets.
 	Buckets Timeseries `json:"buckets"`
 	// GroupBy is a list of field names to group the query result by. Only valid
@@ -143,6 +146,39 @@ type Result struct {
 	// TraceID is the ID of the trace that was generated by the server for this
 	// results query request.
 	TraceID string `json:"-"`
+	// Cursor is the row ID of the last event in Matches. Pass it to
+	// [SetCursor], together with the start and end time of the initial query,
+	// to retrieve the events that follow it, or use [Result.Next]. It is empty
+	// if there are no matches.
+	Cursor string `json:"-"`
+	// More describes if more events than the ones in Matches are available,
+	// either because the result is partial or because the last event in
+	// Matches is not the oldest or newest row the server has seen for the
+	// query, as reported by [Status.MinCursor] and [Status.MaxCursor]. It is
+	// false if the server doesn't report these cursors. As the server may have
+	// seen rows that don't match the query, the next page can be empty even
+	// though More is true.
+	More bool `json:"-"`
+
+	// StartTime and EndTime are the interval the query was sent with, e.g. as
+	// computed by [Last] or [Since]. They are zero if no interval was
+	// specified.
+	StartTime time.Time `json:"-"`
+	EndTime   time.Time `json:"-"`
+}
+
+// Next returns an option that continues the query that returned the result
+// with the events that follow its last match, using the same interval as the
+// query. It takes precedence over the interval computed by [Last] or [Since],
+// so the options of the initial query can be passed again, along with it. If
+// include is set to true, the last match is included in the next result.
+func (r *Result) Next(include bool) Option {
+	start, end, cursor := r.StartTime, r.EndTime, r.Cursor
+	return func(o *Options) {
+		o.StartTime, o.EndTime = start, end
+		o.Cursor, o.IncludeCursor = cursor, include
+		o.pinned = true
+	}
 }
 
 // Status is the status of a query result.
@@ -267,6 +303,19 @@ type EntryGroup struct {
 	Aggregations []EntryGroupAgg `json:"aggregations"`
 }
 
+// Aggregation returns the value of the aggregation with the given alias. This
+// makes it possible to tell apart aggregations that use the same operation on
+// different fields, given they have been assigned distinct aliases at query
+// time.
+func (g EntryGroup) Aggregation(alias string) (any, bool) {
+	for _, agg := range g.Aggregations {
+		if agg.Alias == alias {
+			return agg.Value, true
+		}
+	}
+	return nil, false
+}
+
 // EntryGroupAgg is an aggregation which is part of a group of queried events.
 type EntryGroupAgg struct {
 	// Alias is the aggregations alias. If it wasn't specified at query time, it
@@ -174,3 +174,27 @@ func parseTimeOrPanic(value string) time.Time {
 	}
 	return t
 }
+
+func TestEntryGroup_Aggregation(t *testing.T) {
+	var group EntryGroup
+	err := json.Unmarshal([]byte(`{
+		"id": 1,
+		"group": {"host": "a"},
+		"aggregations": [
+			{"op": "total_bytes", "value": 1024},
+			{"op": "total_requests", "value": 8}
+		]
+	}`), &group)
+	require.NoError(t, err)
+
+	v, ok := group.Aggregation("total_bytes")
+	assert.True(t, ok)
+	assert.EqualValues(t, 1024, v)
+
+	v, ok = group.Aggregation("total_requests")
+	assert.True(t, ok)
+	assert.EqualValues(t, 8, v)
+
+	_, ok = group.Aggregation("sum")
+	assert.False(t, ok)
+}
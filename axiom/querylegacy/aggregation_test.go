@@ -52,3 +52,16 @@ func TestAggregationOpFromString(t *testing.T) {
 		assert.Equal(t, op, parsed)
 	}
 }
+
+func TestAggregation_Alias(t *testing.T) {
+	b, err := json.Marshal([]Aggregation{
+		{Alias: "total_bytes", Op: OpSum, Field: "bytes"},
+		{Alias: "total_requests", Op: OpSum, Field: "requests"},
+	})
+	require.NoError(t, err)
+
+	assert.JSONEq(t, `[
+		{"alias": "total_bytes", "op": "sum", "field": "bytes", "argument": null},
+		{"alias": "total_requests", "op": "sum", "field": "requests", "argument": null}
+	]`, string(b))
+}
@@ -0,0 +1,121 @@
+package querylegacy
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrInvalidTimeRange is raised when a [Builder] is used with a start time that
+// is not before its end time.
+var ErrInvalidTimeRange = errors.New("start time must be before end time")
+
+// ErrGroupByWithoutAggregation is raised when [Builder.GroupBy] is used without
+// an aggregation.
+var ErrGroupByWithoutAggregation = errors.New("group by used without aggregation")
+
+// Builder builds a structured [Query] from filters, aggregations, groupings
+// and orders. Errors are deferred until [Builder.Build] is called, so calls can
+// be chained. The query can be executed using the legacy query method of the
+// datasets service.
+type Builder struct {
+	query   Query
+	filters []Filter
+
+	err error
+}
+
+// NewBuilder returns a new [Builder] which queries the given interval.
+func NewBuilder(startTime, endTime time.Time) *Builder {
+	return &Builder{
+		query: Query{
+			StartTime: startTime,
+			EndTime:   endTime,
+		},
+	}
+}
+
+// AddFilter adds a filter on the given field using the given operation and
+// value. Multiple filters are combined using [OpAnd]. For more complex filters,
+// use [Builder.AddFilters]. The logical operations [OpAnd], [OpOr] and [OpNot]
+// are not valid for this method. Filters are validated using [Filter.Validate]
+// when the query is built.
+func (b *Builder) AddFilter(field string, op FilterOp, value any) *Builder {
+	switch op {
+	case OpAnd, OpOr, OpNot:
+		if b.err == nil {
+			b.err = fmt.Errorf("logical filter operation %q used as field filter on %q", op, field)
+		}
+	}
+	return b.AddFilters(Filter{
+		Op:    op,
+		Field: field,
+		Value: value,
+	})
+}
+
+// AddFilters adds the given filters. Multiple filters are combined using
+// [OpAnd].
+func (b *Builder) AddFilters(filters ...Filter) *Builder {
+	b.filters = append(b.filters, filters...)
+	return b
+}
+
+// AddAggregation adds the given aggregation.
+func (b *Builder) AddAggregation(aggregation Aggregation) *Builder {
+	b.query.Aggregations = append(b.query.Aggregations, aggregation)
+	return b
+}
+
+// GroupBy groups the aggregated results by the given fields. It requires at
+// least one aggregation, otherwise [ErrGroupByWithoutAggregation] is returned
+// by [Builder.Build].
+func (b *Builder) GroupBy(fields ...string) *Builder {
+	b.query.GroupBy = append(b.query.GroupBy, fields...)
+	return b
+}
+
+// Order orders the results by the given field, which must be grouped by or
+// used by an aggregation.
+func (b *Builder) Order(field string, desc bool) *Builder {
+	b.query.Order = append(b.query.Order, Order{
+		Field: field,
+		Desc:  desc,
+	})
+	return b
+}
+
+// Limit limits the amount of results returned.
+func (b *Builder) Limit(n uint32) *Builder {
+	b.query.Limit = n
+	return b
+}
+
+// Build returns the query or the first error that occurred while building it.
+func (b *Builder) Build() (Query, error) {
+	if b.err != nil {
+		return Query{}, b.err
+	} else if !b.query.StartTime.Before(b.query.EndTime) {
+		return Query{}, ErrInvalidTimeRange
+	} else if len(b.query.GroupBy) > 0 && len(b.query.Aggregations) == 0 {
+		return Query{}, ErrGroupByWithoutAggregation
+	}
+
+	q := b.query
+	switch len(b.filters) {
+	case 0:
+	case 1:
+		q.Filter = b.filters[0]
+	default:
+		q.Filter = Filter{
+			Op:       OpAnd,
+			Children: append([]Filter(nil), b.filters...),
+		}
+	}
+
+	if err := q.Filter.Validate(); err != nil {
+		return Query{}, err
+	}
+
+	return q, nil
+}
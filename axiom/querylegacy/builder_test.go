@@ -0,0 +1,70 @@
+package querylegacy
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder(t *testing.T) {
+	var (
+		endTime   = time.Date(2023, 3, 21, 13, 0, 0, 0, time.UTC)
+		startTime = endTime.Add(-time.Hour)
+	)
+
+	q, err := NewBuilder(startTime, endTime).
+		AddFilter("status", OpGreaterThanEqual, 500).
+		AddFilter("service", OpEqual, "api").
+		AddAggregation(Aggregation{Op: OpCount}).
+		GroupBy("host").
+		Order("host", false).
+		Limit(10).
+		Build()
+	require.NoError(t, err)
+
+	assert.Equal(t, Query{
+		StartTime:    startTime,
+		EndTime:      endTime,
+		Aggregations: []Aggregation{{Op: OpCount}},
+		GroupBy:      []string{"host"},
+		Filter: Filter{
+			Op: OpAnd,
+			Children: []Filter{
+				{Op: OpGreaterThanEqual, Field: "status", Value: 500},
+				{Op: OpEqual, Field: "service", Value: "api"},
+			},
+		},
+		Order: []Order{{Field: "host"}},
+		Limit: 10,
+	}, q)
+}
+
+func TestBuilder_SingleFilter(t *testing.T) {
+	q, err := NewBuilder(time.Unix(0, 0), time.Unix(60, 0)).
+		AddFilter("level", OpEqual, "error").
+		Build()
+	require.NoError(t, err)
+
+	assert.Equal(t, Filter{Op: OpEqual, Field: "level", Value: "error"}, q.Filter)
+}
+
+func TestBuilder_Errors(t *testing.T) {
+	var (
+		startTime = time.Unix(0, 0)
+		endTime   = time.Unix(60, 0)
+	)
+
+	_, err := NewBuilder(endTime, startTime).Build()
+	assert.ErrorIs(t, err, ErrInvalidTimeRange)
+
+	_, err = NewBuilder(startTime, endTime).GroupBy("host").Build()
+	assert.ErrorIs(t, err, ErrGroupByWithoutAggregation)
+
+	_, err = NewBuilder(startTime, endTime).AddFilter("status", OpAnd, nil).Build()
+	assert.EqualError(t, err, `logical filter operation "and" used as field filter on "status"`)
+
+	_, err = NewBuilder(startTime, endTime).AddFilter("status", OpEqual, nil).Build()
+	assert.ErrorIs(t, err, ErrInvalidFilter)
+}
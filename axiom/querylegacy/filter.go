@@ -2,10 +2,14 @@ package querylegacy
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"strings"
 )
 
+// ErrInvalidFilter is raised when a [Filter] is not valid.
+var ErrInvalidFilter = errors.New("invalid filter")
+
 //go:generate go run golang.org/x/tools/cmd/stringer -type=FilterOp -linecomment -output=filter_string.go
 
 // A FilterOp can be applied on queries to filter based on different conditions.
@@ -128,3 +132,36 @@ type Filter struct {
 	// [OpOr] and [OpNot].
 	Children []Filter `json:"children"`
 }
+
+// Validate the filter and all of its children. Logical operations ([OpAnd],
+// [OpOr] and [OpNot]) require children, all other operations except for
+// [OpExists] and [OpNotExists] require a field and a value. The zero value,
+// which filters nothing, is valid.
+func (f Filter) Validate() error {
+	switch f.Op {
+	case emptyFilterOp:
+		if f.Field != "" || f.Value != nil || len(f.Children) > 0 {
+			return fmt.Errorf("%w: missing operation", ErrInvalidFilter)
+		}
+	case OpAnd, OpOr, OpNot:
+		if len(f.Children) == 0 {
+			return fmt.Errorf("%w: %q operation without children", ErrInvalidFilter, f.Op)
+		}
+		for _, child := range f.Children {
+			if err := child.Validate(); err != nil {
+				return err
+			}
+		}
+	case OpExists, OpNotExists:
+		if f.Field == "" {
+			return fmt.Errorf("%w: %q operation without field", ErrInvalidFilter, f.Op)
+		}
+	default:
+		if f.Field == "" {
+			return fmt.Errorf("%w: %q operation without field", ErrInvalidFilter, f.Op)
+		} else if f.Value == nil {
+			return fmt.Errorf("%w: %q operation on %q without value", ErrInvalidFilter, f.Op, f.Field)
+		}
+	}
+	return nil
+}
@@ -55,3 +55,68 @@ func TestFilterOpFromString(t *testing.T) {
 		assert.Equal(t, op, parsed)
 	}
 }
+
+func TestFilter_Validate(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter Filter
+		err    string
+	}{
+		{
+			name: "empty",
+		},
+		{
+			name:   "value",
+			filter: Filter{Op: OpEqual, Field: "status", Value: 200},
+		},
+		{
+			name:   "exists without value",
+			filter: Filter{Op: OpExists, Field: "status"},
+		},
+		{
+			name: "nested",
+			filter: Filter{Op: OpOr, Children: []Filter{
+				{Op: OpNot, Children: []Filter{{Op: OpExists, Field: "error"}}},
+				{Op: OpContains, Field: "message", Value: "timeout"},
+			}},
+		},
+		{
+			name:   "missing operation",
+			filter: Filter{Field: "status", Value: 200},
+			err:    "invalid filter: missing operation",
+		},
+		{
+			name:   "missing value",
+			filter: Filter{Op: OpGreaterThan, Field: "status"},
+			err:    `invalid filter: ">" operation on "status" without value`,
+		},
+		{
+			name:   "missing field",
+			filter: Filter{Op: OpNotExists},
+			err:    `invalid filter: "not-exists" operation without field`,
+		},
+		{
+			name:   "missing children",
+			filter: Filter{Op: OpAnd},
+			err:    `invalid filter: "and" operation without children`,
+		},
+		{
+			name: "invalid child",
+			filter: Filter{Op: OpAnd, Children: []Filter{
+				{Op: OpStartsWith, Value: "GET"},
+			}},
+			err: `invalid filter: "starts-with" operation without field`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.filter.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, ErrInvalidFilter)
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
+}
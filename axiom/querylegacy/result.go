@@ -265,6 +265,19 @@ type EntryGroup struct {
 	Aggregations []EntryGroupAgg `json:"aggregations"`
 }
 
+// Aggregation returns the value of the aggregation with the given alias. This
+// makes it possible to tell apart aggregations that use the same operation on
+// different fields, given they have been assigned distinct aliases at query
+// time.
+func (g EntryGroup) Aggregation(alias string) (any, bool) {
+	for _, agg := range g.Aggregations {
+		if agg.Alias == alias {
+			return agg.Value, true
+		}
+	}
+	return nil, false
+}
+
 // EntryGroupAgg is an aggregation which is part of a group of queried events.
 type EntryGroupAgg struct {
 	// Alias is the aggregations alias. If it wasn't specified at query time, it
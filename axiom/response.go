@@ -1,20 +1,50 @@
 package axiom
 
-import "net/http"
+import (
+	"errors"
+	"io"
+	"net/http"
+	"time"
+)
+
+// ErrResponseTooLarge is returned when reading a response body that exceeds the
+// size configured using [SetMaxResponseBytes].
+var ErrResponseTooLarge = errors.New("response body too large")
 
 // Response wraps the default http response type. It never has an open body.
 type Response struct {
 	*http.Response
 
 	Limit Limit
+
+	// Header is a copy of the headers of the response, e.g. to read caching or
+	// deprecation headers. It shadows the header of the embedded
+	// [http.Response] and is safe to modify. The headers of the request are
+	// not included.
+	Header http.Header
+
+	// Duration is the time it took the final attempt to receive the response,
+	// not including reading its body.
+	Duration time.Duration
+	// Attempts is the number of attempts made to send the request.
+	Attempts int
+	// Retried reports whether the request was sent more than once.
+	Retried bool
 }
 
-// newResponse creates a new response from the given http response.
-func newResponse(r *http.Response) *Response {
+// newResponse creates a new response from the given http response which was
+// received with the given attempt that started at the given time.
+func newResponse(r *http.Response, start time.Time, attempt int) *Response {
 	return &Response{
 		Response: r,
 
 		Limit: parseLimit(r),
+
+		Header: r.Header.Clone(),
+
+		Duration: time.Since(start),
+		Attempts: attempt,
+		Retried:  attempt > 1,
 	}
 }
 
@@ -23,3 +53,31 @@ func newResponse(r *http.Response) *Response {
 func (r *Response) TraceID() string {
 	return r.Header.Get(headerTraceID)
 }
+
+// limitedBody is a response body that fails with [ErrResponseTooLarge] when
+// more than n bytes are read from it.
+type limitedBody struct {
+	io.ReadCloser
+
+	n int64
+}
+
+// Read implements [io.Reader].
+func (r *limitedBody) Read(p []byte) (int, error) {
+	if r.n <= 0 {
+		// The limit is reached. Only fail if there is more data to read.
+		var b [1]byte
+		n, err := r.ReadCloser.Read(b[:])
+		if n > 0 {
+			return 0, ErrResponseTooLarge
+		}
+		return 0, err
+	}
+
+	if int64(len(p)) > r.n {
+		p = p[:r.n]
+	}
+	n, err := r.ReadCloser.Read(p)
+	r.n -= int64(n)
+	return n, err
+}
@@ -0,0 +1,189 @@
+package axiom
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/axiomhq/axiom-go/axiom/querylegacy"
+)
+
+// StarredQuery represents a query starred by a user.
+type StarredQuery struct {
+	// ID is the unique ID of the starred query.
+	ID string `json:"id"`
+	// Name of the starred query.
+	Name string `json:"name"`
+	// Kind of the starred query. Either [querylegacy.APL] for APL queries or
+	// [querylegacy.Analytics] for legacy analytics queries.
+	Kind querylegacy.Kind `json:"kind"`
+	// Query is the query string of an APL query or the JSON encoded query of
+	// a legacy analytics query.
+	Query string `json:"query"`
+	// CreatedBy is the ID of the user that starred the query.
+	CreatedBy string `json:"createdBy,omitempty"`
+	// CreatedAt is the time the query was starred.
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// StarredQueryCreateRequest is a request used to star a query.
+type StarredQueryCreateRequest struct {
+	// Name of the starred query.
+	Name string `json:"name"`
+	// Kind of the starred query.
+	Kind querylegacy.Kind `json:"kind"`
+	// Query to star.
+	Query string `json:"query"`
+}
+
+// StarredQueryListOptions specifies the optional parameters to
+// [StarredQueriesService.List].
+type StarredQueryListOptions struct {
+	// Kind restricts the starred queries to the given kind.
+	Kind querylegacy.Kind `url:"kind,omitempty"`
+	// Owner restricts the starred queries to the ones starred by the user with
+	// the given ID.
+	Owner string `url:"who,omitempty"`
+	// Limit the amount of starred queries returned.
+	Limit uint `url:"limit,omitempty"`
+}
+
+type starredQueryShareRequest struct {
+	TeamID string `json:"teamId"`
+}
+
+// StarredQueriesService handles communication with the starred query related
+// operations of the Axiom API.
+//
+// Axiom API Reference: /v1/starred
+type StarredQueriesService service
+
+// List all starred queries matching the given options.
+func (s *StarredQueriesService) List(ctx context.Context, opts StarredQueryListOptions) ([]*StarredQuery, error) {
+	ctx, span := s.client.trace(ctx, "StarredQueries.List", trace.WithAttributes(
+		attribute.String("axiom.param.kind", opts.Kind.String()),
+		attribute.String("axiom.param.owner", opts.Owner),
+		attribute.Int("axiom.param.limit", int(opts.Limit)),
+	))
+	defer span.End()
+
+	path, err := AddURLOptions(s.basePath, opts)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	var res []*StarredQuery
+	if err := s.client.Call(ctx, http.MethodGet, path, nil, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return res, nil
+}
+
+// ListPager returns a [Pager] over all starred queries matching the given
+// options. The [StarredQueryListOptions.Limit] applies to each page.
+func (s *StarredQueriesService) ListPager(opts StarredQueryListOptions) (*Pager[*StarredQuery], error) {
+	path, err := AddURLOptions(s.basePath, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return newPager(path, func(ctx context.Context, path string) ([]*StarredQuery, string, error) {
+		ctx, span := s.client.trace(ctx, "StarredQueries.ListPager", trace.WithAttributes(
+			attribute.String("axiom.param.kind", opts.Kind.String()),
+			attribute.String("axiom.param.owner", opts.Owner),
+			attribute.Int("axiom.param.limit", int(opts.Limit)),
+		))
+		defer span.End()
+
+		var res []*StarredQuery
+		next, err := s.client.getPage(ctx, path, &res)
+		if err != nil {
+			return nil, "", spanError(span, err)
+		}
+
+		return res, next, nil
+	}), nil
+}
+
+// Get a starred query by id.
+func (s *StarredQueriesService) Get(ctx context.Context, id string) (*StarredQuery, error) {
+	ctx, span := s.client.trace(ctx, "StarredQueries.Get", trace.WithAttributes(
+		attribute.String("axiom.starred_query_id", id),
+	))
+	defer span.End()
+
+	path, err := url.JoinPath(s.basePath, id)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	var res StarredQuery
+	if err := s.client.Call(ctx, http.MethodGet, path, nil, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return &res, nil
+}
+
+// Create a starred query with the given properties.
+func (s *StarredQueriesService) Create(ctx context.Context, req StarredQueryCreateRequest) (*StarredQuery, error) {
+	ctx, span := s.client.trace(ctx, "StarredQueries.Create", trace.WithAttributes(
+		attribute.String("axiom.param.name", req.Name),
+		attribute.String("axiom.param.kind", req.Kind.String()),
+	))
+	defer span.End()
+
+	var res StarredQuery
+	if err := s.client.Call(ctx, http.MethodPost, s.basePath, req, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return &res, nil
+}
+
+// Delete the starred query identified by the given id.
+func (s *StarredQueriesService) Delete(ctx context.Context, id string) error {
+	ctx, span := s.client.trace(ctx, "StarredQueries.Delete", trace.WithAttributes(
+		attribute.String("axiom.starred_query_id", id),
+	))
+	defer span.End()
+
+	path, err := url.JoinPath(s.basePath, id)
+	if err != nil {
+		return spanError(span, err)
+	}
+
+	if err := s.client.Call(ctx, http.MethodDelete, path, nil, nil); err != nil {
+		return spanError(span, err)
+	}
+
+	return nil
+}
+
+// Share the starred query identified by the given id with the team identified
+// by the given team id. The starred query becomes visible to all members of
+// the team.
+func (s *StarredQueriesService) Share(ctx context.Context, id, teamID string) (*StarredQuery, error) {
+	ctx, span := s.client.trace(ctx, "StarredQueries.Share", trace.WithAttributes(
+		attribute.String("axiom.starred_query_id", id),
+		attribute.String("axiom.team_id", teamID),
+	))
+	defer span.End()
+
+	path, err := url.JoinPath(s.basePath, id, "share")
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	var res StarredQuery
+	if err := s.client.Call(ctx, http.MethodPost, path, starredQueryShareRequest{TeamID: teamID}, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return &res, nil
+}
@@ -0,0 +1,193 @@
+package axiom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/axiomhq/axiom-go/axiom/querylegacy"
+)
+
+func TestStarredQueriesService_List(t *testing.T) {
+	exp := []*StarredQuery{
+		{
+			ID:        "NBYj9rO5p4F5CtYEy6",
+			Name:      "Errors",
+			Kind:      querylegacy.APL,
+			Query:     "['logs'] | where level == \"error\"",
+			CreatedBy: "e9cffaad-60e7-4b04-8d27-185e1808c38c",
+			CreatedAt: parseTimeOrPanic("2023-03-01T12:00:00Z"),
+		},
+	}
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "apl", r.URL.Query().Get("kind"))
+		assert.Equal(t, "e9cffaad-60e7-4b04-8d27-185e1808c38c", r.URL.Query().Get("who"))
+		assert.Equal(t, "10", r.URL.Query().Get("limit"))
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `[
+			{
+				"id": "NBYj9rO5p4F5CtYEy6",
+				"name": "Errors",
+				"kind": "apl",
+				"query": "['logs'] | where level == \"error\"",
+				"createdBy": "e9cffaad-60e7-4b04-8d27-185e1808c38c",
+				"createdAt": "2023-03-01T12:00:00Z"
+			}
+		]`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/starred", hf)
+
+	res, err := client.StarredQueries.List(context.Background(), StarredQueryListOptions{
+		Kind:  querylegacy.APL,
+		Owner: "e9cffaad-60e7-4b04-8d27-185e1808c38c",
+		Limit: 10,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, exp, res)
+}
+
+func TestStarredQueriesService_List_NoOptions(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Empty(t, r.URL.RawQuery)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `[]`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/starred", hf)
+
+	res, err := client.StarredQueries.List(context.Background(), StarredQueryListOptions{})
+	require.NoError(t, err)
+
+	assert.Empty(t, res)
+}
+
+func TestStarredQueriesService_Get(t *testing.T) {
+	exp := &StarredQuery{
+		ID:        "NBYj9rO5p4F5CtYEy6",
+		Name:      "Errors",
+		Kind:      querylegacy.Analytics,
+		Query:     `{"resolution":"auto"}`,
+		CreatedBy: "e9cffaad-60e7-4b04-8d27-185e1808c38c",
+		CreatedAt: parseTimeOrPanic("2023-03-01T12:00:00Z"),
+	}
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `{
+			"id": "NBYj9rO5p4F5CtYEy6",
+			"name": "Errors",
+			"kind": "analytics",
+			"query": "{\"resolution\":\"auto\"}",
+			"createdBy": "e9cffaad-60e7-4b04-8d27-185e1808c38c",
+			"createdAt": "2023-03-01T12:00:00Z"
+		}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/starred/NBYj9rO5p4F5CtYEy6", hf)
+
+	res, err := client.StarredQueries.Get(context.Background(), "NBYj9rO5p4F5CtYEy6")
+	require.NoError(t, err)
+
+	assert.Equal(t, exp, res)
+}
+
+func TestStarredQueriesService_Create(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, mediaTypeJSON, r.Header.Get("Content-Type"))
+
+		var req map[string]any
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]any{
+			"name":  "Errors",
+			"kind":  "apl",
+			"query": "['logs']",
+		}, req)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err = fmt.Fprint(w, `{
+			"id": "NBYj9rO5p4F5CtYEy6",
+			"name": "Errors",
+			"kind": "apl",
+			"query": "['logs']",
+			"createdAt": "2023-03-01T12:00:00Z"
+		}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/starred", hf)
+
+	res, err := client.StarredQueries.Create(context.Background(), StarredQueryCreateRequest{
+		Name:  "Errors",
+		Kind:  querylegacy.APL,
+		Query: "['logs']",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "NBYj9rO5p4F5CtYEy6", res.ID)
+	assert.Equal(t, querylegacy.APL, res.Kind)
+}
+
+func TestStarredQueriesService_Delete(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	client := setup(t, "/v1/starred/NBYj9rO5p4F5CtYEy6", hf)
+
+	err := client.StarredQueries.Delete(context.Background(), "NBYj9rO5p4F5CtYEy6")
+	require.NoError(t, err)
+}
+
+func TestStarredQueriesService_Share(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, mediaTypeJSON, r.Header.Get("Content-Type"))
+
+		var req map[string]any
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]any{
+			"teamId": "test",
+		}, req)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err = fmt.Fprint(w, `{
+			"id": "NBYj9rO5p4F5CtYEy6",
+			"name": "Errors",
+			"kind": "apl",
+			"query": "['logs']",
+			"createdAt": "2023-03-01T12:00:00Z"
+		}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/starred/NBYj9rO5p4F5CtYEy6/share", hf)
+
+	res, err := client.StarredQueries.Share(context.Background(), "NBYj9rO5p4F5CtYEy6", "test")
+	require.NoError(t, err)
+
+	assert.Equal(t, "NBYj9rO5p4F5CtYEy6", res.ID)
+}
@@ -0,0 +1,179 @@
+package axiom
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/axiomhq/axiom-go/axiom/query"
+)
+
+const (
+	// tailDefaultPollInterval is the default interval at which
+	// [DatasetsService.Tail] polls for new events.
+	tailDefaultPollInterval = time.Second
+	// tailBatchSize is the maximum amount of events retrieved by a single poll
+	// of [DatasetsService.Tail]. If it is reached, the next poll happens right
+	// away.
+	tailBatchSize = 1000
+)
+
+// TailOptions specifies the optional parameters to [DatasetsService.Tail].
+type TailOptions struct {
+	// StartTime is the time of the oldest event to stream. Defaults to the
+	// time [DatasetsService.Tail] is called.
+	StartTime time.Time
+	// PollInterval is the interval at which the dataset is queried for new
+	// events. Defaults to one second.
+	PollInterval time.Duration
+	// OnError is called with the error of a failed poll, if set. Failed polls
+	// are retried at the next interval.
+	OnError func(err error)
+}
+
+// Tail streams the events of the dataset identified by the given id, starting
+// at the configured start time, in the order of their timestamps, like
+// "tail -f". The dataset is queried for new events at the configured poll
+// interval. The returned channel is closed once the context is done.
+//
+// The first poll happens before Tail returns, so an invalid dataset fails
+// right away. Events which are ingested with a timestamp older than the newest
+// event already streamed are not picked up.
+func (s *DatasetsService) Tail(ctx context.Context, id string, opts TailOptions) (<-chan Event, error) {
+	if opts.StartTime.IsZero() {
+		opts.StartTime = time.Now()
+	}
+	if opts.PollInterval <= 0 {
+		opts.PollInterval = tailDefaultPollInterval
+	}
+
+	t := &tailer{
+		datasets: s,
+		apl:      fmt.Sprintf("%s | sort by _time asc | take %d", datasetReference(id), tailBatchSize),
+		last:     opts.StartTime,
+		seen:     make(map[string]bool),
+	}
+
+	spanCtx, span := s.client.trace(ctx, "Datasets.Tail", trace.WithAttributes(
+		attribute.String("axiom.dataset_id", id),
+		attribute.String("axiom.param.start_time", opts.StartTime.String()),
+		attribute.String("axiom.param.poll_interval", opts.PollInterval.String()),
+	))
+	events, full, err := t.poll(spanCtx)
+	if err != nil {
+		err = spanError(span, err)
+		span.End()
+		return nil, err
+	}
+	span.End()
+
+	ch := make(chan Event)
+	go func() {
+		defer close(ch)
+
+		ticker := time.NewTicker(opts.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			for _, event := range events {
+				select {
+				case ch <- event:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			// Poll again right away, if more events might be available.
+			if !full {
+				select {
+				case <-ticker.C:
+				case <-ctx.Done():
+					return
+				}
+			}
+
+			if events, full, err = t.poll(ctx); ctx.Err() != nil {
+				return
+			} else if err != nil && opts.OnError != nil {
+				opts.OnError(err)
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// tailer keeps track of the events streamed by [DatasetsService.Tail].
+type tailer struct {
+	datasets *DatasetsService
+	apl      string
+
+	// last is the timestamp of the newest event streamed and seen holds the
+	// row IDs of the events streamed with that timestamp, as the next poll
+	// includes them.
+	last time.Time
+	seen map[string]bool
+}
+
+// poll queries the events that have not been streamed, yet. It reports if the
+// batch size was reached.
+//
+// If more events than the batch size share the timestamp of the newest event
+// streamed, a batch may consist of events that have all been streamed before.
+// In that case, poll pages past them using the cursor of the batch instead of
+// querying the same batch over and over again.
+func (t *tailer) poll(ctx context.Context) ([]Event, bool, error) {
+	now := time.Now()
+	if !t.last.Before(now) {
+		return nil, false, nil
+	}
+
+	var cursor string
+	for {
+		res, err := t.datasets.Query(ctx, t.apl,
+			query.SetStartTime(t.last),
+			query.SetEndTime(now),
+			query.SetCursor(cursor, false),
+		)
+		if err != nil {
+			return nil, false, err
+		}
+
+		events := t.collect(res.Matches)
+		full := len(res.Matches) >= tailBatchSize
+
+		// Wait for the next interval if the cursor doesn't advance, so a
+		// server that ignores it doesn't cause a tight loop.
+		if len(events) > 0 || !full || res.Cursor == "" || res.Cursor == cursor {
+			return events, full && len(events) > 0, nil
+		}
+		cursor = res.Cursor
+	}
+}
+
+// collect returns the given matches as events, skipping the ones that have
+// been streamed before.
+func (t *tailer) collect(matches []query.Entry) []Event {
+	events := make([]Event, 0, len(matches))
+	for _, m := range matches {
+		if m.Time.Before(t.last) || (m.Time.Equal(t.last) && t.seen[m.RowID]) {
+			continue
+		} else if m.Time.After(t.last) {
+			t.last, t.seen = m.Time, make(map[string]bool)
+		}
+		t.seen[m.RowID] = true
+
+		event := make(Event, len(m.Data)+1)
+		for k, v := range m.Data {
+			event[k] = v
+		}
+		if _, ok := event["_time"]; !ok {
+			event["_time"] = m.Time
+		}
+		events = append(events, event)
+	}
+	return events
+}
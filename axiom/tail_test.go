@@ -0,0 +1,159 @@
+package axiom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDatasetsService_Tail(t *testing.T) {
+	start := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+
+	var (
+		pollsMtx sync.Mutex
+		polls    []aplQueryRequest
+	)
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		var req aplQueryRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+
+		pollsMtx.Lock()
+		polls = append(polls, req)
+		n := len(polls)
+		pollsMtx.Unlock()
+
+		// The first poll returns two events, the following ones return the
+		// newest event again, which must not be streamed twice, along with a
+		// new one.
+		entries := `
+			{ "_time": "%[1]s", "_sysTime": "%[1]s", "_rowId": "1", "data": { "msg": "a" } },
+			{ "_time": "%[2]s", "_sysTime": "%[2]s", "_rowId": "2", "data": { "msg": "b" } }`
+		if n > 1 {
+			entries = `
+				{ "_time": "%[2]s", "_sysTime": "%[2]s", "_rowId": "2", "data": { "msg": "b" } },
+				{ "_time": "%[3]s", "_sysTime": "%[3]s", "_rowId": "3", "data": { "msg": "c" } }`
+		}
+		entries = fmt.Sprintf(entries,
+			start.Format(time.RFC3339Nano),
+			start.Add(time.Second).Format(time.RFC3339Nano),
+			start.Add(2*time.Second).Format(time.RFC3339Nano),
+		)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, _ = fmt.Fprintf(w, `{ "matches": [%s] }`, entries)
+	}
+
+	client := setup(t, "/v1/datasets/_apl", hf)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := client.Datasets.Tail(ctx, "test", TailOptions{
+		StartTime:    start,
+		PollInterval: time.Millisecond * 10,
+	})
+	require.NoError(t, err)
+
+	var msgs []any
+	for len(msgs) < 3 {
+		event := <-events
+		msgs = append(msgs, event["msg"])
+		assert.NotNil(t, event["_time"])
+	}
+	assert.Equal(t, []any{"a", "b", "c"}, msgs)
+
+	// The channel is closed once the context is done.
+	cancel()
+	for ok := true; ok; {
+		_, ok = <-events
+	}
+
+	pollsMtx.Lock()
+	defer pollsMtx.Unlock()
+
+	require.GreaterOrEqual(t, len(polls), 2)
+	assert.Equal(t, "['test'] | sort by _time asc | take 1000", polls[0].APL)
+	assert.True(t, start.Equal(polls[0].StartTime))
+	assert.True(t, start.Add(time.Second).Equal(polls[1].StartTime))
+}
+
+func TestDatasetsService_Tail_SameTimestamp(t *testing.T) {
+	start := time.Now().Add(-time.Minute).UTC().Truncate(time.Second)
+
+	var (
+		cursorsMtx sync.Mutex
+		cursors    []string
+	)
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		var req aplQueryRequest
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+
+		cursorsMtx.Lock()
+		cursors = append(cursors, req.Cursor)
+		cursorsMtx.Unlock()
+
+		// A full batch of events sharing the same timestamp is returned,
+		// unless the query pages past it.
+		var entries []string
+		if req.Cursor == "" {
+			for i := 0; i < tailBatchSize; i++ {
+				entries = append(entries, fmt.Sprintf(`{ "_time": "%[1]s", "_sysTime": "%[1]s", "_rowId": "%[2]d", "data": { "msg": "a" } }`,
+					start.Format(time.RFC3339Nano), i))
+			}
+		} else {
+			entries = append(entries, fmt.Sprintf(`{ "_time": "%[1]s", "_sysTime": "%[1]s", "_rowId": "new", "data": { "msg": "b" } }`,
+				start.Add(time.Second).Format(time.RFC3339Nano)))
+		}
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, _ = fmt.Fprintf(w, `{ "matches": [%s] }`, strings.Join(entries, ","))
+	}
+
+	client := setup(t, "/v1/datasets/_apl", hf)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	events, err := client.Datasets.Tail(ctx, "test", TailOptions{
+		StartTime:    start,
+		PollInterval: time.Millisecond * 10,
+	})
+	require.NoError(t, err)
+
+	for i := 0; i < tailBatchSize; i++ {
+		assert.Equal(t, "a", (<-events)["msg"])
+	}
+	assert.Equal(t, "b", (<-events)["msg"])
+
+	cancel()
+	for ok := true; ok; {
+		_, ok = <-events
+	}
+
+	cursorsMtx.Lock()
+	defer cursorsMtx.Unlock()
+
+	require.GreaterOrEqual(t, len(cursors), 3)
+	assert.Equal(t, []string{"", "", "999"}, cursors[:3])
+}
+
+func TestDatasetsService_Tail_Error(t *testing.T) {
+	hf := func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}
+
+	client := setup(t, "/v1/datasets/_apl", hf)
+
+	_, err := client.Datasets.Tail(context.Background(), "test", TailOptions{})
+	require.ErrorIs(t, err, ErrNotFound)
+}
@@ -0,0 +1,209 @@
+package axiom
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// OrganizationTeam represents a team of an organization. Teams grant their
+// members access to a set of datasets. It is not called "Team" because that
+// name is already taken by the [Team] plan.
+type OrganizationTeam struct {
+	// ID is the unique ID of the team.
+	ID string `json:"id"`
+	// Name of the team.
+	Name string `json:"name"`
+	// Members are the IDs of the users that are members of the team.
+	Members []string `json:"members"`
+	// Datasets are the IDs of the datasets the team has access to.
+	Datasets []string `json:"datasets"`
+}
+
+// TeamCreateRequest is a request used to create a team.
+type TeamCreateRequest struct {
+	// Name of the team to create.
+	Name string `json:"name"`
+	// Members are the IDs of the users to add to the team.
+	Members []string `json:"members,omitempty"`
+	// Datasets are the IDs of the datasets to grant the team access to.
+	Datasets []string `json:"datasets,omitempty"`
+}
+
+// TeamUpdateRequest is a request used to update a team.
+type TeamUpdateRequest struct {
+	// Name of the team.
+	Name string `json:"name"`
+	// Members are the IDs of the users that are members of the team.
+	Members []string `json:"members"`
+	// Datasets are the IDs of the datasets the team has access to.
+	Datasets []string `json:"datasets"`
+}
+
+type teamDatasetsRequest struct {
+	Datasets []string `json:"datasets"`
+}
+
+// TeamsService handles communication with the team related operations of the
+// Axiom API.
+//
+// Axiom API Reference: /v1/teams
+type TeamsService service
+
+// List all available teams.
+func (s *TeamsService) List(ctx context.Context) ([]*OrganizationTeam, error) {
+	ctx, span := s.client.trace(ctx, "Teams.List")
+	defer span.End()
+
+	var res []*OrganizationTeam
+	if err := s.client.Call(ctx, http.MethodGet, s.basePath, nil, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return res, nil
+}
+
+// Get a team by id.
+func (s *TeamsService) Get(ctx context.Context, id string) (*OrganizationTeam, error) {
+	ctx, span := s.client.trace(ctx, "Teams.Get", trace.WithAttributes(
+		attribute.String("axiom.team_id", id),
+	))
+	defer span.End()
+
+	path, err := url.JoinPath(s.basePath, id)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	var res OrganizationTeam
+	if err := s.client.Call(ctx, http.MethodGet, path, nil, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return &res, nil
+}
+
+// Create a team with the given properties.
+func (s *TeamsService) Create(ctx context.Context, req TeamCreateRequest) (*OrganizationTeam, error) {
+	ctx, span := s.client.trace(ctx, "Teams.Create", trace.WithAttributes(
+		attribute.String("axiom.param.name", req.Name),
+	))
+	defer span.End()
+
+	var res OrganizationTeam
+	if err := s.client.Call(ctx, http.MethodPost, s.basePath, req, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return &res, nil
+}
+
+// Update the team identified by the given id with the given properties.
+func (s *TeamsService) Update(ctx context.Context, id string, req TeamUpdateRequest) (*OrganizationTeam, error) {
+	ctx, span := s.client.trace(ctx, "Teams.Update", trace.WithAttributes(
+		attribute.String("axiom.team_id", id),
+	))
+	defer span.End()
+
+	path, err := url.JoinPath(s.basePath, id)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	var res OrganizationTeam
+	if err := s.client.Call(ctx, http.MethodPut, path, req, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return &res, nil
+}
+
+// Delete the team identified by the given id.
+func (s *TeamsService) Delete(ctx context.Context, id string) error {
+	ctx, span := s.client.trace(ctx, "Teams.Delete", trace.WithAttributes(
+		attribute.String("axiom.team_id", id),
+	))
+	defer span.End()
+
+	path, err := url.JoinPath(s.basePath, id)
+	if err != nil {
+		return spanError(span, err)
+	}
+
+	if err := s.client.Call(ctx, http.MethodDelete, path, nil, nil); err != nil {
+		return spanError(span, err)
+	}
+
+	return nil
+}
+
+// AddMember adds the user identified by the given user id to the team
+// identified by the given team id.
+func (s *TeamsService) AddMember(ctx context.Context, teamID, userID string) error {
+	ctx, span := s.client.trace(ctx, "Teams.AddMember", trace.WithAttributes(
+		attribute.String("axiom.team_id", teamID),
+		attribute.String("axiom.user_id", userID),
+	))
+	defer span.End()
+
+	path, err := url.JoinPath(s.basePath, teamID, "members", userID)
+	if err != nil {
+		return spanError(span, err)
+	}
+
+	if err := s.client.Call(ctx, http.MethodPut, path, nil, nil); err != nil {
+		return spanError(span, err)
+	}
+
+	return nil
+}
+
+// RemoveMember removes the user identified by the given user id from the team
+// identified by the given team id.
+func (s *TeamsService) RemoveMember(ctx context.Context, teamID, userID string) error {
+	ctx, span := s.client.trace(ctx, "Teams.RemoveMember", trace.WithAttributes(
+		attribute.String("axiom.team_id", teamID),
+		attribute.String("axiom.user_id", userID),
+	))
+	defer span.End()
+
+	path, err := url.JoinPath(s.basePath, teamID, "members", userID)
+	if err != nil {
+		return spanError(span, err)
+	}
+
+	if err := s.client.Call(ctx, http.MethodDelete, path, nil, nil); err != nil {
+		return spanError(span, err)
+	}
+
+	return nil
+}
+
+// SetDatasets replaces the datasets the team identified by the given id has
+// access to with the given datasets.
+func (s *TeamsService) SetDatasets(ctx context.Context, teamID string, datasetIDs []string) (*OrganizationTeam, error) {
+	ctx, span := s.client.trace(ctx, "Teams.SetDatasets", trace.WithAttributes(
+		attribute.String("axiom.team_id", teamID),
+		attribute.StringSlice("axiom.param.datasets", datasetIDs),
+	))
+	defer span.End()
+
+	path, err := url.JoinPath(s.basePath, teamID, "datasets")
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	if datasetIDs == nil {
+		datasetIDs = []string{}
+	}
+
+	var res OrganizationTeam
+	if err := s.client.Call(ctx, http.MethodPut, path, teamDatasetsRequest{Datasets: datasetIDs}, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return &res, nil
+}
@@ -0,0 +1,251 @@
+package axiom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTeamsService_List(t *testing.T) {
+	exp := []*OrganizationTeam{
+		{
+			ID:       "test",
+			Name:     "Test",
+			Members:  []string{"e9cffaad-60e7-4b04-8d27-185e1808c38c"},
+			Datasets: []string{"logs"},
+		},
+	}
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `[
+			{
+				"id": "test",
+				"name": "Test",
+				"members": ["e9cffaad-60e7-4b04-8d27-185e1808c38c"],
+				"datasets": ["logs"]
+			}
+		]`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/teams", hf)
+
+	res, err := client.Teams.List(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, exp, res)
+}
+
+func TestTeamsService_Get(t *testing.T) {
+	exp := &OrganizationTeam{
+		ID:       "test",
+		Name:     "Test",
+		Members:  []string{"e9cffaad-60e7-4b04-8d27-185e1808c38c"},
+		Datasets: []string{"logs"},
+	}
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `{
+			"id": "test",
+			"name": "Test",
+			"members": ["e9cffaad-60e7-4b04-8d27-185e1808c38c"],
+			"datasets": ["logs"]
+		}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/teams/test", hf)
+
+	res, err := client.Teams.Get(context.Background(), "test")
+	require.NoError(t, err)
+
+	assert.Equal(t, exp, res)
+}
+
+func TestTeamsService_Create(t *testing.T) {
+	exp := &OrganizationTeam{
+		ID:       "test",
+		Name:     "Test",
+		Members:  []string{},
+		Datasets: []string{"logs"},
+	}
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, mediaTypeJSON, r.Header.Get("Content-Type"))
+
+		var req map[string]any
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]any{
+			"name":     "Test",
+			"datasets": []any{"logs"},
+		}, req)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err = fmt.Fprint(w, `{
+			"id": "test",
+			"name": "Test",
+			"members": [],
+			"datasets": ["logs"]
+		}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/teams", hf)
+
+	res, err := client.Teams.Create(context.Background(), TeamCreateRequest{
+		Name:     "Test",
+		Datasets: []string{"logs"},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, exp, res)
+}
+
+func TestTeamsService_Update(t *testing.T) {
+	exp := &OrganizationTeam{
+		ID:       "test",
+		Name:     "Renamed",
+		Members:  []string{},
+		Datasets: []string{},
+	}
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, mediaTypeJSON, r.Header.Get("Content-Type"))
+
+		var req map[string]any
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]any{
+			"name":     "Renamed",
+			"members":  []any{},
+			"datasets": []any{},
+		}, req)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err = fmt.Fprint(w, `{
+			"id": "test",
+			"name": "Renamed",
+			"members": [],
+			"datasets": []
+		}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/teams/test", hf)
+
+	res, err := client.Teams.Update(context.Background(), "test", TeamUpdateRequest{
+		Name:     "Renamed",
+		Members:  []string{},
+		Datasets: []string{},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, exp, res)
+}
+
+func TestTeamsService_Delete(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	client := setup(t, "/v1/teams/test", hf)
+
+	err := client.Teams.Delete(context.Background(), "test")
+	require.NoError(t, err)
+}
+
+func TestTeamsService_AddMember(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	client := setup(t, "/v1/teams/test/members/e9cffaad-60e7-4b04-8d27-185e1808c38c", hf)
+
+	err := client.Teams.AddMember(context.Background(), "test", "e9cffaad-60e7-4b04-8d27-185e1808c38c")
+	require.NoError(t, err)
+}
+
+func TestTeamsService_RemoveMember(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	client := setup(t, "/v1/teams/test/members/e9cffaad-60e7-4b04-8d27-185e1808c38c", hf)
+
+	err := client.Teams.RemoveMember(context.Background(), "test", "e9cffaad-60e7-4b04-8d27-185e1808c38c")
+	require.NoError(t, err)
+}
+
+func TestTeamsService_SetDatasets(t *testing.T) {
+	tests := []struct {
+		name       string
+		datasetIDs []string
+		exp        []any
+	}{
+		{
+			name:       "datasets",
+			datasetIDs: []string{"logs", "metrics"},
+			exp:        []any{"logs", "metrics"},
+		},
+		{
+			name:       "nil clears datasets",
+			datasetIDs: nil,
+			exp:        []any{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hf := func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPut, r.Method)
+				assert.Equal(t, mediaTypeJSON, r.Header.Get("Content-Type"))
+
+				var req map[string]any
+				err := json.NewDecoder(r.Body).Decode(&req)
+				require.NoError(t, err)
+
+				assert.Equal(t, map[string]any{
+					"datasets": tt.exp,
+				}, req)
+
+				w.Header().Set("Content-Type", mediaTypeJSON)
+				err = json.NewEncoder(w).Encode(map[string]any{
+					"id":       "test",
+					"name":     "Test",
+					"members":  []string{},
+					"datasets": req["datasets"],
+				})
+				assert.NoError(t, err)
+			}
+
+			client := setup(t, "/v1/teams/test/datasets", hf)
+
+			res, err := client.Teams.SetDatasets(context.Background(), "test", tt.datasetIDs)
+			require.NoError(t, err)
+
+			assert.Equal(t, "test", res.ID)
+			assert.Len(t, res.Datasets, len(tt.exp))
+		})
+	}
+}
@@ -0,0 +1,68 @@
+package axiom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/axiomhq/axiom-go/internal/config"
+)
+
+// ErrTokenProvider is returned when the token provider configured using
+// [SetTokenProvider] fails to provide a valid token. It wraps the underlying
+// error.
+var ErrTokenProvider = errors.New("token provider failed")
+
+// tokenProvider caches the tokens returned by a user supplied function. See
+// [SetTokenProvider].
+type tokenProvider struct {
+	provide func(ctx context.Context) (string, error)
+
+	mtx    sync.Mutex
+	token  string
+	expiry time.Time
+}
+
+// get returns the cached token, if it was fetched less than ttl ago. Otherwise,
+// a new token is fetched and cached.
+func (p *tokenProvider) get(ctx context.Context, ttl time.Duration) (string, error) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	now := time.Now()
+	if p.token != "" && now.Before(p.expiry) {
+		return p.token, nil
+	}
+
+	token, err := p.provide(ctx)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrTokenProvider, err)
+	} else if !config.IsValidToken(token) {
+		return "", fmt.Errorf("%w: %w", ErrTokenProvider, config.ErrInvalidToken)
+	}
+	p.token, p.expiry = token, now.Add(ttl)
+
+	return token, nil
+}
+
+// invalidate drops the cached token, if it is the given one, so the next
+// request fetches a new one.
+func (p *tokenProvider) invalidate(token string) {
+	p.mtx.Lock()
+	defer p.mtx.Unlock()
+
+	if p.token == token {
+		p.token = ""
+	}
+}
+
+// requestToken returns the token to authenticate a request with. It is
+// retrieved from the token provider, if set.
+func (c *Client) requestToken(ctx context.Context) (string, error) {
+	if c.tokenProvider == nil {
+		return c.token(), nil
+	}
+	return c.tokenProvider.get(ctx, c.tokenProviderTTL)
+}
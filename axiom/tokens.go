@@ -0,0 +1,378 @@
+package axiom
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/axiomhq/axiom-go/internal/config"
+)
+
+//go:generate go run golang.org/x/tools/cmd/stringer -type=Capability,TokenKind -linecomment -output=tokens_string.go
+
+// ErrMissingCapability is returned by [Client.CheckCapability] when the API
+// token used by the [Client] lacks a capability.
+var ErrMissingCapability = errors.New("missing capability")
+
+// ErrInvalidAPIToken is raised when an [APITokenCreateRequest] is not valid and
+// thus not sent to the server.
+var ErrInvalidAPIToken = errors.New("invalid api token")
+
+// TokenKind represents the kind of an Axiom token.
+type TokenKind uint8
+
+// All available [TokenKind]s.
+const (
+	TokenKindAPI      TokenKind = iota + 1 // api
+	TokenKindPersonal                      // personal
+)
+
+// TokenType returns the kind of the given token. It returns false, if the token
+// is not a valid Axiom token.
+func TokenType(token string) (TokenKind, bool) {
+	switch {
+	case config.IsAPIToken(token):
+		return TokenKindAPI, true
+	case config.IsPersonalToken(token):
+		return TokenKindPersonal, true
+	}
+	return 0, false
+}
+
+// Capability represents a capability granted to an [APIToken].
+type Capability uint8
+
+// All available [APIToken] capabilities.
+const (
+	emptyCapability Capability = iota //
+
+	CapabilityIngest // ingest
+	CapabilityQuery  // query
+	CapabilityCreate // create
+	CapabilityUpdate // update
+	CapabilityDelete // delete
+)
+
+func capabilityFromString(s string) (c Capability, err error) {
+	switch s {
+	case emptyCapability.String():
+		c = emptyCapability
+	case CapabilityIngest.String():
+		c = CapabilityIngest
+	case CapabilityQuery.String():
+		c = CapabilityQuery
+	case CapabilityCreate.String():
+		c = CapabilityCreate
+	case CapabilityUpdate.String():
+		c = CapabilityUpdate
+	case CapabilityDelete.String():
+		c = CapabilityDelete
+	default:
+		err = fmt.Errorf("unknown capability %q", s)
+	}
+
+	return c, err
+}
+
+// MarshalJSON implements [json.Marshaler]. It is in place to marshal the
+// capability to its string representation because that's what the server
+// expects.
+func (c Capability) MarshalJSON() ([]byte, error) {
+	return json.Marshal(c.String())
+}
+
+// UnmarshalJSON implements [json.Unmarshaler]. It is in place to unmarshal the
+// capability from the string representation the server returns.
+func (c *Capability) UnmarshalJSON(b []byte) (err error) {
+	var s string
+	if err = json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+
+	*c, err = capabilityFromString(s)
+
+	return err
+}
+
+// APIToken represents an API token.
+type APIToken struct {
+	// ID is the unique ID of the token.
+	ID string `json:"id"`
+	// Name of the token.
+	Name string `json:"name"`
+	// Description of the token.
+	Description string `json:"description,omitempty"`
+	// ExpiresAt is the time the token expires at. It is nil for tokens that
+	// never expire.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	// DatasetCapabilities are the capabilities granted to the token, per
+	// dataset.
+	DatasetCapabilities map[string][]Capability `json:"datasetCapabilities,omitempty"`
+	// OrgCapabilities are the capabilities granted to the token on the
+	// organization.
+	OrgCapabilities []Capability `json:"orgCapabilities,omitempty"`
+}
+
+// CreatedAPIToken is an [APIToken] that was just created. It is the only
+// time the raw token is returned by the server.
+type CreatedAPIToken struct {
+	APIToken
+
+	// Token is the raw token to authenticate with.
+	Token string `json:"token"`
+}
+
+// APITokenCreateRequest is a request used to create an API token. At least
+// one capability must be granted, either on a dataset or on the organization.
+type APITokenCreateRequest struct {
+	// Name of the token to create.
+	Name string `json:"name"`
+	// Description of the token to create.
+	Description string `json:"description,omitempty"`
+	// ExpiresAt is the time the token expires at. If not set, the token never
+	// expires.
+	ExpiresAt *time.Time `json:"expiresAt,omitempty"`
+	// DatasetCapabilities are the capabilities to grant to the token, per
+	// dataset.
+	DatasetCapabilities map[string][]Capability `json:"datasetCapabilities,omitempty"`
+	// OrgCapabilities are the capabilities to grant to the token on the
+	// organization.
+	OrgCapabilities []Capability `json:"orgCapabilities,omitempty"`
+}
+
+func (r APITokenCreateRequest) validate() error {
+	if r.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidAPIToken)
+	}
+
+	var granted int
+	for dataset, capabilities := range r.DatasetCapabilities {
+		if dataset == "" {
+			return fmt.Errorf("%w: dataset capabilities require a dataset name", ErrInvalidAPIToken)
+		} else if err := validateCapabilities(capabilities); err != nil {
+			return err
+		}
+		granted += len(capabilities)
+	}
+	if err := validateCapabilities(r.OrgCapabilities); err != nil {
+		return err
+	}
+	granted += len(r.OrgCapabilities)
+
+	if granted == 0 {
+		return fmt.Errorf("%w: at least one capability must be granted", ErrInvalidAPIToken)
+	}
+
+	return nil
+}
+
+func validateCapabilities(capabilities []Capability) error {
+	for _, c := range capabilities {
+		if c == emptyCapability || c > CapabilityDelete {
+			return fmt.Errorf("%w: unknown capability %s", ErrInvalidAPIToken, c)
+		}
+	}
+	return nil
+}
+
+// TokensService handles communication with the token related operations of
+// the Axiom API.
+type TokensService struct {
+	// API handles the operations on API tokens.
+	API *APITokensService
+}
+
+// APITokensService handles communication with the API token related
+// operations of the Axiom API.
+//
+// Axiom API Reference: /v2/tokens
+type APITokensService service
+
+// List all available API tokens.
+func (s *APITokensService) List(ctx context.Context) ([]*APIToken, error) {
+	ctx, span := s.client.trace(ctx, "Tokens.API.List")
+	defer span.End()
+
+	var res []*APIToken
+	if err := s.client.Call(ctx, http.MethodGet, s.basePath, nil, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return res, nil
+}
+
+// Current retrieves the API token the client is authenticated with. It can
+// only be used with an API token.
+func (s *APITokensService) Current(ctx context.Context) (*APIToken, error) {
+	ctx, span := s.client.trace(ctx, "Tokens.API.Current")
+	defer span.End()
+
+	path, err := url.JoinPath(s.basePath, "self")
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	var res APIToken
+	if err := s.client.Call(ctx, http.MethodGet, path, nil, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return &res, nil
+}
+
+// Get an API token by id.
+func (s *APITokensService) Get(ctx context.Context, id string) (*APIToken, error) {
+	ctx, span := s.client.trace(ctx, "Tokens.API.Get", trace.WithAttributes(
+		attribute.String("axiom.token_id", id),
+	))
+	defer span.End()
+
+	path, err := url.JoinPath(s.basePath, id)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	var res APIToken
+	if err := s.client.Call(ctx, http.MethodGet, path, nil, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return &res, nil
+}
+
+// Create an API token with the given properties. The request is validated
+// before sending it to the server and [ErrInvalidAPIToken] is returned if it
+// is not valid.
+func (s *APITokensService) Create(ctx context.Context, req APITokenCreateRequest) (*CreatedAPIToken, error) {
+	ctx, span := s.client.trace(ctx, "Tokens.API.Create", trace.WithAttributes(
+		attribute.String("axiom.param.name", req.Name),
+	))
+	defer span.End()
+
+	if err := req.validate(); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	var res CreatedAPIToken
+	if err := s.client.Call(ctx, http.MethodPost, s.basePath, req, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return &res, nil
+}
+
+// Delete the API token identified by the given id.
+func (s *APITokensService) Delete(ctx context.Context, id string) error {
+	ctx, span := s.client.trace(ctx, "Tokens.API.Delete", trace.WithAttributes(
+		attribute.String("axiom.token_id", id),
+	))
+	defer span.End()
+
+	path, err := url.JoinPath(s.basePath, id)
+	if err != nil {
+		return spanError(span, err)
+	}
+
+	if err := s.client.Call(ctx, http.MethodDelete, path, nil, nil); err != nil {
+		return spanError(span, err)
+	}
+
+	return nil
+}
+
+// Rotate the API token identified by the given id. A new token with the same
+// name, description and capabilities is created and returned alongside its raw
+// token. The expiry of the old token is not carried over. If revoke is true,
+// the old token is deleted once the new one has been created. Otherwise, it
+// stays valid until deleted explicitly, e.g. after all consumers have been
+// switched to the new token.
+func (s *APITokensService) Rotate(ctx context.Context, id string, revoke bool) (*CreatedAPIToken, error) {
+	ctx, span := s.client.trace(ctx, "Tokens.API.Rotate", trace.WithAttributes(
+		attribute.String("axiom.token_id", id),
+		attribute.Bool("axiom.param.revoke", revoke),
+	))
+	defer span.End()
+
+	old, err := s.Get(ctx, id)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	res, err := s.Create(ctx, APITokenCreateRequest{
+		Name:                old.Name,
+		Description:         old.Description,
+		DatasetCapabilities: old.DatasetCapabilities,
+		OrgCapabilities:     old.OrgCapabilities,
+	})
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	if revoke {
+		if err = s.Delete(ctx, id); err != nil {
+			return res, spanError(span, fmt.Errorf("revoke old token: %w", err))
+		}
+	}
+
+	return res, nil
+}
+
+// CheckCapability returns an [ErrMissingCapability] if the API token the
+// [Client] is authenticated with lacks the given capability on the given
+// dataset. This allows for failing early with an actionable error instead of
+// an unspecific 403 (Forbidden) response. The capabilities of the token are
+// retrieved using [APITokensService.Current] and cached until the token changes.
+//
+// Personal tokens act on behalf of a user and are not restricted by
+// capabilities. For them, no check is performed and nil is returned.
+func (c *Client) CheckCapability(ctx context.Context, dataset string, capability Capability) error {
+	token, err := c.requestToken(ctx)
+	if err != nil {
+		return err
+	} else if !config.IsAPIToken(token) {
+		return nil
+	}
+
+	info, err := c.currentTokenInfo(ctx, token)
+	if err != nil {
+		return err
+	}
+
+	for _, granted := range info.DatasetCapabilities[dataset] {
+		if granted == capability {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%w: token %q lacks %s capability on dataset %q",
+		ErrMissingCapability, info.Name, capability, dataset)
+}
+
+// currentTokenInfo returns the cached API token for the given raw token or
+// retrieves it using [APITokensService.Current], replacing the cached one.
+func (c *Client) currentTokenInfo(ctx context.Context, token string) (*APIToken, error) {
+	key := sha256.Sum256([]byte(token))
+
+	c.tokenInfoMtx.Lock()
+	defer c.tokenInfoMtx.Unlock()
+
+	if c.tokenInfo != nil && c.tokenInfoKey == key {
+		return c.tokenInfo, nil
+	}
+
+	info, err := c.Tokens.API.Current(ctx)
+	if err != nil {
+		return nil, err
+	}
+	c.tokenInfoKey, c.tokenInfo = key, info
+
+	return info, nil
+}
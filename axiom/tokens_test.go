@@ -0,0 +1,483 @@
+package axiom
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenType(t *testing.T) {
+	kind, ok := TokenType(apiToken)
+	assert.True(t, ok)
+	assert.Equal(t, TokenKindAPI, kind)
+
+	kind, ok = TokenType(personalToken)
+	assert.True(t, ok)
+	assert.Equal(t, TokenKindPersonal, kind)
+
+	_, ok = TokenType("abc")
+	assert.False(t, ok)
+}
+
+func TestAPITokensService_List(t *testing.T) {
+	exp := []*APIToken{
+		{
+			ID:          "test",
+			Name:        "Test",
+			Description: "A test token",
+			DatasetCapabilities: map[string][]Capability{
+				"logs": {CapabilityIngest, CapabilityQuery},
+			},
+			OrgCapabilities: []Capability{CapabilityCreate},
+		},
+	}
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `[
+			{
+				"id": "test",
+				"name": "Test",
+				"description": "A test token",
+				"datasetCapabilities": {
+					"logs": ["ingest", "query"]
+				},
+				"orgCapabilities": ["create"]
+			}
+		]`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v2/tokens", hf)
+
+	res, err := client.Tokens.API.List(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, exp, res)
+}
+
+func TestAPITokensService_Get(t *testing.T) {
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+	exp := &APIToken{
+		ID:        "test",
+		Name:      "Test",
+		ExpiresAt: &expiresAt,
+		DatasetCapabilities: map[string][]Capability{
+			"logs": {CapabilityIngest},
+		},
+	}
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `{
+			"id": "test",
+			"name": "Test",
+			"expiresAt": "2030-01-01T00:00:00Z",
+			"datasetCapabilities": {
+				"logs": ["ingest"]
+			}
+		}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v2/tokens/test", hf)
+
+	res, err := client.Tokens.API.Get(context.Background(), "test")
+	require.NoError(t, err)
+
+	assert.Equal(t, exp, res)
+}
+
+func TestAPITokensService_Current(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `{
+			"id": "abc",
+			"name": "ingest",
+			"datasetCapabilities": {
+				"test": ["ingest"]
+			}
+		}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v2/tokens/self", hf)
+
+	err := client.Options(SetToken(apiToken))
+	require.NoError(t, err)
+
+	res, err := client.Tokens.API.Current(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, &APIToken{
+		ID:   "abc",
+		Name: "ingest",
+		DatasetCapabilities: map[string][]Capability{
+			"test": {CapabilityIngest},
+		},
+	}, res)
+}
+
+func TestClient_CheckCapability(t *testing.T) {
+	var calls int
+	hf := func(w http.ResponseWriter, _ *http.Request) {
+		calls++
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `{
+			"id": "abc",
+			"name": "ingest",
+			"datasetCapabilities": {
+				"test": ["ingest", "query"]
+			}
+		}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v2/tokens/self", hf)
+
+	// Personal tokens are not checked.
+	err := client.CheckCapability(context.Background(), "other", CapabilityIngest)
+	require.NoError(t, err)
+	assert.Zero(t, calls)
+
+	err = client.Options(SetToken(apiToken))
+	require.NoError(t, err)
+
+	err = client.CheckCapability(context.Background(), "test", CapabilityIngest)
+	assert.NoError(t, err)
+
+	err = client.CheckCapability(context.Background(), "test", CapabilityQuery)
+	assert.NoError(t, err)
+
+	err = client.CheckCapability(context.Background(), "test", CapabilityDelete)
+	assert.ErrorIs(t, err, ErrMissingCapability)
+	assert.EqualError(t, err, `missing capability: token "ingest" lacks delete capability on dataset "test"`)
+
+	err = client.CheckCapability(context.Background(), "other", CapabilityIngest)
+	assert.ErrorIs(t, err, ErrMissingCapability)
+
+	// The capabilities are fetched only once per token.
+	assert.Equal(t, 1, calls)
+
+	// Concurrent checks share a single fetch.
+	client.tokenInfo = nil
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			assert.NoError(t, client.CheckCapability(context.Background(), "test", CapabilityIngest))
+		}()
+	}
+	wg.Wait()
+
+	assert.Equal(t, 2, calls)
+
+	// Only the current token is cached and it is not kept in plain text.
+	err = client.Options(SetToken("xaat-YYYYYYYY-YYYY-YYYY-YYYY-YYYYYYYYYYYY"))
+	require.NoError(t, err)
+
+	err = client.CheckCapability(context.Background(), "test", CapabilityIngest)
+	assert.NoError(t, err)
+	assert.Equal(t, 3, calls)
+	assert.Equal(t, sha256.Sum256([]byte("xaat-YYYYYYYY-YYYY-YYYY-YYYY-YYYYYYYYYYYY")), client.tokenInfoKey)
+}
+
+func TestAPITokensService_Create(t *testing.T) {
+	exp := &CreatedAPIToken{
+		APIToken: APIToken{
+			ID:   "test",
+			Name: "Test",
+			DatasetCapabilities: map[string][]Capability{
+				"logs": {CapabilityIngest},
+			},
+		},
+		Token: "xaat-123",
+	}
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, mediaTypeJSON, r.Header.Get("Content-Type"))
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `{
+			"id": "test",
+			"name": "Test",
+			"datasetCapabilities": {
+				"logs": ["ingest"]
+			},
+			"token": "xaat-123"
+		}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v2/tokens", hf)
+
+	res, err := client.Tokens.API.Create(context.Background(), APITokenCreateRequest{
+		Name: "Test",
+		DatasetCapabilities: map[string][]Capability{
+			"logs": {CapabilityIngest},
+		},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, exp, res)
+}
+
+func TestAPITokensService_Create_Request(t *testing.T) {
+	expiresAt := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+
+		var req map[string]any
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]any{
+			"name":        "Test",
+			"description": "Least privilege",
+			"expiresAt":   "2030-01-01T00:00:00Z",
+			"datasetCapabilities": map[string]any{
+				"logs":    []any{"ingest"},
+				"metrics": []any{"query"},
+			},
+			"orgCapabilities": []any{"create", "update", "delete"},
+		}, req)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err = fmt.Fprint(w, `{
+			"id": "test",
+			"name": "Test",
+			"token": "xaat-123"
+		}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v2/tokens", hf)
+
+	res, err := client.Tokens.API.Create(context.Background(), APITokenCreateRequest{
+		Name:        "Test",
+		Description: "Least privilege",
+		ExpiresAt:   &expiresAt,
+		DatasetCapabilities: map[string][]Capability{
+			"logs":    {CapabilityIngest},
+			"metrics": {CapabilityQuery},
+		},
+		OrgCapabilities: []Capability{CapabilityCreate, CapabilityUpdate, CapabilityDelete},
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "xaat-123", res.Token)
+}
+
+func TestAPITokensService_Create_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		req  APITokenCreateRequest
+		err  string
+	}{
+		{
+			name: "missing name",
+			req: APITokenCreateRequest{
+				OrgCapabilities: []Capability{CapabilityCreate},
+			},
+			err: "invalid api token: name is required",
+		},
+		{
+			name: "no capabilities",
+			req: APITokenCreateRequest{
+				Name: "Test",
+			},
+			err: "invalid api token: at least one capability must be granted",
+		},
+		{
+			name: "empty dataset capabilities",
+			req: APITokenCreateRequest{
+				Name: "Test",
+				DatasetCapabilities: map[string][]Capability{
+					"logs": {},
+				},
+			},
+			err: "invalid api token: at least one capability must be granted",
+		},
+		{
+			name: "missing dataset name",
+			req: APITokenCreateRequest{
+				Name: "Test",
+				DatasetCapabilities: map[string][]Capability{
+					"": {CapabilityIngest},
+				},
+			},
+			err: "invalid api token: dataset capabilities require a dataset name",
+		},
+		{
+			name: "unknown capability",
+			req: APITokenCreateRequest{
+				Name:            "Test",
+				OrgCapabilities: []Capability{CapabilityDelete + 1},
+			},
+			err: "invalid api token: unknown capability Capability(6)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hf := func(w http.ResponseWriter, _ *http.Request) {
+				t.Error("invalid token should not be sent to the server")
+				w.WriteHeader(http.StatusBadRequest)
+			}
+
+			client := setup(t, "/v2/tokens", hf)
+
+			_, err := client.Tokens.API.Create(context.Background(), tt.req)
+			require.ErrorIs(t, err, ErrInvalidAPIToken)
+			assert.EqualError(t, err, tt.err)
+		})
+	}
+}
+
+func TestAPITokensService_Delete(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	client := setup(t, "/v2/tokens/test", hf)
+
+	err := client.Tokens.API.Delete(context.Background(), "test")
+	require.NoError(t, err)
+}
+
+func TestAPITokensService_Rotate(t *testing.T) {
+	for _, revoke := range []bool{false, true} {
+		t.Run(fmt.Sprintf("revoke=%t", revoke), func(t *testing.T) {
+			var deleted bool
+			hf := func(w http.ResponseWriter, r *http.Request) {
+				switch {
+				case r.Method == http.MethodGet && r.URL.Path == "/v2/tokens/old":
+					w.Header().Set("Content-Type", mediaTypeJSON)
+					_, err := fmt.Fprint(w, `{
+						"id": "old",
+						"name": "Test",
+						"description": "A test token",
+						"datasetCapabilities": {
+							"logs": ["ingest", "query"]
+						},
+						"orgCapabilities": ["update"]
+					}`)
+					assert.NoError(t, err)
+				case r.Method == http.MethodPost && r.URL.Path == "/v2/tokens":
+					var req map[string]any
+					err := json.NewDecoder(r.Body).Decode(&req)
+					require.NoError(t, err)
+
+					assert.Equal(t, map[string]any{
+						"name":        "Test",
+						"description": "A test token",
+						"datasetCapabilities": map[string]any{
+							"logs": []any{"ingest", "query"},
+						},
+						"orgCapabilities": []any{"update"},
+					}, req)
+
+					w.Header().Set("Content-Type", mediaTypeJSON)
+					_, err = fmt.Fprint(w, `{
+						"id": "new",
+						"name": "Test",
+						"description": "A test token",
+						"datasetCapabilities": {
+							"logs": ["ingest", "query"]
+						},
+						"orgCapabilities": ["update"],
+						"token": "xaat-new"
+					}`)
+					assert.NoError(t, err)
+				case r.Method == http.MethodDelete && r.URL.Path == "/v2/tokens/old":
+					deleted = true
+					w.WriteHeader(http.StatusNoContent)
+				default:
+					t.Errorf("unexpected request: %s %s", r.Method, r.URL.Path)
+					w.WriteHeader(http.StatusNotFound)
+				}
+			}
+
+			client := setup(t, "/", hf)
+
+			res, err := client.Tokens.API.Rotate(context.Background(), "old", revoke)
+			require.NoError(t, err)
+
+			assert.Equal(t, "new", res.ID)
+			assert.Equal(t, "xaat-new", res.Token)
+			assert.Equal(t, map[string][]Capability{
+				"logs": {CapabilityIngest, CapabilityQuery},
+			}, res.DatasetCapabilities)
+			assert.Equal(t, []Capability{CapabilityUpdate}, res.OrgCapabilities)
+			assert.Equal(t, revoke, deleted)
+		})
+	}
+}
+
+func TestCapability_Marshal(t *testing.T) {
+	exp := `{
+		"capability": "ingest"
+	}`
+
+	b, err := json.Marshal(struct {
+		Capability Capability `json:"capability"`
+	}{
+		Capability: CapabilityIngest,
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, b)
+
+	assert.JSONEq(t, exp, string(b))
+}
+
+func TestCapability_Unmarshal(t *testing.T) {
+	var act struct {
+		Capability Capability `json:"capability"`
+	}
+	err := json.Unmarshal([]byte(`{ "capability": "ingest" }`), &act)
+	require.NoError(t, err)
+
+	assert.Equal(t, CapabilityIngest, act.Capability)
+}
+
+func TestCapability_String(t *testing.T) {
+	// Check outer bounds.
+	assert.Empty(t, Capability(0).String())
+	assert.Empty(t, emptyCapability.String())
+	assert.Equal(t, emptyCapability, Capability(0))
+	assert.Contains(t, (CapabilityDelete + 1).String(), "Capability(")
+
+	for c := CapabilityIngest; c <= CapabilityDelete; c++ {
+		s := c.String()
+		assert.NotEmpty(t, s)
+		assert.NotContains(t, s, "Capability(")
+	}
+}
+
+func TestCapabilityFromString(t *testing.T) {
+	for c := CapabilityIngest; c <= CapabilityDelete; c++ {
+		parsed, err := capabilityFromString(c.String())
+		assert.NoError(t, err)
+		assert.Equal(t, c, parsed)
+	}
+}
@@ -4,6 +4,10 @@ import (
 	"context"
 	"encoding/json"
 	"net/http"
+	"net/url"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 //go:generate go run golang.org/x/tools/cmd/stringer -type=UserRole -linecomment -output=users_string.go
@@ -67,6 +71,33 @@ type User struct {
 	Name string `json:"name"`
 	// Emails are the email addresses of the user.
 	Emails []string `json:"emails"`
+	// Role of the user in the organization the request was made for.
+	Role UserRole `json:"role"`
+	// Organizations the user is a member of. Only populated for the
+	// authenticated user returned by [UsersService.Current].
+	Organizations []*UserOrganization `json:"orgs,omitempty"`
+}
+
+// UserOrganization is an organization an [User] is a member of.
+type UserOrganization struct {
+	// ID is the unique ID of the organization.
+	ID string `json:"id"`
+	// Name of the organization.
+	Name string `json:"name"`
+	// Role of the user in the organization.
+	Role UserRole `json:"role"`
+}
+
+// UserInviteRequest is a request used to invite a user to the organization.
+type UserInviteRequest struct {
+	// Email address of the user to invite.
+	Email string `json:"email"`
+	// Role to assign to the invited user.
+	Role UserRole `json:"role"`
+}
+
+type userRoleUpdateRequest struct {
+	Role UserRole `json:"role"`
 }
 
 // UsersService handles communication with the user related operations of the
@@ -75,7 +106,8 @@ type User struct {
 // Axiom API Reference: /v1/users
 type UsersService service
 
-// Current retrieves the authenticated user.
+// Current retrieves the authenticated user, including its role and the
+// organizations it is a member of.
 func (s *UsersService) Current(ctx context.Context) (*User, error) {
 	ctx, span := s.client.trace(ctx, "Users.Current")
 	defer span.End()
@@ -87,3 +119,87 @@ func (s *UsersService) Current(ctx context.Context) (*User, error) {
 
 	return &res, nil
 }
+
+// List all users of the organization.
+func (s *UsersService) List(ctx context.Context) ([]*User, error) {
+	ctx, span := s.client.trace(ctx, "Users.List")
+	defer span.End()
+
+	var res []*User
+	if err := s.client.Call(ctx, http.MethodGet, s.basePath, nil, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return res, nil
+}
+
+// ListPager returns a [Pager] over all users of the organization.
+func (s *UsersService) ListPager() *Pager[*User] {
+	return newPager(s.basePath, func(ctx context.Context, path string) ([]*User, string, error) {
+		ctx, span := s.client.trace(ctx, "Users.ListPager")
+		defer span.End()
+
+		var res []*User
+		next, err := s.client.getPage(ctx, path, &res)
+		if err != nil {
+			return nil, "", spanError(span, err)
+		}
+
+		return res, next, nil
+	})
+}
+
+// Invite a user to the organization and assign the given role.
+func (s *UsersService) Invite(ctx context.Context, req UserInviteRequest) (*User, error) {
+	ctx, span := s.client.trace(ctx, "Users.Invite", trace.WithAttributes(
+		attribute.String("axiom.param.role", req.Role.String()),
+	))
+	defer span.End()
+
+	var res User
+	if err := s.client.Call(ctx, http.MethodPost, s.basePath, req, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return &res, nil
+}
+
+// Update the role of the user identified by the given id.
+func (s *UsersService) Update(ctx context.Context, id string, role UserRole) (*User, error) {
+	ctx, span := s.client.trace(ctx, "Users.Update", trace.WithAttributes(
+		attribute.String("axiom.user_id", id),
+		attribute.String("axiom.param.role", role.String()),
+	))
+	defer span.End()
+
+	path, err := url.JoinPath(s.basePath, id, "role")
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	var res User
+	if err := s.client.Call(ctx, http.MethodPut, path, userRoleUpdateRequest{Role: role}, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return &res, nil
+}
+
+// Remove the user identified by the given id from the organization.
+func (s *UsersService) Remove(ctx context.Context, id string) error {
+	ctx, span := s.client.trace(ctx, "Users.Remove", trace.WithAttributes(
+		attribute.String("axiom.user_id", id),
+	))
+	defer span.End()
+
+	path, err := url.JoinPath(s.basePath, id)
+	if err != nil {
+		return spanError(span, err)
+	}
+
+	if err := s.client.Call(ctx, http.MethodDelete, path, nil, nil); err != nil {
+		return spanError(span, err)
+	}
+
+	return nil
+}
@@ -22,4 +22,19 @@ func (s *UsersTestSuite) Test() {
 	user, err := s.client.Users.Current(s.suiteCtx)
 	s.Require().NoError(err)
 	s.Require().NotNil(user)
+	s.NotEmpty(user.Organizations)
+
+	// The current user must be part of the organizations users.
+	users, err := s.client.Users.List(s.suiteCtx)
+	s.Require().NoError(err)
+	s.Require().NotEmpty(users)
+
+	var found bool
+	for _, u := range users {
+		if u.ID == user.ID {
+			found = true
+			s.Equal(user.Role, u.Role)
+		}
+	}
+	s.True(found, "current user not found in users")
 }
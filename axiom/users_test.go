@@ -9,6 +9,8 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func TestUsersService_Current(t *testing.T) {
@@ -18,6 +20,19 @@ func TestUsersService_Current(t *testing.T) {
 		Emails: []string{
 			"lukas@axiom.co",
 		},
+		Role: RoleAdmin,
+		Organizations: []*UserOrganization{
+			{
+				ID:   "axiom-abcd",
+				Name: "Axiom",
+				Role: RoleAdmin,
+			},
+			{
+				ID:   "acme-efgh",
+				Name: "Acme",
+				Role: RoleReadOnly,
+			},
+		},
 	}
 
 	hf := func(w http.ResponseWriter, r *http.Request) {
@@ -29,6 +44,19 @@ func TestUsersService_Current(t *testing.T) {
 			"name": "Lukas Malkmus",
 			"emails": [
 				"lukas@axiom.co"
+			],
+			"role": "admin",
+			"orgs": [
+				{
+					"id": "axiom-abcd",
+					"name": "Axiom",
+					"role": "admin"
+				},
+				{
+					"id": "acme-efgh",
+					"name": "Acme",
+					"role": "read-only"
+				}
 			]
 		}`)
 		assert.NoError(t, err)
@@ -42,6 +70,164 @@ func TestUsersService_Current(t *testing.T) {
 	assert.Equal(t, exp, res)
 }
 
+func TestUsersService_Current_TracerProvider(t *testing.T) {
+	hf := func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `{"id":"e9cffaad-60e7-4b04-8d27-185e1808c38c"}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/user", hf)
+
+	sr := tracetest.NewSpanRecorder()
+	err := client.Options(SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(sr))))
+	require.NoError(t, err)
+
+	_, err = client.Users.Current(context.Background())
+	require.NoError(t, err)
+
+	var found bool
+	for _, span := range sr.Ended() {
+		if span.Name() == "Users.Current" {
+			found = true
+			assert.Equal(t, otelTracerName, span.InstrumentationScope().Name)
+		}
+	}
+	assert.True(t, found, "no span recorded for Users.Current")
+}
+
+func TestUsersService_List(t *testing.T) {
+	exp := []*User{
+		{
+			ID:   "e9cffaad-60e7-4b04-8d27-185e1808c38c",
+			Name: "Lukas Malkmus",
+			Emails: []string{
+				"lukas@axiom.co",
+			},
+		},
+	}
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `[
+			{
+				"id": "e9cffaad-60e7-4b04-8d27-185e1808c38c",
+				"name": "Lukas Malkmus",
+				"emails": [
+					"lukas@axiom.co"
+				]
+			}
+		]`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/users", hf)
+
+	res, err := client.Users.List(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, exp, res)
+}
+
+func TestUsersService_Invite(t *testing.T) {
+	exp := &User{
+		ID:   "e9cffaad-60e7-4b04-8d27-185e1808c38c",
+		Name: "",
+		Emails: []string{
+			"lukas@axiom.co",
+		},
+	}
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, mediaTypeJSON, r.Header.Get("Content-Type"))
+
+		var req map[string]any
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]any{
+			"email": "lukas@axiom.co",
+			"role":  "read-only",
+		}, req)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err = fmt.Fprint(w, `{
+			"id": "e9cffaad-60e7-4b04-8d27-185e1808c38c",
+			"name": "",
+			"emails": [
+				"lukas@axiom.co"
+			]
+		}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/users", hf)
+
+	res, err := client.Users.Invite(context.Background(), UserInviteRequest{
+		Email: "lukas@axiom.co",
+		Role:  RoleReadOnly,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, exp, res)
+}
+
+func TestUsersService_Update(t *testing.T) {
+	exp := &User{
+		ID:   "e9cffaad-60e7-4b04-8d27-185e1808c38c",
+		Name: "Lukas Malkmus",
+		Emails: []string{
+			"lukas@axiom.co",
+		},
+	}
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, mediaTypeJSON, r.Header.Get("Content-Type"))
+
+		var req map[string]any
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]any{
+			"role": "admin",
+		}, req)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err = fmt.Fprint(w, `{
+			"id": "e9cffaad-60e7-4b04-8d27-185e1808c38c",
+			"name": "Lukas Malkmus",
+			"emails": [
+				"lukas@axiom.co"
+			]
+		}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/users/e9cffaad-60e7-4b04-8d27-185e1808c38c/role", hf)
+
+	res, err := client.Users.Update(context.Background(), "e9cffaad-60e7-4b04-8d27-185e1808c38c", RoleAdmin)
+	require.NoError(t, err)
+
+	assert.Equal(t, exp, res)
+}
+
+func TestUsersService_Remove(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	client := setup(t, "/v1/users/e9cffaad-60e7-4b04-8d27-185e1808c38c", hf)
+
+	err := client.Users.Remove(context.Background(), "e9cffaad-60e7-4b04-8d27-185e1808c38c")
+	require.NoError(t, err)
+}
+
 func TestUserRole_Marshal(t *testing.T) {
 	exp := `{
 		"role": "read-only"
@@ -0,0 +1,80 @@
+package axiom
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"golang.org/x/mod/semver"
+)
+
+// Version represents the build information of the Axiom server.
+type Version struct {
+	// Version is the semantic version of the server.
+	Version string `json:"version"`
+	// GitCommit is the commit the server was built from.
+	GitCommit string `json:"gitCommit"`
+	// BuildDate is the time the server was built.
+	BuildDate time.Time `json:"buildDate"`
+	// GoVersion is the version of Go the server was built with.
+	GoVersion string `json:"goVersion"`
+}
+
+// AtLeast returns true if the version is greater than or equal to the given
+// minimum semantic version, e.g. "1.5.0". An error is returned if either of the
+// versions is not a valid semantic version.
+func (v Version) AtLeast(minimum string) (bool, error) {
+	current, err := canonicalVersion(v.Version)
+	if err != nil {
+		return false, err
+	}
+	wanted, err := canonicalVersion(minimum)
+	if err != nil {
+		return false, err
+	}
+	return semver.Compare(current, wanted) >= 0, nil
+}
+
+// canonicalVersion returns the given semantic version with a "v" prefix, as
+// expected by the [semver] package.
+func canonicalVersion(version string) (string, error) {
+	if !strings.HasPrefix(version, "v") {
+		version = "v" + version
+	}
+	if !semver.IsValid(version) {
+		return "", fmt.Errorf("invalid semantic version %q", strings.TrimPrefix(version, "v"))
+	}
+	return version, nil
+}
+
+// VersionService handles communication with the version related operations of
+// the Axiom API.
+//
+// Axiom API Reference: /v1/version
+type VersionService service
+
+// Get the build information of the server.
+func (s *VersionService) Get(ctx context.Context) (*Version, error) {
+	ctx, span := s.client.trace(ctx, "Version.Get")
+	defer span.End()
+
+	var res Version
+	if err := s.client.Call(ctx, http.MethodGet, s.basePath, nil, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return &res, nil
+}
+
+// AtLeast returns true if the version of the server is greater than or equal
+// to the given minimum semantic version, e.g. "1.5.0". It can be used to make
+// sure the server supports a feature before using it.
+func (s *VersionService) AtLeast(ctx context.Context, minimum string) (bool, error) {
+	version, err := s.Get(ctx)
+	if err != nil {
+		return false, err
+	}
+	return version.AtLeast(minimum)
+}
@@ -0,0 +1,87 @@
+package axiom
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVersionService_Get(t *testing.T) {
+	exp := &Version{
+		Version:   "1.5.2",
+		GitCommit: "b6e03d4",
+		BuildDate: time.Date(2023, 3, 21, 13, 38, 0, 0, time.UTC),
+		GoVersion: "go1.21.5",
+	}
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `{
+			"version": "1.5.2",
+			"gitCommit": "b6e03d4",
+			"buildDate": "2023-03-21T13:38:00Z",
+			"goVersion": "go1.21.5"
+		}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/version", hf)
+
+	res, err := client.Version.Get(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, exp, res)
+}
+
+func TestVersionService_AtLeast(t *testing.T) {
+	hf := func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `{"version":"1.5.2"}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/version", hf)
+
+	ok, err := client.Version.AtLeast(context.Background(), "1.5.0")
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	ok, err = client.Version.AtLeast(context.Background(), "1.6.0")
+	require.NoError(t, err)
+	assert.False(t, ok)
+}
+
+func TestVersion_AtLeast(t *testing.T) {
+	tests := []struct {
+		version string
+		minimum string
+		want    bool
+		err     string
+	}{
+		{version: "1.5.0", minimum: "1.5.0", want: true},
+		{version: "1.5.1", minimum: "1.5.0", want: true},
+		{version: "v2.0.0", minimum: "1.10.0", want: true},
+		{version: "1.9.0", minimum: "1.10.0", want: false},
+		{version: "1.5.0-rc.1", minimum: "1.5.0", want: false},
+		{version: "dev", minimum: "1.5.0", err: `invalid semantic version "dev"`},
+		{version: "1.5.0", minimum: "latest", err: `invalid semantic version "latest"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.version+">="+tt.minimum, func(t *testing.T) {
+			got, err := Version{Version: tt.version}.AtLeast(tt.minimum)
+			if tt.err != "" {
+				assert.EqualError(t, err, tt.err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
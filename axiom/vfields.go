@@ -0,0 +1,232 @@
+package axiom
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// problemPositionRe matches the position of a problem in an APL error message,
+// e.g. "line 1, column 12".
+var problemPositionRe = regexp.MustCompile(`(?i)line (\d+),? col(?:umn)? (\d+)`)
+
+// VirtualField represents a virtual field of a dataset. Its value is computed
+// from an APL expression at query time.
+type VirtualField struct {
+	// ID is the unique ID of the virtual field.
+	ID string `json:"id,omitempty"`
+	// Dataset is the name of the dataset the virtual field belongs to.
+	Dataset string `json:"dataset"`
+	// Name of the virtual field.
+	Name string `json:"name"`
+	// Description of the virtual field.
+	Description string `json:"description,omitempty"`
+	// Expression is the APL expression the value of the virtual field is
+	// computed with.
+	Expression string `json:"expression"`
+}
+
+// VirtualFieldProblem is a problem found by [VirtualFieldsService.Validate].
+type VirtualFieldProblem struct {
+	// Message describes the problem.
+	Message string
+	// Line of the expression the problem is located at. Zero, if the position
+	// is not known.
+	Line int
+	// Column of the expression the problem is located at. Zero, if the
+	// position is not known.
+	Column int
+}
+
+// String returns the string representation of the problem.
+func (p VirtualFieldProblem) String() string {
+	if p.Line == 0 {
+		return p.Message
+	}
+	return fmt.Sprintf("%d:%d: %s", p.Line, p.Column, p.Message)
+}
+
+type virtualFieldListOptions struct {
+	Dataset string `url:"dataset"`
+}
+
+// VirtualFieldsService handles communication with the virtual field related
+// operations of the Axiom API.
+//
+// Axiom API Reference: /v1/vfields
+type VirtualFieldsService service
+
+// List all virtual fields of the dataset identified by the given id.
+func (s *VirtualFieldsService) List(ctx context.Context, dataset string) ([]*VirtualField, error) {
+	ctx, span := s.client.trace(ctx, "VirtualFields.List", trace.WithAttributes(
+		attribute.String("axiom.dataset_id", dataset),
+	))
+	defer span.End()
+
+	path, err := AddURLOptions(s.basePath, virtualFieldListOptions{Dataset: dataset})
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	var res []*VirtualField
+	if err := s.client.Call(ctx, http.MethodGet, path, nil, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return res, nil
+}
+
+// Get a virtual field by id.
+func (s *VirtualFieldsService) Get(ctx context.Context, id string) (*VirtualField, error) {
+	ctx, span := s.client.trace(ctx, "VirtualFields.Get", trace.WithAttributes(
+		attribute.String("axiom.virtual_field_id", id),
+	))
+	defer span.End()
+
+	path, err := url.JoinPath(s.basePath, id)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	var res VirtualField
+	if err := s.client.Call(ctx, http.MethodGet, path, nil, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return &res, nil
+}
+
+// Create a virtual field with the given properties. Use
+// [VirtualFieldsService.Validate] to check the expression beforehand.
+func (s *VirtualFieldsService) Create(ctx context.Context, vf VirtualField) (*VirtualField, error) {
+	ctx, span := s.client.trace(ctx, "VirtualFields.Create", trace.WithAttributes(
+		attribute.String("axiom.param.dataset", vf.Dataset),
+		attribute.String("axiom.param.name", vf.Name),
+	))
+	defer span.End()
+
+	vf.ID = ""
+
+	var res VirtualField
+	if err := s.client.Call(ctx, http.MethodPost, s.basePath, vf, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return &res, nil
+}
+
+// Update the virtual field identified by the given id with the given
+// properties.
+func (s *VirtualFieldsService) Update(ctx context.Context, id string, vf VirtualField) (*VirtualField, error) {
+	ctx, span := s.client.trace(ctx, "VirtualFields.Update", trace.WithAttributes(
+		attribute.String("axiom.virtual_field_id", id),
+	))
+	defer span.End()
+
+	path, err := url.JoinPath(s.basePath, id)
+	if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	vf.ID = ""
+
+	var res VirtualField
+	if err := s.client.Call(ctx, http.MethodPut, path, vf, &res); err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return &res, nil
+}
+
+// Delete the virtual field identified by the given id.
+func (s *VirtualFieldsService) Delete(ctx context.Context, id string) error {
+	ctx, span := s.client.trace(ctx, "VirtualFields.Delete", trace.WithAttributes(
+		attribute.String("axiom.virtual_field_id", id),
+	))
+	defer span.End()
+
+	path, err := url.JoinPath(s.basePath, id)
+	if err != nil {
+		return spanError(span, err)
+	}
+
+	if err := s.client.Call(ctx, http.MethodDelete, path, nil, nil); err != nil {
+		return spanError(span, err)
+	}
+
+	return nil
+}
+
+// Validate checks the given virtual field for problems without persisting it.
+// The expression is compiled by the server as part of a query that doesn't
+// return any rows. An empty slice is returned if no problems were found. An
+// error is only returned if the validation itself failed.
+func (s *VirtualFieldsService) Validate(ctx context.Context, vf VirtualField) ([]VirtualFieldProblem, error) {
+	ctx, span := s.client.trace(ctx, "VirtualFields.Validate", trace.WithAttributes(
+		attribute.String("axiom.param.dataset", vf.Dataset),
+		attribute.String("axiom.param.name", vf.Name),
+	))
+	defer span.End()
+
+	problems := make([]VirtualFieldProblem, 0)
+	if vf.Dataset == "" {
+		problems = append(problems, VirtualFieldProblem{Message: "dataset is required"})
+	}
+	if vf.Name == "" {
+		problems = append(problems, VirtualFieldProblem{Message: "name is required"})
+	}
+	if strings.TrimSpace(vf.Expression) == "" {
+		problems = append(problems, VirtualFieldProblem{Message: "expression is required"})
+	}
+	if len(problems) > 0 {
+		return problems, nil
+	}
+
+	prefix := fmt.Sprintf("['%s'] | extend ['%s'] = ",
+		strings.ReplaceAll(vf.Dataset, "'", `\'`),
+		strings.ReplaceAll(vf.Name, "'", `\'`),
+	)
+	apl := prefix + vf.Expression + "\n| limit 0"
+
+	_, err := s.client.Datasets.Query(ctx, apl)
+	var httpErr HTTPError
+	if errors.As(err, &httpErr) && httpErr.Status == http.StatusBadRequest {
+		problems = append(problems, parseVirtualFieldProblem(httpErr.Message, utf8.RuneCountInString(prefix)))
+	} else if err != nil {
+		return nil, spanError(span, err)
+	}
+
+	return problems, nil
+}
+
+// parseVirtualFieldProblem parses the given APL error message into a
+// [VirtualFieldProblem]. Positions on the first line are made relative to the
+// expression by subtracting the length of the query prefix.
+func parseVirtualFieldProblem(msg string, prefixLen int) VirtualFieldProblem {
+	problem := VirtualFieldProblem{Message: msg}
+
+	m := problemPositionRe.FindStringSubmatch(msg)
+	if m == nil {
+		return problem
+	}
+
+	line, _ := strconv.Atoi(m[1])
+	column, _ := strconv.Atoi(m[2])
+	if line == 1 {
+		column -= prefixLen
+	}
+	if line > 0 && column > 0 {
+		problem.Line, problem.Column = line, column
+	}
+
+	return problem
+}
@@ -0,0 +1,257 @@
+package axiom
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVirtualFieldsService_List(t *testing.T) {
+	exp := []*VirtualField{
+		{
+			ID:         "test",
+			Dataset:    "logs",
+			Name:       "status_class",
+			Expression: "toint(status / 100)",
+		},
+	}
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+		assert.Equal(t, "logs", r.URL.Query().Get("dataset"))
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `[
+			{
+				"id": "test",
+				"dataset": "logs",
+				"name": "status_class",
+				"expression": "toint(status / 100)"
+			}
+		]`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/vfields", hf)
+
+	res, err := client.VirtualFields.List(context.Background(), "logs")
+	require.NoError(t, err)
+
+	assert.Equal(t, exp, res)
+}
+
+func TestVirtualFieldsService_Get(t *testing.T) {
+	exp := &VirtualField{
+		ID:          "test",
+		Dataset:     "logs",
+		Name:        "status_class",
+		Description: "Class of the HTTP status",
+		Expression:  "toint(status / 100)",
+	}
+
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodGet, r.Method)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `{
+			"id": "test",
+			"dataset": "logs",
+			"name": "status_class",
+			"description": "Class of the HTTP status",
+			"expression": "toint(status / 100)"
+		}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/vfields/test", hf)
+
+	res, err := client.VirtualFields.Get(context.Background(), "test")
+	require.NoError(t, err)
+
+	assert.Equal(t, exp, res)
+}
+
+func TestVirtualFieldsService_Create(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, mediaTypeJSON, r.Header.Get("Content-Type"))
+
+		var req map[string]any
+		err := json.NewDecoder(r.Body).Decode(&req)
+		require.NoError(t, err)
+
+		assert.Equal(t, map[string]any{
+			"dataset":    "logs",
+			"name":       "status_class",
+			"expression": "toint(status / 100)",
+		}, req)
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err = fmt.Fprint(w, `{
+			"id": "test",
+			"dataset": "logs",
+			"name": "status_class",
+			"expression": "toint(status / 100)"
+		}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/vfields", hf)
+
+	res, err := client.VirtualFields.Create(context.Background(), VirtualField{
+		ID:         "ignored",
+		Dataset:    "logs",
+		Name:       "status_class",
+		Expression: "toint(status / 100)",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "test", res.ID)
+}
+
+func TestVirtualFieldsService_Update(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPut, r.Method)
+		assert.Equal(t, mediaTypeJSON, r.Header.Get("Content-Type"))
+
+		w.Header().Set("Content-Type", mediaTypeJSON)
+		_, err := fmt.Fprint(w, `{
+			"id": "test",
+			"dataset": "logs",
+			"name": "status_class",
+			"expression": "status / 100"
+		}`)
+		assert.NoError(t, err)
+	}
+
+	client := setup(t, "/v1/vfields/test", hf)
+
+	res, err := client.VirtualFields.Update(context.Background(), "test", VirtualField{
+		Dataset:    "logs",
+		Name:       "status_class",
+		Expression: "status / 100",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "status / 100", res.Expression)
+}
+
+func TestVirtualFieldsService_Delete(t *testing.T) {
+	hf := func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodDelete, r.Method)
+
+		w.WriteHeader(http.StatusNoContent)
+	}
+
+	client := setup(t, "/v1/vfields/test", hf)
+
+	err := client.VirtualFields.Delete(context.Background(), "test")
+	require.NoError(t, err)
+}
+
+func TestVirtualFieldsService_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		vf      VirtualField
+		status  int
+		message string
+		exp     []VirtualFieldProblem
+	}{
+		{
+			name: "valid",
+			vf: VirtualField{
+				Dataset:    "logs",
+				Name:       "status_class",
+				Expression: "toint(status / 100)",
+			},
+			status: http.StatusOK,
+			exp:    []VirtualFieldProblem{},
+		},
+		{
+			name: "syntax error with position",
+			vf: VirtualField{
+				Dataset:    "logs",
+				Name:       "status_class",
+				Expression: "toint(status / )",
+			},
+			status:  http.StatusBadRequest,
+			message: "syntax error at line 1, column 52: unexpected ')'",
+			exp: []VirtualFieldProblem{
+				{
+					Message: "syntax error at line 1, column 52: unexpected ')'",
+					Line:    1,
+					Column:  15,
+				},
+			},
+		},
+		{
+			name: "error without position",
+			vf: VirtualField{
+				Dataset:    "logs",
+				Name:       "status_class",
+				Expression: "unknown(status)",
+			},
+			status:  http.StatusBadRequest,
+			message: "unknown function 'unknown'",
+			exp: []VirtualFieldProblem{
+				{Message: "unknown function 'unknown'"},
+			},
+		},
+		{
+			name: "missing fields",
+			vf:   VirtualField{},
+			exp: []VirtualFieldProblem{
+				{Message: "dataset is required"},
+				{Message: "name is required"},
+				{Message: "expression is required"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			hf := func(w http.ResponseWriter, r *http.Request) {
+				if tt.status == 0 {
+					t.Error("invalid virtual field should not be sent to the server")
+				}
+
+				assert.Equal(t, http.MethodPost, r.Method)
+
+				var req aplQueryRequest
+				err := json.NewDecoder(r.Body).Decode(&req)
+				require.NoError(t, err)
+
+				assert.Equal(t, "['logs'] | extend ['status_class'] = "+tt.vf.Expression+"\n| limit 0", req.APL)
+
+				w.Header().Set("Content-Type", mediaTypeJSON)
+				w.WriteHeader(tt.status)
+				if tt.status == http.StatusOK {
+					_, err = fmt.Fprint(w, actQueryResp)
+				} else {
+					err = json.NewEncoder(w).Encode(map[string]string{"message": tt.message})
+				}
+				assert.NoError(t, err)
+			}
+
+			client := setup(t, "/v1/datasets/_apl", hf)
+
+			res, err := client.VirtualFields.Validate(context.Background(), tt.vf)
+			require.NoError(t, err)
+
+			assert.Equal(t, tt.exp, res)
+		})
+	}
+}
+
+func TestVirtualFieldProblem_String(t *testing.T) {
+	assert.Equal(t, "unknown function", VirtualFieldProblem{Message: "unknown function"}.String())
+	assert.Equal(t, "1:15: unexpected ')'", VirtualFieldProblem{
+		Message: "unexpected ')'",
+		Line:    1,
+		Column:  15,
+	}.String())
+}
@@ -13,3 +13,6 @@ var ErrMissingOrganizationID = errors.New("missing organization id")
 
 // ErrInvalidToken is returned when the token is invalid.
 var ErrInvalidToken = errors.New("invalid token")
+
+// ErrInvalidOrganizationID is returned when the organization ID is malformed.
+var ErrInvalidOrganizationID = errors.New("invalid organization id")
@@ -1,6 +1,14 @@
 package config
 
-import "net/url"
+import (
+	"fmt"
+	"net/url"
+	"regexp"
+)
+
+// organizationIDRe matches valid organization IDs. It is deliberately lenient
+// to allow for the naming of self-hosted deployments.
+var organizationIDRe = regexp.MustCompile(`^[a-zA-Z0-9][a-zA-Z0-9._-]{0,127}$`)
 
 // An Option modifies the configuration.
 type Option func(config *Config) error
@@ -35,7 +43,25 @@ func SetToken(token string) Option {
 // SetOrganizationID specifies the organization ID to use.
 func SetOrganizationID(organizationID string) Option {
 	return func(config *Config) error {
+		if err := ValidateOrganizationID(organizationID); err != nil {
+			return err
+		}
+
 		config.SetOrganizationID(organizationID)
 		return nil
 	}
 }
+
+// ValidateOrganizationID returns a descriptive [ErrInvalidOrganizationID] if the
+// given organization ID is malformed. An empty organization ID is valid.
+func ValidateOrganizationID(organizationID string) error {
+	if organizationID == "" {
+		return nil
+	} else if IsValidToken(organizationID) {
+		return fmt.Errorf("%w: got a token instead", ErrInvalidOrganizationID)
+	} else if !organizationIDRe.MatchString(organizationID) {
+		return fmt.Errorf("%w %q: must start with a letter or digit, only contain letters, digits, '.', '_' or '-' and be at most 128 characters long",
+			ErrInvalidOrganizationID, organizationID)
+	}
+	return nil
+}
@@ -72,7 +72,7 @@ func IntegrationTest(t *testing.T, adapterName string, testFunc IntegrationTestF
 	t.Logf("using account %q", testUser.Name)
 
 	// Create the dataset to use.
-	dataset, err := client.Datasets.Create(ctx, axiom.DatasetCreateRequest{
+	dataset, _, err := client.Datasets.EnsureExists(ctx, axiom.DatasetCreateRequest{
 		Name:        fmt.Sprintf("test-axiom-go-adapter-%s-%s", adapterName, datasetSuffix),
 		Description: "This is a test dataset for adapter integration tests.",
 	})
@@ -1,6 +1,14 @@
 package version
 
-import "runtime/debug"
+import (
+	"fmt"
+	"runtime"
+	"runtime/debug"
+)
+
+// fallback is the version reported by [UserAgent] if the module version can't
+// be read from the build information.
+const fallback = "devel"
 
 var version string
 
@@ -19,3 +27,14 @@ func init() {
 func Get() string {
 	return version
 }
+
+// UserAgent returns the default user agent of the axiom-go module, carrying
+// the module version, the Go version and the platform, e.g.
+// "axiom-go/v0.17.0 (go/go1.21.0; linux/amd64)".
+func UserAgent() string {
+	v := version
+	if v == "" {
+		v = fallback
+	}
+	return fmt.Sprintf("axiom-go/%s (go/%s; %s/%s)", v, runtime.Version(), runtime.GOOS, runtime.GOARCH)
+}